@@ -60,6 +60,7 @@ func init() {
 	rootCmd.AddCommand(commands.VarInitCmd)
 	rootCmd.AddCommand(commands.VarDepsCmd)
 	rootCmd.AddCommand(commands.VarGraphCmd)
+	rootCmd.AddCommand(commands.VarRefsCmd)
 	rootCmd.AddCommand(commands.VarDoctorCmd)
 	rootCmd.AddCommand(commands.VarPlaybookCmd)
 	rootCmd.AddCommand(commands.VarAuthCmd)
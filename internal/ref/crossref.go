@@ -0,0 +1,332 @@
+package ref
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/deps"
+)
+
+// CrossReference is an @alias/path#heading reference to an artifact in a
+// linked dependency.
+type CrossReference struct {
+	File   string // Spec file containing the reference, relative to the project root
+	Line   int    // 1-based line number
+	Column int    // 1-based column of the '@'
+	Raw    string // The reference as written, e.g. @platform/001-auth/spec.md#requirements
+	Alias  string // Dependency alias
+	Path   string // Path within the dependency's artifact_path
+	Anchor string // Heading anchor without '#', empty if none
+}
+
+// Severity of a cross-reference problem.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// CrossReferenceError describes a broken cross-spec reference.
+type CrossReferenceError struct {
+	Reference CrossReference
+	Severity  Severity
+	Message   string
+}
+
+// Error returns the error message prefixed with its location
+func (e *CrossReferenceError) Error() string {
+	return fmt.Sprintf("%s:%d: %s: %s", e.Reference.File, e.Reference.Line, e.Reference.Raw, e.Message)
+}
+
+// DependencySource tells the resolver where a dependency's artifacts are
+// available locally.
+type DependencySource struct {
+	Alias string
+	// Dir is the directory holding the dependency's artifact_path contents
+	// (a cache checkout, a local source or a vendored copy).
+	Dir string
+	// Commit is the commit the artifacts were checked out at, for messages.
+	Commit string
+	// Err, if set, explains why the dependency cannot be used (not cached,
+	// cache not at the pinned commit, ...). References to it are reported
+	// with this message.
+	Err error
+}
+
+// ProjectReport is the result of validating every spec in a project.
+type ProjectReport struct {
+	Files      int
+	References int
+	Errors     []CrossReferenceError
+}
+
+// ErrorCount returns the number of problems with error severity.
+func (r *ProjectReport) ErrorCount() int {
+	n := 0
+	for _, e := range r.Errors {
+		if e.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+// crossRefPattern matches @alias/path[#anchor]. The '@' must start the line
+// or follow whitespace or an opening bracket so e-mail addresses are ignored.
+var crossRefPattern = regexp.MustCompile("(^|[\\s(\\[`\"'])@([A-Za-z0-9][A-Za-z0-9_.-]*)/([^\\s)\\]#`\"']+)(?:#([A-Za-z0-9_-]+))?")
+
+// ExtractCrossReferences returns every @alias/path#heading reference in
+// content with its position. References inside fenced code blocks are skipped.
+func ExtractCrossReferences(content string) []CrossReference {
+	var refs []CrossReference
+	inFence := false
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(text), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		for _, m := range crossRefPattern.FindAllStringSubmatchIndex(text, -1) {
+			start := m[4] - 1 // position of '@'
+			path := strings.TrimRight(text[m[6]:m[7]], ".,;:")
+			ref := CrossReference{
+				Line:   line,
+				Column: start + 1,
+				Alias:  text[m[4]:m[5]],
+				Path:   path,
+			}
+			raw := "@" + ref.Alias + "/" + path
+			if m[8] >= 0 {
+				ref.Anchor = text[m[8]:m[9]]
+				raw += "#" + ref.Anchor
+			}
+			ref.Raw = raw
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
+
+// HeadingAnchors returns the anchors generated for the markdown headings in
+// content, using GitHub's slug rules. Duplicate headings get -1, -2, ...
+// suffixes the same way GitHub renders them.
+func HeadingAnchors(content string) map[string]bool {
+	anchors := make(map[string]bool)
+	counts := make(map[string]int)
+	inFence := false
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		heading := strings.TrimLeft(trimmed, "#")
+		if heading == trimmed || (heading != "" && heading[0] != ' ') {
+			continue // Not a heading, e.g. "#tag"
+		}
+
+		slug := Slugify(heading)
+		if n := counts[slug]; n > 0 {
+			anchors[fmt.Sprintf("%s-%d", slug, n)] = true
+		} else {
+			anchors[slug] = true
+		}
+		counts[slug]++
+	}
+
+	return anchors
+}
+
+// Slugify converts heading text into a GitHub-style anchor: lower case,
+// punctuation removed, spaces replaced by hyphens.
+func Slugify(heading string) string {
+	heading = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(heading), "#"))
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ':
+			b.WriteRune('-')
+		case r == '-' || r == '_':
+			b.WriteRune(r)
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r > 127:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// SetSources sets where each dependency alias can be read from for
+// cross-spec validation
+func (r *ReferenceResolver) SetSources(sources map[string]DependencySource) {
+	r.sources = sources
+}
+
+// ValidateProject walks every markdown file under artifactPath (skipping the
+// deps/ and vendor/ directories, which hold dependency content) and checks
+// each @alias/path#heading reference against the sources set with SetSources.
+func (r *ReferenceResolver) ValidateProject(projectRoot, artifactPath string) (*ProjectReport, error) {
+	root := filepath.Join(projectRoot, artifactPath)
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && (d.Name() == "deps" || d.Name() == deps.DefaultVendorDir || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), ".md") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+	sort.Strings(files)
+
+	report := &ProjectReport{}
+	for _, file := range files {
+		rel, err := filepath.Rel(projectRoot, file)
+		if err != nil {
+			rel = file
+		}
+		fileReport, err := r.ValidateFile(projectRoot, artifactPath, rel)
+		if err != nil {
+			return nil, err
+		}
+		report.Files++
+		report.References += fileReport.References
+		report.Errors = append(report.Errors, fileReport.Errors...)
+	}
+
+	return report, nil
+}
+
+// ValidateFile checks the cross-spec references of a single file, given
+// relative to projectRoot.
+func (r *ReferenceResolver) ValidateFile(projectRoot, artifactPath, file string) (*ProjectReport, error) {
+	content, err := os.ReadFile(filepath.Join(projectRoot, file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	refs := ExtractCrossReferences(string(content))
+	report := &ProjectReport{Files: 1, References: len(refs)}
+	anchorCache := make(map[string]map[string]bool)
+	for _, ref := range refs {
+		ref.File = file
+		if e := r.validateCrossReference(projectRoot, artifactPath, ref, anchorCache); e != nil {
+			report.Errors = append(report.Errors, *e)
+		}
+	}
+	return report, nil
+}
+
+// validateCrossReference resolves a single reference and checks its anchor.
+func (r *ReferenceResolver) validateCrossReference(projectRoot, artifactPath string, ref CrossReference, anchorCache map[string]map[string]bool) *CrossReferenceError {
+	src, ok := r.sources[ref.Alias]
+	if !ok {
+		return &CrossReferenceError{
+			Reference: ref,
+			Severity:  SeverityWarning,
+			Message:   fmt.Sprintf("unknown dependency alias %q", ref.Alias),
+		}
+	}
+	if src.Err != nil {
+		return &CrossReferenceError{
+			Reference: ref,
+			Severity:  SeverityError,
+			Message:   fmt.Sprintf("cannot check @%s: %v", ref.Alias, src.Err),
+		}
+	}
+
+	// A reference may only name files inside the dependency, or checking it
+	// would read arbitrary files such as @dep/../../etc/passwd
+	refPath := filepath.Clean(filepath.FromSlash(ref.Path))
+	if !filepath.IsLocal(refPath) {
+		return &CrossReferenceError{
+			Reference: ref,
+			Severity:  SeverityError,
+			Message:   fmt.Sprintf("%s is outside @%s", ref.Path, ref.Alias),
+		}
+	}
+
+	_, target, err := deps.ResolveReferenceWithCache(artifactPath, ref.Alias, "", refPath, projectRoot, src.Dir)
+	if err != nil {
+		return &CrossReferenceError{
+			Reference: ref,
+			Severity:  SeverityError,
+			Message:   fmt.Sprintf("%s not found in @%s%s", ref.Path, ref.Alias, atCommit(src.Commit)),
+		}
+	}
+
+	if ref.Anchor == "" {
+		return nil
+	}
+
+	anchors, ok := anchorCache[target]
+	if !ok {
+		info, err := os.Stat(target)
+		if err != nil || info.IsDir() {
+			return &CrossReferenceError{
+				Reference: ref,
+				Severity:  SeverityError,
+				Message:   fmt.Sprintf("#%s points into a directory, not a file", ref.Anchor),
+			}
+		}
+		content, err := os.ReadFile(target)
+		if err != nil {
+			return &CrossReferenceError{
+				Reference: ref,
+				Severity:  SeverityError,
+				Message:   fmt.Sprintf("failed to read %s: %v", ref.Path, err),
+			}
+		}
+		anchors = HeadingAnchors(string(content))
+		anchorCache[target] = anchors
+	}
+
+	if !anchors[strings.ToLower(ref.Anchor)] {
+		return &CrossReferenceError{
+			Reference: ref,
+			Severity:  SeverityError,
+			Message:   fmt.Sprintf("heading #%s not found in %s%s", ref.Anchor, ref.Path, atCommit(src.Commit)),
+		}
+	}
+	return nil
+}
+
+// atCommit formats a commit suffix for messages.
+func atCommit(commit string) string {
+	if len(commit) > 8 {
+		commit = commit[:8]
+	}
+	if commit == "" {
+		return ""
+	}
+	return " at " + commit
+}
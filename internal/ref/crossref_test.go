@@ -0,0 +1,120 @@
+package ref
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractCrossReferences(t *testing.T) {
+	content := "# Spec\n" +
+		"See @platform/001-auth/spec.md#requirements for details.\n" +
+		"Contact team@example.com/foo, not a reference.\n" +
+		"```\n" +
+		"@platform/ignored.md\n" +
+		"```\n" +
+		"Also ([contract](@api/contracts/openapi.yaml)).\n"
+
+	refs := ExtractCrossReferences(content)
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 references, got %d: %+v", len(refs), refs)
+	}
+
+	first := refs[0]
+	if first.Alias != "platform" || first.Path != "001-auth/spec.md" || first.Anchor != "requirements" {
+		t.Errorf("unexpected first reference: %+v", first)
+	}
+	if first.Line != 2 || first.Column != 5 {
+		t.Errorf("expected position 2:5, got %d:%d", first.Line, first.Column)
+	}
+
+	second := refs[1]
+	if second.Raw != "@api/contracts/openapi.yaml" || second.Line != 7 {
+		t.Errorf("unexpected second reference: %+v", second)
+	}
+}
+
+func TestHeadingAnchors(t *testing.T) {
+	content := "# Auth Spec\n" +
+		"## Functional Requirements (MVP)\n" +
+		"## Notes\n" +
+		"## Notes\n" +
+		"#hashtag\n"
+
+	anchors := HeadingAnchors(content)
+	for _, want := range []string{"auth-spec", "functional-requirements-mvp", "notes", "notes-1"} {
+		if !anchors[want] {
+			t.Errorf("expected anchor %q in %v", want, anchors)
+		}
+	}
+	if anchors["hashtag"] {
+		t.Error("did not expect #hashtag to be treated as a heading")
+	}
+}
+
+func TestValidateProject(t *testing.T) {
+	projectRoot := t.TempDir()
+	depDir := t.TempDir()
+
+	writeFile(t, filepath.Join(depDir, "001-auth", "spec.md"), "# Auth\n\n## Requirements\n")
+	// A file next to the dependency, reachable only by escaping it
+	writeFile(t, filepath.Join(filepath.Dir(depDir), "secret.md"), "# Secret\n")
+	writeFile(t, filepath.Join(projectRoot, "specledger", "002-billing", "spec.md"),
+		"# Billing\n"+
+			"Uses @platform/001-auth/spec.md#requirements.\n"+
+			"Uses @platform/001-auth/spec.md#missing-heading.\n"+
+			"Uses @platform/002-gone/spec.md.\n"+
+			"Uses @unknown/spec.md.\n"+
+			"Uses @offline/spec.md.\n"+
+			"Uses @platform/../"+filepath.Base(filepath.Dir(depDir))+"/../secret.md#secret.\n"+
+			"Uses @platform/001-auth/../../secret.md.\n")
+	// Dependency content linked into deps/ must not be validated
+	writeFile(t, filepath.Join(projectRoot, "specledger", "deps", "platform", "spec.md"), "@nope/x.md\n")
+
+	resolver := NewResolver("")
+	resolver.SetSources(map[string]DependencySource{
+		"platform": {Alias: "platform", Dir: depDir, Commit: "0123456789abcdef"},
+		"offline":  {Alias: "offline", Err: errors.New("not cached")},
+	})
+
+	report, err := resolver.ValidateProject(projectRoot, "specledger")
+	if err != nil {
+		t.Fatalf("ValidateProject() error: %v", err)
+	}
+	if report.Files != 1 || report.References != 7 {
+		t.Fatalf("expected 1 file and 7 references, got %d and %d", report.Files, report.References)
+	}
+
+	wantLines := map[int]Severity{3: SeverityError, 4: SeverityError, 5: SeverityWarning, 6: SeverityError, 7: SeverityError, 8: SeverityError}
+	if len(report.Errors) != len(wantLines) {
+		t.Fatalf("expected %d problems, got %d: %+v", len(wantLines), len(report.Errors), report.Errors)
+	}
+	for _, e := range report.Errors {
+		if want, ok := wantLines[e.Reference.Line]; !ok || want != e.Severity {
+			t.Errorf("unexpected problem on line %d: %s (%s)", e.Reference.Line, e.Message, e.Severity)
+		}
+	}
+	if report.ErrorCount() != 5 {
+		t.Errorf("expected 5 errors, got %d", report.ErrorCount())
+	}
+	for _, e := range report.Errors {
+		if e.Reference.Line >= 7 && !strings.Contains(e.Message, "is outside @platform") {
+			t.Errorf("expected line %d rejected as outside the dependency, got %q", e.Reference.Line, e.Message)
+		}
+	}
+	if !strings.Contains(report.Errors[0].Message, "at 01234567") {
+		t.Errorf("expected commit in message, got %q", report.Errors[0].Message)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
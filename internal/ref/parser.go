@@ -20,6 +20,7 @@ type Reference struct {
 type ReferenceResolver struct {
 	lockfilePath string
 	dependencies map[string]string // alias -> repository URL mapping
+	sources      map[string]DependencySource
 }
 
 // NewResolver creates a new reference resolver
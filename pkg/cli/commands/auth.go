@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/specledger/specledger/pkg/cli/auth"
@@ -117,16 +118,24 @@ The token is automatically refreshed if expired.`,
 // VarAuthHookCmd represents the hook command
 var VarAuthHookCmd = &cobra.Command{
 	Use:   "hook",
-	Short: "Manage Claude Code session capture hook",
-	Long: `Check, install, or remove the session capture hook in Claude Code settings.
+	Short: "Manage the session capture hook of a coding agent",
+	Long: `Check, install, or remove the session capture hook of a coding agent.
 
-The hook is automatically installed during 'sl auth login', but you can
-use this command to manually manage it.
+The Claude Code hook is automatically installed during 'sl auth login', and
+'sl code <agent>' installs the launched agent's hook once session capture is
+enabled, but you can use this command to manually manage them.
+
+Hook locations:
+  claude          ~/.claude/settings.json (PostToolUse hook)
+  opencode        ~/.config/opencode/plugin/specledger-session.js
+  github-copilot  .github/hooks/specledger.json in the project
+  codex           notify setting in ~/.codex/config.toml
 
 Examples:
-  sl auth hook           # Check if hook is installed
-  sl auth hook --install # Install the hook
-  sl auth hook --remove  # Remove the hook`,
+  sl auth hook                         # Check if the Claude Code hook is installed
+  sl auth hook --install               # Install the Claude Code hook
+  sl auth hook --install --agent codex # Install the Codex hook
+  sl auth hook --remove                # Remove the Claude Code hook`,
 	RunE: runHook,
 }
 
@@ -142,6 +151,7 @@ func init() {
 
 	VarAuthHookCmd.Flags().Bool("install", false, "Install the session capture hook")
 	VarAuthHookCmd.Flags().Bool("remove", false, "Remove the session capture hook")
+	VarAuthHookCmd.Flags().String("agent", "claude", "Agent to manage the hook for ("+strings.Join(hooks.SessionCaptureAgents, ", ")+")")
 }
 
 func runLogin(cmd *cobra.Command, args []string) error {
@@ -436,18 +446,24 @@ func installSessionHook() {
 func runHook(cmd *cobra.Command, args []string) error {
 	install, _ := cmd.Flags().GetBool("install")
 	remove, _ := cmd.Flags().GetBool("remove")
+	agentName, _ := cmd.Flags().GetString("agent")
 
 	if install && remove {
 		return fmt.Errorf("cannot use --install and --remove together")
 	}
 
+	projectDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
 	if install {
-		installed, err := hooks.InstallSessionCaptureHook()
+		installed, location, err := hooks.InstallSessionCaptureHookFor(agentName, projectDir)
 		if err != nil {
 			return fmt.Errorf("failed to install hook: %w", err)
 		}
 		if installed {
-			fmt.Println("Session capture hook installed successfully.")
+			fmt.Printf("Session capture hook installed successfully (%s).\n", location)
 		} else {
 			fmt.Println("Session capture hook already installed.")
 		}
@@ -455,7 +471,7 @@ func runHook(cmd *cobra.Command, args []string) error {
 	}
 
 	if remove {
-		removed, err := hooks.UninstallSessionCaptureHook()
+		removed, err := hooks.UninstallSessionCaptureHookFor(agentName, projectDir)
 		if err != nil {
 			return fmt.Errorf("failed to remove hook: %w", err)
 		}
@@ -468,16 +484,16 @@ func runHook(cmd *cobra.Command, args []string) error {
 	}
 
 	// Default: check status
-	settings, err := hooks.LoadClaudeSettings()
+	installed, err := hooks.HasSessionCaptureHookFor(agentName, projectDir)
 	if err != nil {
-		return fmt.Errorf("failed to load Claude settings: %w", err)
+		return fmt.Errorf("failed to check hook: %w", err)
 	}
 
-	if hooks.HasSessionCaptureHook(settings) {
+	if installed {
 		fmt.Println("Session capture hook: installed")
 	} else {
 		fmt.Println("Session capture hook: not installed")
-		fmt.Println("\nRun 'sl auth hook --install' to install it.")
+		fmt.Printf("\nRun 'sl auth hook --install --agent %s' to install it.\n", agentName)
 	}
 
 	return nil
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/charmbracelet/huh"
 	"github.com/specledger/specledger/internal/agent"
 	"github.com/specledger/specledger/pkg/cli/config"
+	"github.com/specledger/specledger/pkg/cli/hooks"
 	"github.com/specledger/specledger/pkg/cli/launcher"
+	"github.com/specledger/specledger/pkg/cli/tui"
 	"github.com/specledger/specledger/pkg/cli/ui"
 	"github.com/spf13/cobra"
 )
@@ -110,6 +113,8 @@ func runCode(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	ensureAgentSessionHook(ag.Command)
+
 	fmt.Println(ui.Info(fmt.Sprintf("Launching %s...", ag.Name)))
 	if err := l.Launch(); err != nil {
 		return fmt.Errorf("failed to launch %s: %w", ag.Name, err)
@@ -122,3 +127,57 @@ func init() {
 	VarCodeCmd.SetOut(os.Stdout)
 	VarCodeCmd.SetErr(os.Stderr)
 }
+
+// ensureAgentSessionHook offers to install the launched agent's session
+// capture hook when session capture is enabled, i.e. the Claude Code hook
+// installed by 'sl auth login' is present. The hook is only written once the
+// user agrees; otherwise the install command is shown. Failures only warn;
+// the agent still launches.
+func ensureAgentSessionHook(agentCommand string) {
+	if agentCommand == "claude" {
+		return
+	}
+	settings, err := hooks.LoadClaudeSettings()
+	if err != nil || !hooks.HasSessionCaptureHook(settings) {
+		return
+	}
+
+	projectDir, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	if present, err := hooks.HasSessionCaptureHookFor(agentCommand, projectDir); err != nil || present {
+		return
+	}
+
+	installCmd := fmt.Sprintf("sl auth hook --install --agent %s", agentCommand)
+	install := false
+	if tui.NewModeDetector().IsInteractive() {
+		description := "Adds a hook to the agent's user config."
+		if agentCommand == "github-copilot" {
+			description = "Adds .github/hooks/specledger.json to this project."
+		}
+		err := huh.NewForm(huh.NewGroup(
+			huh.NewConfirm().
+				Title(fmt.Sprintf("Capture %s sessions?", agentCommand)).
+				Description(description).
+				Value(&install),
+		)).Run()
+		if err != nil {
+			install = false
+		}
+	}
+	if !install {
+		fmt.Println(ui.Info(fmt.Sprintf("Sessions of %s are not captured; run '%s' to enable", agentCommand, installCmd)))
+		return
+	}
+
+	installed, location, err := hooks.InstallSessionCaptureHookFor(agentCommand, projectDir)
+	if err != nil {
+		ui.PrintWarning(fmt.Sprintf("Session capture hook not installed for %s: %v", agentCommand, err))
+		return
+	}
+	if installed {
+		fmt.Println(ui.Info(fmt.Sprintf("Installed session capture hook (%s)", location)))
+	}
+}
@@ -2,9 +2,12 @@ package commands
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/specledger/specledger/pkg/cli/auth"
 	"github.com/specledger/specledger/pkg/cli/comment"
@@ -20,8 +23,16 @@ var VarCommentCmd = &cobra.Command{
 Subcommands:
   list     List review comments (compact or JSON format)
   show     Show full comment details with thread replies
+  add      Add a review comment anchored to file lines
   reply    Reply to a comment thread
-  resolve  Mark comments as resolved (--reason required)`,
+  resolve  Mark comments as resolved (--reason required)
+  sync     Push local comments to Supabase and pull remote ones
+  watch    Print new comments and replies as they arrive
+
+Offline review:
+  With --local, or when not logged in, comments are read from and written to
+  the spec's local store (specledger/<spec>/review.jsonl). Comments created
+  locally get "local-" IDs until 'sl comment sync' pushes them.`,
 	Args:         cobra.NoArgs,
 	RunE:         func(cmd *cobra.Command, args []string) error { return cmd.Help() },
 	SilenceUsage: true,
@@ -41,6 +52,10 @@ Status filters:
   --status resolved  Only resolved comments
   --status all       All comments
 
+Time filter:
+  --since 2h          Only comments created in the last two hours
+  --since 2026-01-15  Only comments created since a date
+
 Exit codes:
   0: Success (including no comments)
   1: Auth failure (silent exit for agent integration)
@@ -49,7 +64,8 @@ Examples:
   sl comment list
   sl comment list 601-cli-skills
   sl comment list --json --status open
-  sl comment list --status resolved`,
+  sl comment list --status resolved
+  sl comment list --since 1d`,
 	Args:         cobra.MaximumNArgs(1),
 	RunE:         runCommentList,
 	SilenceUsage: true,
@@ -97,18 +113,90 @@ Examples:
 	SilenceUsage: true,
 }
 
+var commentAddCmd = &cobra.Command{
+	Use:   "add <file> <message> --line N",
+	Short: "Add a review comment anchored to file lines",
+	Long: `Post a new review comment on the current branch's change, anchored to
+one line (--line) or a range of lines (--start-line to --line) of a file.
+
+The selected text defaults to the content of the anchored lines. When
+--selected-text is given it must occur within those lines.
+
+Arguments:
+  file:    Path of the commented file (e.g. specledger/010-auth/spec.md)
+  message: The comment text
+
+Output formats:
+  Default: Success message with comment ID
+  --json:  JSON object with the created comment
+
+Examples:
+  sl comment add specledger/010-auth/spec.md --line 42 "Clarify the token lifetime"
+  sl comment add spec.md --start-line 10 --line 14 "This section contradicts the plan"
+  sl comment add plan.md --line 7 --selected-text "Redis" "Why not Postgres?" --json`,
+	Args:         cobra.ExactArgs(2),
+	RunE:         runCommentAdd,
+	SilenceUsage: true,
+}
+
+var commentSyncCmd = &cobra.Command{
+	Use:   "sync [branch-name]",
+	Short: "Sync the local comment store with Supabase",
+	Long: `Push comments, replies and resolutions made in the local store
+(specledger/<spec>/review.jsonl) to Supabase, then pull the spec's remote
+comments into it. Requires authentication.
+
+Conflicts: a local resolution is not pushed when the remote comment was
+edited or got new replies since the last sync. The comment is reopened
+locally so the new activity can be reviewed; use --force to resolve anyway.
+Local replies to comments deleted remotely are dropped.
+
+Output formats:
+  Default: Summary with conflicts
+  --json:  JSON object with counts and conflicts
+
+Examples:
+  sl comment sync
+  sl comment sync 601-cli-skills
+  sl comment sync --force --json`,
+	Args:         cobra.MaximumNArgs(1),
+	RunE:         runCommentSync,
+	SilenceUsage: true,
+}
+
 var (
-	commentListJSON      bool
-	commentListStatus    string
-	commentShowJSON      bool
-	commentReplyJSON     bool
-	commentResolveJSON   bool
-	commentResolveReason string
+	commentLocal     bool
+	commentSyncForce bool
+	commentSyncJSON  bool
+
+	commentAddLine         int
+	commentAddStartLine    int
+	commentAddSelectedText string
+	commentAddJSON         bool
+	commentListJSON        bool
+	commentListStatus      string
+	commentListSince       string
+	commentShowJSON        bool
+	commentReplyJSON       bool
+	commentResolveJSON     bool
+	commentResolveReason   string
 )
 
 func init() {
+	VarCommentCmd.PersistentFlags().BoolVar(&commentLocal, "local", false, "Use the local comment store (specledger/<spec>/review.jsonl)")
+
+	commentSyncCmd.Flags().BoolVar(&commentSyncForce, "force", false, "Push local resolutions even if the comment changed remotely")
+	commentSyncCmd.Flags().BoolVar(&commentSyncJSON, "json", false, "Output as JSON")
+
 	commentListCmd.Flags().BoolVar(&commentListJSON, "json", false, "Output as JSON array")
 	commentListCmd.Flags().StringVar(&commentListStatus, "status", "open", "Filter by status: open, resolved, all")
+	commentListCmd.Flags().StringVar(&commentListSince, "since", "", "Only comments created since a date (YYYY-MM-DD) or duration (e.g. 2h, 7d)")
+
+	commentAddCmd.Flags().IntVar(&commentAddLine, "line", 0, "Line the comment is anchored to (last line of a range)")
+	commentAddCmd.Flags().IntVar(&commentAddStartLine, "start-line", 0, "First line of a multi-line range")
+	commentAddCmd.Flags().StringVar(&commentAddSelectedText, "selected-text", "", "Commented text (default: the anchored lines)")
+	commentAddCmd.Flags().BoolVar(&commentAddJSON, "json", false, "Output as JSON")
+	_ = commentAddCmd.MarkFlagRequired("line")
 
 	commentShowCmd.Flags().BoolVar(&commentShowJSON, "json", false, "Output as JSON")
 
@@ -120,24 +208,26 @@ func init() {
 
 	VarCommentCmd.AddCommand(commentListCmd)
 	VarCommentCmd.AddCommand(commentShowCmd)
+	VarCommentCmd.AddCommand(commentAddCmd)
 	VarCommentCmd.AddCommand(commentReplyCmd)
 	VarCommentCmd.AddCommand(commentResolveCmd)
+	VarCommentCmd.AddCommand(commentSyncCmd)
 }
 
 func runCommentList(cmd *cobra.Command, args []string) error {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
+	var since time.Time
+	if commentListSince != "" {
+		var err error
+		if since, err = parseSince(commentListSince, time.Now()); err != nil {
+			return err
+		}
 	}
 
-	accessToken, err := auth.GetValidAccessToken()
+	cwd, err := os.Getwd()
 	if err != nil {
-		os.Exit(1)
-		return nil
+		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	client := comment.NewClient(accessToken)
-
 	var specKey string
 	if len(args) > 0 {
 		specKey = args[0]
@@ -150,50 +240,160 @@ func runCommentList(cmd *cobra.Command, args []string) error {
 		specKey = currentBranch
 	}
 
-	repoOwner, repoName, err := cligit.GetRepoOwnerName(cwd)
+	store, err := openCommentStore(cwd, commentStoreRequest{SpecKey: specKey, WithChange: true})
+	if errors.Is(err, errNotAuthenticated) {
+		os.Exit(1)
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get repo info: %w", err)
+		return err
 	}
 
-	project, err := client.GetProject(repoOwner, repoName)
+	comments, err := fetchCommentsByStatus(store, commentListStatus)
 	if err != nil {
-		return fmt.Errorf("failed to get project: %w", err)
+		return fmt.Errorf("failed to fetch comments: %w", err)
+	}
+	if !since.IsZero() {
+		comments = filterCommentsSince(comments, since)
 	}
+	reanchorComments(cwd, comments)
 
-	spec, err := client.GetSpec(project.ID, specKey)
+	if commentListJSON {
+		return outputCommentsJSON(comments)
+	}
+
+	if err := outputCommentsCompact(comments, store); err != nil {
+		return err
+	}
+	printLocalPending(store)
+	return nil
+}
+
+// errNotAuthenticated is returned by openCommentStore when the remote store
+// is needed and there are no valid credentials.
+var errNotAuthenticated = errors.New("authentication required")
+
+// commentStoreRequest describes the comments a command works on.
+type commentStoreRequest struct {
+	SpecKey    string // spec whose comments are used; "" for the current branch
+	WithChange bool   // remote: resolve the spec's change (needed to list and add)
+	Writes     bool   // when not logged in, fall back to the local store even if it doesn't exist yet
+}
+
+// openCommentStore returns the local store with --local, or when not logged
+// in and the local store exists (or the command writes); otherwise the
+// remote store.
+func openCommentStore(cwd string, req commentStoreRequest) (comment.Store, error) {
+	if commentLocal {
+		return openLocalCommentStore(cwd, req.SpecKey)
+	}
+
+	accessToken, err := auth.GetValidAccessToken()
 	if err != nil {
-		return fmt.Errorf("failed to get spec: %w", err)
+		local, localErr := openLocalCommentStore(cwd, req.SpecKey)
+		if localErr == nil && (req.Writes || local.Exists()) {
+			fmt.Fprintf(os.Stderr, "Not logged in: using local comments in %s\n", displayPath(cwd, local.Path()))
+			return local, nil
+		}
+		return nil, fmt.Errorf("%w: %v\n\nRun 'sl auth login' to authenticate, or use --local for the local comment store.", errNotAuthenticated, err)
 	}
 
-	change, err := client.GetChange(spec.ID)
+	client := comment.NewClient(accessToken)
+	store := comment.NewRemoteStore(client, "")
+	if req.WithChange {
+		specKey := req.SpecKey
+		if specKey == "" {
+			if specKey, err = cligit.GetCurrentBranch(cwd); err != nil {
+				return nil, fmt.Errorf("failed to get current branch: %w", err)
+			}
+		}
+		if store.ChangeID, err = resolveChangeID(client, cwd, specKey); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// openLocalCommentStore returns the local comment store of a spec ("" for
+// the current branch), attributing new comments to the git user.
+func openLocalCommentStore(cwd, specKey string) (*comment.LocalStore, error) {
+	if specKey == "" {
+		currentBranch, err := cligit.GetCurrentBranch(cwd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current branch: %w", err)
+		}
+		specKey = currentBranch
+	}
+
+	root, err := cligit.GetRepoRoot(cwd)
+	if err != nil {
+		return nil, err
+	}
+
+	store := comment.NewLocalStore(filepath.Join(root, getArtifactPath()), specKey)
+	store.AuthorName, store.AuthorEmail = cligit.GetUserIdentity(cwd)
+	return store, nil
+}
+
+// printLocalPending reminds to sync when the local store has unpushed changes.
+func printLocalPending(store comment.Store) {
+	if combined, ok := store.(*comment.CombinedStore); ok {
+		store = combined.Primary
+	}
+	local, ok := store.(*comment.LocalStore)
+	if !ok {
+		return
+	}
+	if n, err := local.PendingCount(); err == nil && n > 0 {
+		fmt.Printf("%d local change(s) not synced. Run 'sl comment sync' to push them.\n", n)
+	}
+}
+
+// displayPath shows path relative to cwd when it is below it.
+func displayPath(cwd, path string) string {
+	if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// resolveChangeID looks up the change of a spec in the repo's project.
+func resolveChangeID(client *comment.Client, cwd, specKey string) (string, error) {
+	repoOwner, repoName, err := cligit.GetRepoOwnerName(cwd)
 	if err != nil {
-		return fmt.Errorf("failed to get change: %w", err)
+		return "", fmt.Errorf("failed to get repo info: %w", err)
 	}
 
-	comments, err := fetchCommentsByStatus(client, change.ID, commentListStatus)
+	project, err := client.GetProject(repoOwner, repoName)
 	if err != nil {
-		return fmt.Errorf("failed to fetch comments: %w", err)
+		return "", fmt.Errorf("failed to get project: %w", err)
 	}
 
-	if commentListJSON {
-		return outputCommentsJSON(comments)
+	spec, err := client.GetSpec(project.ID, specKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to get spec: %w", err)
 	}
 
-	return outputCommentsCompact(comments, client, change.ID)
+	change, err := client.GetChange(spec.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get change: %w", err)
+	}
+
+	return change.ID, nil
 }
 
-func fetchCommentsByStatus(client *comment.Client, changeID, status string) ([]comment.ReviewComment, error) {
+func fetchCommentsByStatus(store comment.Store, status string) ([]comment.ReviewComment, error) {
 	switch status {
 	case "open":
-		return client.FetchComments(changeID)
+		return store.FetchComments()
 	case "resolved":
-		return fetchResolvedComments(client, changeID)
+		return store.FetchResolvedComments()
 	case "all":
-		open, err := client.FetchComments(changeID)
+		open, err := store.FetchComments()
 		if err != nil {
 			return nil, err
 		}
-		resolved, err := fetchResolvedComments(client, changeID)
+		resolved, err := store.FetchResolvedComments()
 		if err != nil {
 			return nil, err
 		}
@@ -203,16 +403,13 @@ func fetchCommentsByStatus(client *comment.Client, changeID, status string) ([]c
 	}
 }
 
-func fetchResolvedComments(client *comment.Client, changeID string) ([]comment.ReviewComment, error) {
-	return client.FetchResolvedComments(changeID)
-}
-
 func outputCommentsJSON(comments []comment.ReviewComment) error {
 	type CommentOutput struct {
 		ID           string `json:"id"`
 		FilePath     string `json:"file_path"`
 		Line         *int   `json:"line"`
 		StartLine    *int   `json:"start_line"`
+		AnchorStatus string `json:"anchor_status,omitempty"`
 		Content      string `json:"content"`
 		SelectedText string `json:"selected_text"`
 		AuthorName   string `json:"author_name"`
@@ -228,6 +425,7 @@ func outputCommentsJSON(comments []comment.ReviewComment) error {
 			FilePath:     c.FilePath,
 			Line:         c.Line,
 			StartLine:    c.StartLine,
+			AnchorStatus: c.AnchorStatus,
 			Content:      c.Content,
 			SelectedText: c.SelectedText,
 			AuthorName:   c.AuthorName,
@@ -242,26 +440,22 @@ func outputCommentsJSON(comments []comment.ReviewComment) error {
 	return encoder.Encode(output)
 }
 
-func outputCommentsCompact(comments []comment.ReviewComment, client *comment.Client, changeID string) error {
+func outputCommentsCompact(comments []comment.ReviewComment, store comment.Store) error {
 	if len(comments) == 0 {
 		fmt.Printf("0 comments\n")
 		return nil
 	}
 
-	replies, _ := client.FetchReplies(changeID)
+	replies, _ := store.FetchReplies()
 	replyMap := comment.BuildReplyMap(replies)
 
 	artifacts := make(map[string]struct{})
 	for _, c := range comments {
 		artifacts[c.FilePath] = struct{}{}
 
-		lineStr := ""
-		if c.StartLine != nil && c.Line != nil {
-			lineStr = fmt.Sprintf("%d-%d", *c.StartLine, *c.Line)
-		} else if c.Line != nil {
-			lineStr = fmt.Sprintf("%d", *c.Line)
-		} else {
-			lineStr = "-"
+		lineStr := formatLineRange(c.StartLine, c.Line)
+		if c.AnchorStatus == comment.AnchorOutdated {
+			lineStr += " [outdated]"
 		}
 
 		content := c.Content
@@ -293,24 +487,30 @@ func outputCommentsCompact(comments []comment.ReviewComment, client *comment.Cli
 }
 
 func runCommentShow(cmd *cobra.Command, args []string) error {
-	accessToken, err := auth.GetValidAccessToken()
+	cwd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("authentication required: %w\n\nRun 'sl auth login' to authenticate.", err)
+		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	client := comment.NewClient(accessToken)
+	store, err := openCommentStore(cwd, commentStoreRequest{})
+	if err != nil {
+		return err
+	}
 
 	for i, commentID := range args {
 		if i > 0 {
 			fmt.Println("\n---")
 		}
 
-		c, err := client.FetchCommentByID(commentID)
+		c, err := store.FetchCommentByID(commentID)
 		if err != nil {
 			return fmt.Errorf("failed to fetch comment %s: %w", commentID, err)
 		}
+		anchored := []comment.ReviewComment{*c}
+		reanchorComments(cwd, anchored)
+		c = &anchored[0]
 
-		replies, err := client.FetchRepliesByParentID(commentID)
+		replies, err := store.FetchRepliesByParentID(commentID)
 		if err != nil {
 			return fmt.Errorf("failed to fetch replies for comment %s: %w", commentID, err)
 		}
@@ -342,6 +542,7 @@ func outputCommentJSON(c *comment.ReviewComment, replies []comment.ReviewComment
 		FilePath     string        `json:"file_path"`
 		Line         *int          `json:"line"`
 		StartLine    *int          `json:"start_line"`
+		AnchorStatus string        `json:"anchor_status,omitempty"`
 		Content      string        `json:"content"`
 		SelectedText string        `json:"selected_text"`
 		AuthorName   string        `json:"author_name"`
@@ -356,6 +557,7 @@ func outputCommentJSON(c *comment.ReviewComment, replies []comment.ReviewComment
 		FilePath:     c.FilePath,
 		Line:         c.Line,
 		StartLine:    c.StartLine,
+		AnchorStatus: c.AnchorStatus,
 		Content:      c.Content,
 		SelectedText: c.SelectedText,
 		AuthorName:   c.AuthorName,
@@ -384,12 +586,17 @@ func outputCommentHuman(c *comment.ReviewComment, replies []comment.ReviewCommen
 	fmt.Printf("File: %s", c.FilePath)
 
 	if c.StartLine != nil && c.Line != nil {
-		fmt.Printf(":%d-%d\n", *c.StartLine, *c.Line)
+		fmt.Printf(":%d-%d", *c.StartLine, *c.Line)
 	} else if c.Line != nil {
-		fmt.Printf(":%d\n", *c.Line)
-	} else {
-		fmt.Println()
+		fmt.Printf(":%d", *c.Line)
+	}
+	switch c.AnchorStatus {
+	case comment.AnchorOutdated:
+		fmt.Printf(" [outdated: commented text no longer in the file]")
+	case comment.AnchorMoved:
+		fmt.Printf(" [moved]")
 	}
+	fmt.Println()
 
 	fmt.Printf("Author: %s <%s>\n", c.AuthorName, c.AuthorEmail)
 	fmt.Printf("Status: %s\n", map[bool]string{true: "Resolved", false: "Open"}[c.IsResolved])
@@ -412,18 +619,145 @@ func outputCommentHuman(c *comment.ReviewComment, replies []comment.ReviewCommen
 	return nil
 }
 
+func runCommentAdd(cmd *cobra.Command, args []string) error {
+	filePath := args[0]
+	message := args[1]
+
+	if strings.TrimSpace(message) == "" {
+		return fmt.Errorf("comment message cannot be empty")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	relPath, err := repoRelativePath(cwd, filePath)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	selectedText := commentAddSelectedText
+	if selectedText == "" {
+		selectedText, err = comment.SelectLines(string(data), commentAddStartLine, commentAddLine)
+	} else {
+		err = comment.ValidateAnchor(string(data), selectedText, commentAddStartLine, commentAddLine)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid anchor in %s: %w", relPath, err)
+	}
+
+	store, err := openCommentStore(cwd, commentStoreRequest{WithChange: true, Writes: true})
+	if err != nil {
+		return err
+	}
+
+	nc := comment.NewComment{
+		FilePath:     relPath,
+		Content:      message,
+		SelectedText: selectedText,
+		Line:         &commentAddLine,
+	}
+	if commentAddStartLine > 0 && commentAddStartLine != commentAddLine {
+		nc.StartLine = &commentAddStartLine
+	}
+
+	created, err := store.CreateComment(nc)
+	if err != nil {
+		return fmt.Errorf("failed to post comment: %w", err)
+	}
+
+	if commentAddJSON {
+		return outputCommentJSON(created, nil)
+	}
+
+	fmt.Printf("Comment posted successfully\n")
+	fmt.Printf("Comment ID: %s\n", created.ID)
+	fmt.Printf("File: %s:%s\n", relPath, formatLineRange(nc.StartLine, nc.Line))
+
+	return nil
+}
+
+// repoRelativePath converts a path given on the command line to the
+// slash-separated, repo-root-relative form used by review comments.
+func repoRelativePath(cwd, path string) (string, error) {
+	root, err := cligit.GetRepoRoot(cwd)
+	if err != nil {
+		return "", err
+	}
+
+	abs := path
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(cwd, path)
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the repository", path)
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// reanchorComments relocates comments in the current version of their files,
+// so line numbers survive spec edits. Outside a git repo they are left as is.
+func reanchorComments(cwd string, comments []comment.ReviewComment) {
+	root, err := cligit.GetRepoRoot(cwd)
+	if err != nil {
+		return
+	}
+	comment.ReanchorComments(root, comments)
+}
+
+// filterCommentsSince keeps the comments created at or after since.
+// Comments with an unparseable timestamp are kept.
+func filterCommentsSince(comments []comment.ReviewComment, since time.Time) []comment.ReviewComment {
+	filtered := make([]comment.ReviewComment, 0, len(comments))
+	for _, c := range comments {
+		created, err := time.Parse(time.RFC3339Nano, c.CreatedAt)
+		if err == nil && created.Before(since) {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered
+}
+
+func formatLineRange(startLine, line *int) string {
+	if startLine != nil && line != nil {
+		return fmt.Sprintf("%d-%d", *startLine, *line)
+	} else if line != nil {
+		return fmt.Sprintf("%d", *line)
+	}
+	return "-"
+}
+
 func runCommentReply(cmd *cobra.Command, args []string) error {
 	commentID := args[0]
 	message := args[1]
 
-	accessToken, err := auth.GetValidAccessToken()
+	cwd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("authentication required: %w\n\nRun 'sl auth login' to authenticate.", err)
+		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	client := comment.NewClient(accessToken)
+	store, err := openCommentStore(cwd, commentStoreRequest{Writes: true})
+	if err != nil {
+		return err
+	}
 
-	reply, err := client.CreateReply(commentID, message)
+	reply, err := store.CreateReply(commentID, message)
 	if err != nil {
 		return fmt.Errorf("failed to post reply: %w", err)
 	}
@@ -476,22 +810,25 @@ Examples:
 }
 
 func runCommentResolve(cmd *cobra.Command, args []string) error {
-	accessToken, err := auth.GetValidAccessToken()
+	cwd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("authentication required: %w\n\nRun 'sl auth login' to authenticate.", err)
+		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	client := comment.NewClient(accessToken)
+	store, err := openCommentStore(cwd, commentStoreRequest{Writes: true})
+	if err != nil {
+		return err
+	}
 
 	resolvedIDs := make([]string, 0, len(args))
 
 	for _, commentID := range args {
 		// Post reason as a reply before resolving (audit trail)
-		if _, err := client.CreateReply(commentID, commentResolveReason); err != nil {
+		if _, err := store.CreateReply(commentID, commentResolveReason); err != nil {
 			return fmt.Errorf("failed to post resolution reason for %s: %w\n→ The comment was NOT resolved. Fix the reply issue first.", commentID, err)
 		}
 
-		replies, err := client.FetchRepliesByParentID(commentID)
+		replies, err := store.FetchRepliesByParentID(commentID)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to fetch replies for %s: %v\n", commentID, err)
 		}
@@ -502,7 +839,7 @@ func runCommentResolve(cmd *cobra.Command, args []string) error {
 				replyIDs = append(replyIDs, r.ID)
 			}
 
-			if err := client.ResolveCommentWithReplies(commentID, replyIDs); err != nil {
+			if err := store.ResolveCommentWithReplies(commentID, replyIDs); err != nil {
 				return fmt.Errorf("failed to resolve comment %s with replies: %w", commentID, err)
 			}
 
@@ -511,7 +848,7 @@ func runCommentResolve(cmd *cobra.Command, args []string) error {
 			allIDs = append(allIDs, replyIDs...)
 			resolvedIDs = append(resolvedIDs, allIDs...)
 		} else {
-			if err := client.ResolveComment(commentID); err != nil {
+			if err := store.ResolveComment(commentID); err != nil {
 				return fmt.Errorf("failed to resolve comment %s: %w", commentID, err)
 			}
 			resolvedIDs = append(resolvedIDs, commentID)
@@ -539,3 +876,59 @@ func runCommentResolve(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runCommentSync(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	var specKey string
+	if len(args) > 0 {
+		specKey = args[0]
+	}
+
+	local, err := openLocalCommentStore(cwd, specKey)
+	if err != nil {
+		return err
+	}
+
+	accessToken, err := auth.GetValidAccessToken()
+	if err != nil {
+		return fmt.Errorf("authentication required: %w\n\nRun 'sl auth login' to authenticate.", err)
+	}
+
+	client := comment.NewClient(accessToken)
+	if specKey == "" {
+		if specKey, err = cligit.GetCurrentBranch(cwd); err != nil {
+			return fmt.Errorf("failed to get current branch: %w", err)
+		}
+	}
+	changeID, err := resolveChangeID(client, cwd, specKey)
+	if err != nil {
+		return err
+	}
+
+	result, err := comment.Sync(local, comment.NewRemoteStore(client, changeID), comment.SyncOptions{Force: commentSyncForce})
+	if err != nil {
+		return fmt.Errorf("sync failed (progress so far is saved): %w", err)
+	}
+
+	if commentSyncJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	fmt.Printf("Synced %s: %d pushed, %d resolved, %d pulled, %d removed\n",
+		displayPath(cwd, local.Path()), result.Pushed, result.Resolved, result.Pulled, result.Removed)
+	if len(result.Conflicts) > 0 {
+		fmt.Printf("\n%d conflict(s):\n", len(result.Conflicts))
+		for _, c := range result.Conflicts {
+			fmt.Printf("  - %s (%s): %s\n", c.CommentID, c.FilePath, c.Reason)
+		}
+		fmt.Println("\nReopened comments need review; resolve them again (or re-run with --force).")
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/specledger/specledger/pkg/cli/comment"
+	cligit "github.com/specledger/specledger/pkg/cli/git"
+	"github.com/spf13/cobra"
+)
+
+// defaultWatchInterval is how often 'sl comment watch' polls
+const defaultWatchInterval = 30 * time.Second
+
+var commentWatchCmd = &cobra.Command{
+	Use:   "watch [branch-name]",
+	Short: "Print new comments and replies as they arrive",
+	Long: `Poll the change of a branch (default: current) for new review comments and
+thread replies, and print each one as it arrives.
+
+Polls are cheap: the server answers "not modified" when nothing changed, and
+only activity newer than the last comment seen is fetched. By default only
+activity from now on is shown; --since also shows earlier activity first.
+Stops on Ctrl-C.
+
+Examples:
+  sl comment watch
+  sl comment watch --notify              # Also show desktop notifications
+  sl comment watch --since 2h            # Start with the last two hours
+  sl comment watch --interval 1m --json  # One JSON object per line`,
+	Args:         cobra.MaximumNArgs(1),
+	RunE:         runCommentWatch,
+	SilenceUsage: true,
+}
+
+var (
+	commentWatchInterval time.Duration
+	commentWatchNotify   bool
+	commentWatchSince    string
+	commentWatchOnce     bool
+	commentWatchJSON     bool
+)
+
+func init() {
+	commentWatchCmd.Flags().DurationVar(&commentWatchInterval, "interval", defaultWatchInterval, "How often to poll")
+	commentWatchCmd.Flags().BoolVar(&commentWatchNotify, "notify", false, "Show a desktop notification for each new comment")
+	commentWatchCmd.Flags().StringVar(&commentWatchSince, "since", "", "Also show activity since a date (YYYY-MM-DD) or duration (e.g. 2h, 7d)")
+	commentWatchCmd.Flags().BoolVar(&commentWatchOnce, "once", false, "Poll once and exit")
+	commentWatchCmd.Flags().BoolVar(&commentWatchJSON, "json", false, "Output one JSON object per comment")
+
+	VarCommentCmd.AddCommand(commentWatchCmd)
+}
+
+func runCommentWatch(cmd *cobra.Command, args []string) error {
+	if commentWatchInterval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	var specKey string
+	if len(args) > 0 {
+		specKey = args[0]
+	} else {
+		specKey, err = cligit.GetCurrentBranch(cwd)
+		if err != nil {
+			return fmt.Errorf("failed to detect current branch: %w", err)
+		}
+	}
+
+	store, err := openCommentStore(cwd, commentStoreRequest{SpecKey: specKey, WithChange: true})
+	if errors.Is(err, errNotAuthenticated) {
+		return fmt.Errorf("authentication required: run 'sl auth login', or use --local")
+	}
+	if err != nil {
+		return err
+	}
+	source, ok := store.(comment.ActivitySource)
+	if !ok {
+		return fmt.Errorf("this comment store can't be watched")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var watcher *comment.Watcher
+	if commentWatchSince != "" {
+		since, err := parseSince(commentWatchSince, time.Now())
+		if err != nil {
+			return err
+		}
+		watcher = comment.NewWatcher(source, since.UTC().Format(time.RFC3339))
+	} else {
+		// Skip existing activity; the cursor comes from the server's clock
+		watcher = comment.NewWatcher(source, "")
+		if _, err := watcher.Poll(); err != nil {
+			return fmt.Errorf("failed to fetch comments: %w", err)
+		}
+	}
+
+	if !commentWatchJSON && !commentWatchOnce {
+		fmt.Fprintf(os.Stderr, "Watching %s for new comments (every %s, Ctrl-C to stop)\n", specKey, commentWatchInterval)
+	}
+
+	for {
+		activity, err := watcher.Poll()
+		if err != nil {
+			if commentWatchOnce {
+				return fmt.Errorf("failed to fetch comments: %w", err)
+			}
+			// Keep watching through network errors
+			fmt.Fprintf(os.Stderr, "warning: %v\n", networkHint(err))
+		}
+		for _, c := range activity {
+			if err := printWatchActivity(c); err != nil {
+				return err
+			}
+			if commentWatchNotify {
+				notifyDesktop(watchTitle(c), c.Content)
+			}
+		}
+
+		if commentWatchOnce {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(commentWatchInterval):
+		}
+	}
+}
+
+func printWatchActivity(c comment.ReviewComment) error {
+	if commentWatchJSON {
+		type ActivityOutput struct {
+			Type            string `json:"type"`
+			ID              string `json:"id"`
+			ParentCommentID string `json:"parent_comment_id,omitempty"`
+			FilePath        string `json:"file_path"`
+			Line            *int   `json:"line,omitempty"`
+			StartLine       *int   `json:"start_line,omitempty"`
+			Content         string `json:"content"`
+			AuthorName      string `json:"author_name"`
+			AuthorEmail     string `json:"author_email"`
+			CreatedAt       string `json:"created_at"`
+		}
+		kind := "comment"
+		if c.ParentCommentID != "" {
+			kind = "reply"
+		}
+		return json.NewEncoder(os.Stdout).Encode(ActivityOutput{
+			Type:            kind,
+			ID:              c.ID,
+			ParentCommentID: c.ParentCommentID,
+			FilePath:        c.FilePath,
+			Line:            c.Line,
+			StartLine:       c.StartLine,
+			Content:         c.Content,
+			AuthorName:      c.AuthorName,
+			AuthorEmail:     c.AuthorEmail,
+			CreatedAt:       c.CreatedAt,
+		})
+	}
+
+	content := truncateRunes(strings.ReplaceAll(c.Content, "\n", " "), 100)
+	when := c.CreatedAt
+	if t, err := time.Parse(time.RFC3339Nano, c.CreatedAt); err == nil {
+		when = t.Local().Format("2006-01-02 15:04")
+	}
+
+	if c.ParentCommentID != "" {
+		fmt.Printf("%s  %s  reply on %s | %s\n", when, watchTitle(c), c.ParentCommentID, content)
+	} else {
+		fmt.Printf("%s  %s  %s | %s:%s | %s\n", when, watchTitle(c), c.ID, c.FilePath, formatLineRange(c.StartLine, c.Line), content)
+	}
+	return nil
+}
+
+func watchTitle(c comment.ReviewComment) string {
+	author := c.AuthorName
+	if author == "" {
+		author = c.AuthorEmail
+	}
+	if c.ParentCommentID != "" {
+		return author + " replied"
+	}
+	return author + " commented"
+}
+
+// notifyDesktop shows a desktop notification with notify-send (Linux) or
+// osascript (macOS); failures are ignored.
+func notifyDesktop(title, body string) {
+	body = truncateRunes(body, 200)
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		cmd = exec.Command("notify-send", "--app-name=sl", title, body)
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", body, title)
+		cmd = exec.Command("osascript", "-e", script)
+	default:
+		return
+	}
+	_ = cmd.Run()
+}
+
+// truncateRunes shortens s to at most max characters, ending with "...",
+// without splitting multi-byte characters.
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
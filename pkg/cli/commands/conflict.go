@@ -1,212 +1,121 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
-	"strings"
 
-	"github.com/specledger/specledger/internal/spec"
-	"github.com/specledger/specledger/pkg/models"
+	cligit "github.com/specledger/specledger/pkg/cli/git"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/deps"
 	"github.com/spf13/cobra"
 )
 
-// VarConflictCmd represents the conflict command
-var VarConflictCmd = &cobra.Command{
-	Use:   "conflict",
-	Short: "Check for dependency conflicts",
-	Long:  `Check the dependency graph for potential conflicts and circular dependencies.`,
-}
-
-// VarCheckCmd represents the check command
-var VarCheckCmd = &cobra.Command{
+// VarDepsCheckCmd represents the deps check command
+var VarDepsCheckCmd = &cobra.Command{
 	Use:   "check",
-	Short: "Check for conflicts in the dependency graph",
-	RunE:  runCheckConflicts,
+	Short: "Check the dependency graph for conflicts",
+	Long: `Check the dependencies declared in specledger.yaml for conflicts:
+
+  - duplicates:  the same repository or alias declared more than once
+  - cycles:      a dependency chain that leads back to an earlier node
+  - branches:    the same repository required at different branches
+
+Transitive dependencies are read from the specledger.yaml of each cached or
+local dependency. Nothing is cloned; run 'sl deps resolve' first to include
+upstreams that are not cached yet.
+
+Exits with a non-zero status when conflicts are found, so it can run in CI.`,
+	Example: `  sl deps check
+  sl deps check --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runCheckConflicts,
 }
 
-// VarDetectCmd represents the detect command
-var VarDetectCmd = &cobra.Command{
-	Use:   "detect",
-	Short: "Detect potential conflicts",
-	RunE:  runDetectConflicts,
+func init() {
+	VarDepsCheckCmd.Flags().Bool("json", false, "Output conflicts as JSON")
 }
 
-func init() {
-	VarConflictCmd.AddCommand(VarCheckCmd, VarDetectCmd)
+// conflictJSON is the JSON representation of a dependency conflict.
+type conflictJSON struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
 }
 
 func runCheckConflicts(cmd *cobra.Command, args []string) error {
-	manifestPath := "github.com/specledger/specledger/spec.mod"
+	jsonOutput, _ := cmd.Flags().GetBool("json")
 
-	// Read manifest
-	manifest, err := spec.ParseManifest(manifestPath)
+	projectDir, err := findProjectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to read manifest: %w", err)
+		return fmt.Errorf("failed to find project root: %w", err)
 	}
 
-	if len(manifest.Dependecies) == 0 {
-		fmt.Println("No dependencies found")
-		return nil
+	meta, err := metadata.LoadFromProject(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
 	}
 
-	fmt.Printf("Checking %d dependency(ies) for conflicts...\n\n", len(manifest.Dependecies))
+	// The project's own URL lets us detect upstreams that depend back on us.
+	rootURL, _ := cligit.GetOriginURL(projectDir)
 
-	// Check for duplicate dependencies
-	duplicates := checkDuplicateDependencies(manifest.Dependecies)
-	if len(duplicates) > 0 {
-		fmt.Println("⚠️  Duplicates found:")
-		for _, dup := range duplicates {
-			fmt.Printf("  - %s\n", dup)
-		}
-		fmt.Println()
-	}
+	loader := deps.SourceUpstreamLoader(projectDir, loadDependencyReplacements(projectDir))
+	conflicts := deps.CheckConflicts(rootURL, meta.Dependencies, loader)
 
-	// Check for circular dependencies
-	circular := checkCircularDependencies(manifest.Dependecies)
-	if len(circular) > 0 {
-		fmt.Println("⚠️  Circular dependencies detected:")
-		for _, cycle := range circular {
-			fmt.Printf("  - %s\n", cycle)
+	if jsonOutput {
+		out := make([]conflictJSON, 0, len(conflicts))
+		for _, c := range conflicts {
+			out = append(out, conflictJSON{Kind: string(c.Kind), Message: c.Message})
 		}
-		fmt.Println()
-	}
-
-	// Check for version conflicts
-	conflicts := checkVersionConflicts(manifest.Dependecies)
-	if len(conflicts) > 0 {
-		fmt.Println("⚠️  Version conflicts found:")
-		for _, conflict := range conflicts {
-			fmt.Printf("  - %s\n", conflict)
-		}
-		fmt.Println()
-	}
-
-	// Check for missing spec paths
-	missing := checkMissingSpecPaths(manifest.Dependecies)
-	if len(missing) > 0 {
-		fmt.Println("⚠️  Missing or invalid spec paths:")
-		for _, specPath := range missing {
-			fmt.Printf("  - %s\n", specPath)
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
 		}
-		fmt.Println()
-	}
-
-	if len(duplicates) == 0 && len(circular) == 0 && len(conflicts) == 0 && len(missing) == 0 {
-		fmt.Println("✓ No conflicts detected!")
-		return nil
-	}
-
-	return fmt.Errorf("%d conflict(s) detected", len(duplicates)+len(circular)+len(conflicts)+len(missing))
-}
-
-func runDetectConflicts(cmd *cobra.Command, args []string) error {
-	manifestPath := "github.com/specledger/specledger/spec.mod"
-
-	// Read manifest
-	manifest, err := spec.ParseManifest(manifestPath)
-	if err != nil {
-		return fmt.Errorf("failed to read manifest: %w", err)
-	}
-
-	if len(manifest.Dependecies) == 0 {
-		fmt.Println("No dependencies to check")
-		return nil
-	}
-
-	// Build a dependency graph
-	graph := buildDependencyGraph(manifest.Dependecies)
-
-	// Detect potential conflicts
-	issues := detectPotentialConflicts(graph)
-
-	if len(issues) == 0 {
-		fmt.Println("No potential conflicts detected")
-		return nil
-	}
-
-	fmt.Println("Potential conflicts detected:")
-	for _, issue := range issues {
-		fmt.Printf("  - %s\n", issue)
+		fmt.Println(string(data))
+	} else {
+		printConflicts(len(meta.Dependencies), conflicts)
 	}
 
-	return fmt.Errorf("%d potential conflict(s) detected", len(issues))
-}
-
-// checkDuplicateDependencies checks for duplicate dependencies
-func checkDuplicateDependencies(deps []models.Dependency) []string {
-	var duplicates []string
-	seen := make(map[string]bool)
-
-	for _, dep := range deps {
-		key := fmt.Sprintf("%s:%s", dep.RepositoryURL, dep.SpecPath)
-		if seen[key] {
-			duplicates = append(duplicates, key)
-		} else {
-			seen[key] = true
-		}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%d conflict(s) detected", len(conflicts))
 	}
-
-	return duplicates
-}
-
-// checkCircularDependencies checks for circular dependencies
-func checkCircularDependencies(deps []models.Dependency) []string {
-	// This is a basic implementation
-	// For a more complete solution, we'd need to track transitive dependencies
 	return nil
 }
 
-// checkVersionConflicts checks for version conflicts
-func checkVersionConflicts(deps []models.Dependency) []string {
-	// This is a basic implementation
-	// For a more complete solution, we'd need to parse version constraints
-	return nil
-}
+// printConflicts renders conflicts grouped by kind.
+func printConflicts(total int, conflicts []deps.Conflict) {
+	ui.PrintSection("Checking Dependencies")
+	fmt.Printf("Checking %s dependencies for conflicts...\n", ui.Bold(fmt.Sprintf("%d", total)))
+	fmt.Println()
 
-// checkMissingSpecPaths checks for missing or invalid spec paths
-func checkMissingSpecPaths(deps []models.Dependency) []string {
-	var missing []string
-
-	for _, dep := range deps {
-		if dep.SpecPath == "" {
-			missing = append(missing, dep.RepositoryURL)
-		} else if !strings.HasSuffix(dep.SpecPath, ".md") {
-			missing = append(missing, fmt.Sprintf("%s (invalid path: %s)", dep.RepositoryURL, dep.SpecPath))
+	if len(conflicts) == 0 {
+		ui.PrintSuccess("No conflicts detected")
+		fmt.Println()
+		return
+	}
+
+	groups := []struct {
+		kind  deps.ConflictKind
+		title string
+	}{
+		{deps.ConflictDuplicate, "Duplicates"},
+		{deps.ConflictCycle, "Circular dependencies"},
+		{deps.ConflictBranch, "Branch conflicts"},
+	}
+	for _, g := range groups {
+		var messages []string
+		for _, c := range conflicts {
+			if c.Kind == g.kind {
+				messages = append(messages, c.Message)
+			}
 		}
-	}
-
-	return missing
-}
-
-// buildDependencyGraph builds a dependency graph from the manifest
-func buildDependencyGraph(deps []models.Dependency) map[string][]string {
-	graph := make(map[string][]string)
-
-	for _, dep := range deps {
-		graph[dep.RepositoryURL] = make([]string, 0)
-	}
-
-	return graph
-}
-
-// detectPotentialConflicts detects potential conflicts in the dependency graph
-func detectPotentialConflicts(graph map[string][]string) []string {
-	var issues []string
-
-	// Check for duplicate dependencies
-	for repo, deps := range graph {
-		if len(deps) > 1 {
-			issues = append(issues, fmt.Sprintf("Multiple specs from %s: %v", repo, deps))
+		if len(messages) == 0 {
+			continue
 		}
-	}
-
-	// Check for self-references
-	for repo := range graph {
-		for _, dep := range graph[repo] {
-			if dep == repo {
-				issues = append(issues, fmt.Sprintf("Self-reference detected: %s depends on itself", repo))
-			}
+		ui.PrintWarning(fmt.Sprintf("%s (%d):", g.title, len(messages)))
+		for _, m := range messages {
+			fmt.Printf("  - %s\n", m)
 		}
+		fmt.Println()
 	}
-
-	return issues
 }
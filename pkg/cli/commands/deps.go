@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/specledger/specledger/pkg/cli/config"
 	"github.com/specledger/specledger/pkg/cli/framework"
 	"github.com/specledger/specledger/pkg/cli/metadata"
 	"github.com/specledger/specledger/pkg/cli/ui"
@@ -25,21 +26,28 @@ Dependencies are stored in github.com/specledger/specledger/specledger.yaml and
 Examples:
   sl deps list                           # List all dependencies
   sl deps add git@github.com:org/spec    # Add a dependency
-  sl deps remove git@github.com:org/spec # Remove a dependency`,
+  sl deps remove git@github.com:org/spec # Remove a dependency
+  sl deps diff api --format markdown     # Summarize upstream spec changes
+  sl deps check                          # Check for conflicts
+  sl deps vendor                         # Vendor artifacts for offline builds`,
 }
 
 // VarAddCmd represents the add command
 var VarAddCmd = &cobra.Command{
-	Use:   "add <repo-url> [branch] --alias <name> [--artifact-path <path>]",
+	Use:   "add <repo-url|path> [branch] --alias <name> [--artifact-path <path>]",
 	Short: "Add a dependency",
 	Long: `Add an external specification dependency to your project. The dependency will be tracked in specledger.yaml and cached locally for offline use.
 
 The --alias flag is required and will be used as the reference path when accessing artifacts from this dependency.
 
-For SpecLedger repositories, the artifact_path will be auto-detected from the dependency's specledger.yaml. For non-SpecLedger repositories, use --artifact-path to manually specify where artifacts are located.`,
+For SpecLedger repositories, the artifact_path will be auto-detected from the dependency's specledger.yaml. For non-SpecLedger repositories, use --artifact-path to manually specify where artifacts are located.
+
+Local paths (relative to the project root or absolute) and file:// URLs are linked directly without cloning, which suits monorepos and offline use. To temporarily point a remote dependency at a local checkout, use 'sl deps replace'.`,
 	Example: `  sl deps add git@github.com:org/api-spec --alias api
   sl deps add git@github.com:org/api-spec develop --alias api
-  sl deps add https://github.com/org/api-docs --alias docs --artifact-path docs/openapi/`,
+  sl deps add https://github.com/org/api-docs --alias docs --artifact-path docs/openapi/
+  sl deps add ../platform-specs --alias platform
+  sl deps add git@github.com:org/monorepo --alias platform --sparse`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runAddDependency,
 }
@@ -67,7 +75,7 @@ var VarRemoveCmd = &cobra.Command{
 var VarResolveCmd = &cobra.Command{
 	Use:     "resolve",
 	Short:   "Download and cache dependencies",
-	Long:    `Download all dependencies from specledger.yaml and cache them locally at ~/.github.com/specledger/specledger/cache/.`,
+	Long:    `Download all dependencies from specledger.yaml and cache them locally at ~/.specledger/cache/ (or $SPECLEDGER_CACHE_DIR).`,
 	Example: `  sl deps resolve`,
 	RunE:    runResolveDependencies,
 }
@@ -109,15 +117,17 @@ Example:  sl deps unlink      # Unlink all
 }
 
 func init() {
-	VarDepsCmd.AddCommand(VarAddCmd, VarDepsListCmd, VarResolveCmd, VarDepsUpdateCmd, VarLinkCmd, VarUnlinkCmd, VarRemoveCmd)
+	VarDepsCmd.AddCommand(VarAddCmd, VarDepsListCmd, VarResolveCmd, VarDepsUpdateCmd, VarLinkCmd, VarUnlinkCmd, VarRemoveCmd, VarDepsCheckCmd, VarDepsVendorCmd, VarDepsDiffCmd, VarDepsReplaceCmd)
 
 	VarAddCmd.Flags().StringP("alias", "a", "", "Required alias for the dependency (used as reference path)")
 	_ = VarAddCmd.MarkFlagRequired("alias")
 	VarAddCmd.Flags().String("artifact-path", "", "Path to artifacts within dependency repository (auto-detected for SpecLedger repos)")
 	VarAddCmd.Flags().Bool("link", false, "Create symlinks after adding dependency")
+	VarAddCmd.Flags().Bool("sparse", false, "Cache only artifact_path and specledger.yaml (partial clone, requires git)")
 
 	VarResolveCmd.Flags().BoolP("no-cache", "n", false, "Ignore cached specifications")
 	VarResolveCmd.Flags().Bool("link", false, "Create symlinks after resolving dependencies")
+	VarResolveCmd.Flags().Bool("sparse", false, "Switch all dependencies to sparse caches (artifact_path and specledger.yaml only)")
 }
 
 func runAddDependency(cmd *cobra.Command, args []string) error {
@@ -136,6 +146,7 @@ func runAddDependency(cmd *cobra.Command, args []string) error {
 	// Extract flags
 	alias, _ := cmd.Flags().GetString("alias")
 	artifactPath, _ := cmd.Flags().GetString("artifact-path")
+	sparse, _ := cmd.Flags().GetBool("sparse")
 
 	// Parse arguments
 	repoURL := args[0]
@@ -157,12 +168,24 @@ func runAddDependency(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Local paths and file:// URLs are linked directly, never cloned
+	if deps.IsLocalSource(repoURL) {
+		linkFlag, _ := cmd.Flags().GetBool("link")
+		return addLocalDependency(projectDir, meta, repoURL, alias, artifactPath, linkFlag)
+	}
+
 	// Detect framework type
 	frameworkType := metadata.FrameworkNone
 	ui.PrintSection("Detecting Framework")
 	fmt.Printf("Checking %s...\n", ui.Bold(repoURL))
 
-	detectedFramework, err := framework.DetectFramework(repoURL)
+	// A sparse dependency is never cloned in full, not even for detection
+	var detectedFramework metadata.FrameworkChoice
+	if sparse {
+		detectedFramework, err = framework.DetectFrameworkSparse(repoURL, branch)
+	} else {
+		detectedFramework, err = framework.DetectFramework(repoURL)
+	}
 	if err != nil {
 		ui.PrintWarning(fmt.Sprintf("Could not detect framework: %v", err))
 		ui.PrintWarning("Continuing with 'none' as framework type")
@@ -214,6 +237,7 @@ func runAddDependency(cmd *cobra.Command, args []string) error {
 		Alias:        alias,
 		ArtifactPath: artifactPath,
 		Framework:    frameworkType,
+		Sparse:       sparse,
 	}
 
 	// Generate import path for AI context
@@ -241,9 +265,13 @@ func runAddDependency(cmd *cobra.Command, args []string) error {
 
 	// Auto-download the dependency
 	ui.PrintSection("Downloading Dependency")
-	dirName := alias
-	homeDir, _ := os.UserHomeDir()
-	cacheDir := filepath.Join(homeDir, ".specledger", "cache", dirName)
+	cacheDir, err := deps.CachePathForDependency(alias, repoURL)
+	if err != nil {
+		ui.PrintWarning(fmt.Sprintf("Failed to locate the dependency cache: %v", err))
+		ui.PrintWarning("Dependency was added but not downloaded. Run 'sl deps resolve' to retry.")
+		fmt.Println()
+		return nil
+	}
 
 	fmt.Printf("Cache: %s\n", ui.Cyan(cacheDir))
 	fmt.Printf("Status: %s...\n", ui.Yellow("cloning"))
@@ -280,6 +308,9 @@ func runAddDependency(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Printf("  Framework:   %s\n", frameworkDisplay)
 	fmt.Printf("  Import Path: %s\n", ui.Cyan(importPath))
+	if dep.Sparse {
+		fmt.Printf("  Cache:       %s\n", ui.Cyan("sparse"))
+	}
 	fmt.Println()
 
 	// Auto-link the dependency if --link flag is set
@@ -294,6 +325,72 @@ func runAddDependency(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// addLocalDependency adds a dependency on a local directory. Framework and
+// artifact_path are detected in place, and nothing is cloned or cached.
+func addLocalDependency(projectDir string, meta *metadata.ProjectMetadata, source, alias, artifactPath string, link bool) error {
+	for _, existing := range meta.Dependencies {
+		if existing.URL == source {
+			return fmt.Errorf("dependency already exists: %s", source)
+		}
+		if existing.Alias == alias {
+			return fmt.Errorf("alias already exists: %s", alias)
+		}
+	}
+
+	localPath, err := deps.LocalSourcePath(source, projectDir)
+	if err != nil {
+		return err
+	}
+
+	ui.PrintSection("Detecting Local Source")
+	fmt.Printf("Checking %s...\n", ui.Bold(localPath))
+
+	frameworkType := framework.DetectLocalFramework(localPath)
+	if artifactPath == "" {
+		detected, err := deps.DetectArtifactPathFromSpecLedgerRepo(localPath)
+		if err != nil {
+			return fmt.Errorf("artifact_path must be specified for non-SpecLedger directories (use --artifact-path <path>): %w", err)
+		}
+		artifactPath = detected
+		fmt.Printf("  Found: %s\n", ui.Cyan(artifactPath))
+	}
+	fmt.Println()
+
+	if _, err := os.Stat(filepath.Join(localPath, artifactPath)); err != nil {
+		return fmt.Errorf("artifact path not found in local source: %s", filepath.Join(localPath, artifactPath))
+	}
+
+	dep := metadata.Dependency{
+		URL:          source,
+		Alias:        alias,
+		ArtifactPath: artifactPath,
+		Framework:    frameworkType,
+	}
+	dep.ImportPath = framework.GetFrameworkImportPath(dep)
+
+	meta.Dependencies = append(meta.Dependencies, dep)
+	if err := metadata.SaveToProject(meta, projectDir); err != nil {
+		return fmt.Errorf("failed to save metadata: %w", err)
+	}
+
+	ui.PrintSuccess("Local dependency added")
+	fmt.Printf("  Source:      %s\n", ui.Bold(source))
+	fmt.Printf("  Path:        %s\n", ui.Bold(localPath))
+	fmt.Printf("  Alias:       %s\n", ui.Bold(alias))
+	fmt.Printf("  Artifact Path: %s\n", ui.Bold(artifactPath))
+	fmt.Printf("  Import Path: %s\n", ui.Cyan(dep.ImportPath))
+	fmt.Println()
+
+	if link {
+		if err := linkDependency(projectDir, meta, dep); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to create symlink: %v", err))
+			ui.PrintWarning("Dependency was added but not linked. Run 'sl deps link' to manually link.")
+		}
+	}
+
+	return nil
+}
+
 func runListDependencies(cmd *cobra.Command, args []string) error {
 	projectDir, err := findProjectRoot()
 	if err != nil {
@@ -318,6 +415,8 @@ func runListDependencies(cmd *cobra.Command, args []string) error {
 	ui.PrintHeader("Dependencies", fmt.Sprintf("%d total", len(meta.Dependencies)), 70)
 	fmt.Println()
 
+	replace := loadDependencyReplacements(projectDir)
+
 	for i, dep := range meta.Dependencies {
 		fmt.Printf("%s. %s\n", ui.Bold(fmt.Sprintf("%d", i+1)), ui.Bold(dep.URL))
 		if dep.Branch != "" && dep.Branch != "main" {
@@ -336,7 +435,14 @@ func runListDependencies(cmd *cobra.Command, args []string) error {
 		if dep.ImportPath != "" {
 			fmt.Printf("   Import:    %s\n", ui.Yellow(dep.ImportPath))
 		}
-		if dep.ResolvedCommit != "" {
+		if dep.Sparse {
+			fmt.Printf("   Cache:   %s\n", ui.Cyan("sparse"))
+		}
+		if override, ok := replace[dep.Alias]; ok {
+			fmt.Printf("   Status:  %s %s\n", ui.Yellow("replaced"), ui.Gray("=> "+override))
+		} else if deps.IsLocalSource(dep.URL) {
+			fmt.Printf("   Status:  %s\n", ui.Cyan("local"))
+		} else if dep.ResolvedCommit != "" {
 			fmt.Printf("   Status:  %s %s\n", ui.Green("✓"), ui.Gray(dep.ResolvedCommit[:8]))
 		} else {
 			fmt.Printf("   Status:  %s (run %s)\n", ui.Yellow("not resolved"), ui.Cyan("sl deps resolve"))
@@ -413,35 +519,61 @@ func runResolveDependencies(cmd *cobra.Command, args []string) error {
 
 	// Check for --no-cache flag
 	noCache, _ := cmd.Flags().GetBool("no-cache")
+	sparse, _ := cmd.Flags().GetBool("sparse")
+	vendorDir := deps.VendorDir(projectDir, meta.GetArtifactPath())
+	replace := loadDependencyReplacements(projectDir)
 
 	// Resolve each dependency
 	resolvedCount := 0
 	for i, dep := range meta.Dependencies {
-		// Use alias as directory name if available, otherwise generate from URL
-		dirName := dep.Alias
-		if dirName == "" {
-			dirName = generateDirName(dep.URL)
+		if sparse && !dep.Sparse {
+			meta.Dependencies[i].Sparse = true
+			dep.Sparse = true
 		}
 
-		// Determine cache location: project-local github.com/specledger/specledger/deps/ or global cache
-		var cacheDir string
+		// Determine cache location: the global cache, or project-local
+		// specledger/deps/ under the same directory name
+		cacheDir, err := deps.CachePathForDependency(dep.Alias, dep.URL)
+		if err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to locate the cache for %s: %v", dep.URL, err))
+			fmt.Println()
+			continue
+		}
 		if noCache {
-			// Use project-local cache
-			cacheDir = filepath.Join(projectDir, "specledger", "deps", dirName)
-		} else {
-			// Use global cache in user home directory
-			homeDir, _ := os.UserHomeDir()
-			cacheDir = filepath.Join(homeDir, ".specledger", "cache", dirName)
+			cacheDir = filepath.Join(projectDir, "specledger", "deps", filepath.Base(cacheDir))
 		}
 
 		fmt.Printf("%s. %s\n", ui.Bold(fmt.Sprintf("%d", i+1)), ui.Bold(dep.URL))
 		if dep.Alias != "" {
 			fmt.Printf("   Alias:  %s\n", ui.Cyan(dep.Alias))
 		}
-		fmt.Printf("   Branch: %s\n", ui.Cyan(dep.Branch))
+		if dep.Branch != "" {
+			fmt.Printf("   Branch: %s\n", ui.Cyan(dep.Branch))
+		}
+
+		// Local and replaced sources are used in place
+		if sourceDir, kind, err := deps.ResolveSource(dep, projectDir, replace); kind != deps.SourceCache {
+			if err != nil {
+				ui.PrintWarning(fmt.Sprintf("Failed to resolve %s: %v", dep.URL, err))
+				fmt.Println()
+				continue
+			}
+			resolvedCount++
+			fmt.Printf("   Status: %s %s (%s)\n", ui.Green("✓"), ui.Gray(sourceDir), kind)
+			fmt.Println()
+			continue
+		}
+
+		// Vendored copies at the pinned commit need no network access
+		if vendored := deps.VendoredPath(vendorDir, dep); vendored != "" {
+			resolvedCount++
+			fmt.Printf("   Status: %s %s (vendored)\n", ui.Green("✓"), ui.Gray(dep.ResolvedCommit[:8]))
+			fmt.Println()
+			continue
+		}
 
 		// Check if already resolved (skip if --no-cache not set and commit exists)
-		if dep.ResolvedCommit != "" && !noCache {
+		if dep.ResolvedCommit != "" && !noCache && cacheMatchesSparse(cacheDir, dep.Sparse) {
 			// Verify the commit still exists in the cloned repo
 			if _, err := os.Stat(cacheDir); err == nil {
 				// Repo exists, verify commit
@@ -458,6 +590,9 @@ func runResolveDependencies(cmd *cobra.Command, args []string) error {
 
 		// Clone or update the repository
 		fmt.Printf("   Cache:  %s\n", ui.Cyan(cacheDir))
+		if dep.Sparse {
+			fmt.Printf("   Sparse: %s\n", ui.Cyan(strings.Join(deps.SparsePathsForDependency(dep.ArtifactPath), ", ")))
+		}
 		fmt.Printf("   Status: %s...\n", ui.Yellow("cloning"))
 
 		if err := cloneOrUpdateRepository(dep, cacheDir); err != nil {
@@ -501,7 +636,7 @@ func runResolveDependencies(cmd *cobra.Command, args []string) error {
 		ui.PrintSection("Linking Dependencies")
 		linkedCount := 0
 		for _, dep := range meta.Dependencies {
-			if dep.ResolvedCommit == "" {
+			if dep.ResolvedCommit == "" && !deps.IsLocalSource(dep.URL) {
 				continue // Skip unresolved dependencies
 			}
 			if err := linkDependency(projectDir, meta, dep); err != nil {
@@ -522,6 +657,13 @@ func runResolveDependencies(cmd *cobra.Command, args []string) error {
 
 // cloneOrUpdateRepository clones a Git repository if it doesn't exist, or updates it if it does
 func cloneOrUpdateRepository(dep metadata.Dependency, targetDir string) error {
+	// A cache cloned with a different sparse setting is replaced rather than converted
+	if !cacheMatchesSparse(targetDir, dep.Sparse) {
+		if err := os.RemoveAll(targetDir); err != nil {
+			return fmt.Errorf("failed to remove existing cache: %w", err)
+		}
+	}
+
 	// Check if directory already exists
 	if _, err := os.Stat(targetDir); os.IsNotExist(err) {
 		// Clone the repository using go-git/v5
@@ -531,6 +673,9 @@ func cloneOrUpdateRepository(dep metadata.Dependency, targetDir string) error {
 			TargetDir: targetDir,
 			Shallow:   false, // Do full clone for easier updates
 		}
+		if dep.Sparse {
+			cloneOpts.SparsePaths = deps.SparsePathsForDependency(dep.ArtifactPath)
+		}
 
 		_, _, err := deps.Clone(cloneOpts)
 		if err != nil {
@@ -554,21 +699,14 @@ func cloneOrUpdateRepository(dep metadata.Dependency, targetDir string) error {
 	return nil
 }
 
-// generateDirName generates a directory name from a Git URL
-func generateDirName(url string) string {
-	// Remove protocol and domain, extract repo name
-	url = strings.TrimPrefix(url, "https://")
-	url = strings.TrimPrefix(url, "http://")
-	url = strings.TrimPrefix(url, "git@")
-
-	// Replace : and / with -
-	url = strings.ReplaceAll(url, ":", "-")
-	url = strings.ReplaceAll(url, "/", "-")
-
-	// Remove .git suffix if present
-	url = strings.TrimSuffix(url, ".git")
-
-	return url
+// cacheMatchesSparse reports whether an existing cache directory was cloned
+// with the requested sparse setting. A missing cache always matches.
+func cacheMatchesSparse(cacheDir string, sparse bool) bool {
+	repo, err := deps.OpenRepository(cacheDir)
+	if err != nil {
+		return true
+	}
+	return deps.IsSparse(repo) == sparse
 }
 
 func runUpdateDependencies(cmd *cobra.Command, args []string) error {
@@ -604,12 +742,19 @@ func runUpdateDependencies(cmd *cobra.Command, args []string) error {
 		}
 
 		// Get cache directory for this dependency
-		dirName := dep.Alias
-		if dirName == "" {
-			dirName = generateDirName(dep.URL)
+		cacheDir, err := deps.CachePathForDependency(dep.Alias, dep.URL)
+		if err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to locate the cache: %v", err))
+			fmt.Println()
+			continue
+		}
+
+		// Local sources track the directory on disk, there is nothing to update
+		if deps.IsLocalSource(dep.URL) {
+			fmt.Printf("   Status: %s\n", ui.Cyan("local source"))
+			fmt.Println()
+			continue
 		}
-		homeDir, _ := os.UserHomeDir()
-		cacheDir := filepath.Join(homeDir, ".specledger", "cache", dirName)
 
 		// If dependency hasn't been resolved yet, skip
 		if dep.ResolvedCommit == "" {
@@ -651,14 +796,19 @@ func runUpdateDependencies(cmd *cobra.Command, args []string) error {
 
 		// Show commit log between current and latest
 		commits, err := deps.Log(repo, dep.ResolvedCommit, latestCommit, 5)
-		if err == nil && commits != "" {
-			lines := strings.Split(commits, "\n")
+		if err == nil && len(commits) > 0 {
 			fmt.Printf("   Changes:\n")
-			for _, line := range lines {
-				fmt.Printf("     %s\n", line)
+			for _, c := range commits {
+				fmt.Printf("     %s %s\n", c.Hash[:8], c.Subject)
 			}
 		}
 
+		// Show which spec artifacts the update touches
+		if diff, err := deps.DiffArtifacts(repo, dep.ResolvedCommit, latestCommit, dep.ArtifactPath); err == nil {
+			diff.Changes = diff.SpecChanges()
+			printArtifactDiff(diff, "   ")
+		}
+
 		// Prompt for update (or auto-apply if --yes flag exists)
 		// For now, automatically apply updates
 		fmt.Printf("   Status: %s\n", ui.Yellow("updating"))
@@ -717,7 +867,6 @@ func runLinkDependencies(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Creating symlinks from cache to %s/deps/\n", ui.Bold(projectArtifactPath))
 	fmt.Println()
 
-	homeDir, _ := os.UserHomeDir()
 	linkedCount := 0
 
 	for _, dep := range meta.Dependencies {
@@ -725,28 +874,9 @@ func runLinkDependencies(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		// Get cache directory
-		cacheDir := filepath.Join(homeDir, ".specledger", "cache", dep.Alias)
-
-		// Check if dependency is cached
-		if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
-			ui.PrintWarning(fmt.Sprintf("Dependency %s is not cached (run 'sl deps resolve' first)", dep.Alias))
-			continue
-		}
-
-		// Get dependency's artifact path
-		depArtifactPath := dep.ArtifactPath
-		if depArtifactPath == "" {
-			ui.PrintWarning(fmt.Sprintf("Dependency %s has no artifact_path", dep.Alias))
-			continue
-		}
-
-		// Source: cache_dir/dep_artifact_path
-		sourceDir := filepath.Join(cacheDir, depArtifactPath)
-
-		// Check if source exists
-		if _, err := os.Stat(sourceDir); os.IsNotExist(err) {
-			ui.PrintWarning(fmt.Sprintf("Artifact path not found in cache: %s", sourceDir))
+		sourceDir, err := dependencySourceDir(projectDir, meta, dep)
+		if err != nil {
+			ui.PrintWarning(fmt.Sprintf("Dependency %s: %v", dep.Alias, err))
 			continue
 		}
 
@@ -805,22 +935,9 @@ func linkDependency(projectDir string, meta *metadata.ProjectMetadata, dep metad
 		return fmt.Errorf("project artifact_path is not set")
 	}
 
-	homeDir, _ := os.UserHomeDir()
-
-	// Get cache directory
-	cacheDir := filepath.Join(homeDir, ".specledger", "cache", dep.Alias)
-
-	// Check if dependency is cached
-	if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
-		return fmt.Errorf("dependency is not cached")
-	}
-
-	// Source: cache_dir/dep_artifact_path
-	sourceDir := filepath.Join(cacheDir, dep.ArtifactPath)
-
-	// Check if source exists
-	if _, err := os.Stat(sourceDir); os.IsNotExist(err) {
-		return fmt.Errorf("artifact path not found in cache: %s", sourceDir)
+	sourceDir, err := dependencySourceDir(projectDir, meta, dep)
+	if err != nil {
+		return err
 	}
 
 	// Target: project_dir/project_artifact_path/deps/alias
@@ -865,11 +982,69 @@ func linkDependency(projectDir string, meta *metadata.ProjectMetadata, dep metad
 	return nil
 }
 
+// dependencySourceDir returns the directory a dependency's symlink should point to.
+// Replace overrides and local sources come first, then a vendored copy at the
+// pinned commit, then the global cache, so linking works without ever cloning.
+func dependencySourceDir(projectDir string, meta *metadata.ProjectMetadata, dep metadata.Dependency) (string, error) {
+	if dep.ArtifactPath == "" {
+		return "", fmt.Errorf("dependency has no artifact_path")
+	}
+
+	// Replaced and local sources link straight to the directory on disk
+	if root, kind, err := deps.ResolveSource(dep, projectDir, loadDependencyReplacements(projectDir)); kind != deps.SourceCache {
+		if err != nil {
+			return "", err
+		}
+		sourceDir := filepath.Join(root, dep.ArtifactPath)
+		if _, err := os.Stat(sourceDir); os.IsNotExist(err) {
+			return "", fmt.Errorf("artifact path not found in %s source: %s", kind, sourceDir)
+		}
+		return sourceDir, nil
+	}
+
+	vendorDir := deps.VendorDir(projectDir, meta.GetArtifactPath())
+	if vendored := deps.VendoredPath(vendorDir, dep); vendored != "" {
+		return vendored, nil
+	}
+
+	// Get cache directory
+	cacheDir, err := deps.CachePathForDependency(dep.Alias, dep.URL)
+	if err != nil {
+		return "", err
+	}
+
+	// Check if dependency is cached
+	if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
+		return "", fmt.Errorf("dependency is not cached (run 'sl deps resolve' first)")
+	}
+
+	// Source: cache_dir/dep_artifact_path
+	sourceDir := filepath.Join(cacheDir, dep.ArtifactPath)
+
+	// Check if source exists
+	if _, err := os.Stat(sourceDir); os.IsNotExist(err) {
+		return "", fmt.Errorf("artifact path not found in cache: %s", sourceDir)
+	}
+
+	return sourceDir, nil
+}
+
+// loadDependencyReplacements returns the alias => local path overrides from
+// specledger.local.yaml. Errors are ignored: no overrides is a safe default.
+func loadDependencyReplacements(projectDir string) map[string]string {
+	personal, err := config.LoadPersonal(projectDir)
+	if err != nil {
+		return nil
+	}
+	return personal.Replace
+}
+
 func isValidGitURL(s string) bool {
 	// Simple check for common Git URLs and local paths
 	return len(s) > 0 && (strings.HasPrefix(s, "http://") ||
 		strings.HasPrefix(s, "https://") ||
 		strings.HasPrefix(s, "git@") ||
+		strings.HasPrefix(s, "file://") ||
 		strings.HasPrefix(s, "/") || // Local absolute path
 		strings.HasPrefix(s, "./") || // Local relative path
 		strings.HasPrefix(s, "../"))
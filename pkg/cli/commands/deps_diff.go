@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/deps"
+	"github.com/spf13/cobra"
+)
+
+// VarDepsDiffCmd represents the deps diff command
+var VarDepsDiffCmd = &cobra.Command{
+	Use:   "diff [alias]",
+	Short: "Show spec artifact changes between the pinned and latest commit",
+	Long: `Show which spec artifacts (spec.md, plan.md, data-model.md, contracts/) changed
+in a dependency between the commit pinned in specledger.yaml and a candidate
+commit, without updating anything.
+
+The candidate defaults to the latest commit on the dependency's branch. Use
+--to to compare against a specific commit instead.
+
+Use --format markdown to produce a summary suitable for a pull request body.`,
+	Example: `  sl deps diff                      # All dependencies, text output
+  sl deps diff api                  # One dependency
+  sl deps diff api --format markdown
+  sl deps diff api --to 1a2b3c4d`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runDiffDependencies,
+}
+
+func init() {
+	VarDepsDiffCmd.Flags().String("format", "text", "Output format: text, markdown, json")
+	VarDepsDiffCmd.Flags().String("to", "", "Candidate commit to compare against (default: latest on branch)")
+	VarDepsDiffCmd.Flags().Bool("all", false, "Include non-spec files under artifact_path")
+}
+
+// dependencyDiff pairs a dependency with its artifact diff.
+type dependencyDiff struct {
+	Alias string             `json:"alias"`
+	URL   string             `json:"url"`
+	Diff  *deps.ArtifactDiff `json:"diff"`
+}
+
+func runDiffDependencies(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	to, _ := cmd.Flags().GetString("to")
+	all, _ := cmd.Flags().GetBool("all")
+
+	switch format {
+	case "text", "markdown", "json":
+	default:
+		return fmt.Errorf("invalid format %q (valid: text, markdown, json)", format)
+	}
+
+	projectDir, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	meta, err := metadata.LoadFromProject(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+
+	var results []dependencyDiff
+	matched := false
+	for _, dep := range meta.Dependencies {
+		if len(args) > 0 && dep.URL != args[0] && dep.Alias != args[0] {
+			continue
+		}
+		matched = true
+
+		diff, err := diffDependency(dep, to)
+		if err != nil {
+			if format == "text" {
+				ui.PrintWarning(fmt.Sprintf("%s: %v", dep.Alias, err))
+			} else {
+				// Keep stdout machine-readable
+				fmt.Fprintf(os.Stderr, "warning: %s: %v\n", dep.Alias, err)
+			}
+			continue
+		}
+		if !all {
+			diff.Changes = diff.SpecChanges()
+		}
+		results = append(results, dependencyDiff{Alias: dep.Alias, URL: dep.URL, Diff: diff})
+	}
+
+	if len(args) > 0 && !matched {
+		return fmt.Errorf("dependency not found: %s", args[0])
+	}
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "markdown":
+		fmt.Print(renderDiffMarkdown(results))
+	default:
+		for _, r := range results {
+			fmt.Printf("%s %s\n", ui.Bold(r.Alias), ui.Gray(r.URL))
+			printArtifactDiff(r.Diff, "  ")
+			fmt.Println()
+		}
+	}
+
+	return nil
+}
+
+// diffDependency computes the artifact diff between a dependency's pinned
+// commit and to (or the latest commit on its branch when to is empty).
+func diffDependency(dep metadata.Dependency, to string) (*deps.ArtifactDiff, error) {
+	if deps.IsLocalSource(dep.URL) {
+		return nil, fmt.Errorf("local source, no pinned commit to compare")
+	}
+	if dep.ResolvedCommit == "" {
+		return nil, fmt.Errorf("not resolved yet (run 'sl deps resolve' first)")
+	}
+
+	cachePath, err := deps.CachePathForDependency(dep.Alias, dep.URL)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := deps.OpenRepository(cachePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if to == "" {
+		to, err = deps.ResolveRemoteCommit(repo, dep.Branch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get remote commit: %w", err)
+		}
+	} else {
+		hash, err := repo.ResolveRevision(plumbing.Revision(to))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", to, err)
+		}
+		to = hash.String()
+	}
+
+	return deps.DiffArtifacts(repo, dep.ResolvedCommit, to, dep.ArtifactPath)
+}
+
+// printArtifactDiff prints a human-readable artifact diff with the given indent.
+func printArtifactDiff(diff *deps.ArtifactDiff, indent string) {
+	if diff.From == diff.To {
+		fmt.Printf("%sStatus:  %s\n", indent, ui.Green("already up to date"))
+		return
+	}
+
+	fmt.Printf("%sRange:   %s..%s (%d commits)\n", indent, ui.Gray(diff.From[:8]), ui.Green(diff.To[:8]), len(diff.Commits))
+	if len(diff.Changes) == 0 {
+		fmt.Printf("%sArtifacts: %s\n", indent, ui.Green("no spec artifacts changed"))
+		return
+	}
+
+	fmt.Printf("%sArtifacts:\n", indent)
+	for _, c := range diff.Changes {
+		fmt.Printf("%s  %s %s %s\n", indent, actionMarker(c.Action), c.Path, ui.Gray(fmt.Sprintf("(+%d -%d)", c.Additions, c.Deletions)))
+	}
+	if features := diff.Features(); len(features) > 0 {
+		fmt.Printf("%sFeatures: %s\n", indent, ui.Cyan(strings.Join(features, ", ")))
+	}
+}
+
+// actionMarker returns a coloured single-character marker for a change action.
+func actionMarker(action deps.ChangeAction) string {
+	switch action {
+	case deps.ChangeAdded:
+		return ui.Green("A")
+	case deps.ChangeDeleted:
+		return ui.Red("D")
+	default:
+		return ui.Yellow("M")
+	}
+}
+
+// renderDiffMarkdown renders dependency diffs as Markdown for PR bodies.
+func renderDiffMarkdown(results []dependencyDiff) string {
+	var b strings.Builder
+	b.WriteString("## Upstream spec changes\n\n")
+
+	if len(results) == 0 {
+		b.WriteString("_No dependencies to compare._\n")
+		return b.String()
+	}
+
+	for _, r := range results {
+		d := r.Diff
+		fmt.Fprintf(&b, "### `%s` (%s)\n\n", r.Alias, r.URL)
+		if d.From == d.To {
+			b.WriteString("Already up to date.\n\n")
+			continue
+		}
+		fmt.Fprintf(&b, "Pinned `%s` → candidate `%s` (%d commits)\n\n", d.From[:8], d.To[:8], len(d.Commits))
+
+		if len(d.Changes) == 0 {
+			b.WriteString("No spec artifacts changed.\n\n")
+		} else {
+			b.WriteString("| Artifact | Kind | Change | Lines |\n")
+			b.WriteString("|----------|------|--------|-------|\n")
+			for _, c := range d.Changes {
+				fmt.Fprintf(&b, "| `%s` | %s | %s | +%d / -%d |\n", c.Path, c.Kind, c.Action, c.Additions, c.Deletions)
+			}
+			b.WriteString("\n")
+		}
+
+		if len(d.Commits) > 0 {
+			b.WriteString("<details>\n<summary>Commits</summary>\n\n")
+			for _, c := range d.Commits {
+				fmt.Fprintf(&b, "- `%s` %s (%s)\n", c.Hash[:8], c.Subject, c.Author)
+			}
+			b.WriteString("\n</details>\n\n")
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/specledger/specledger/pkg/cli/config"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/deps"
+	"github.com/spf13/cobra"
+)
+
+// VarDepsReplaceCmd represents the deps replace command
+var VarDepsReplaceCmd = &cobra.Command{
+	Use:   "replace [alias] [path]",
+	Short: "Point a dependency alias at a local checkout",
+	Long: `Point a dependency alias at a local directory while iterating on it, like
+Go's replace directive.
+
+Overrides are stored in specledger/specledger.local.yaml (gitignored), so they
+only affect your machine. While an override is active, 'sl deps resolve' skips
+cloning the dependency and 'sl deps link' links straight to the local checkout.
+
+With no arguments, lists active overrides.`,
+	Example: `  sl deps replace                          # List overrides
+  sl deps replace platform ../platform-specs
+  sl deps replace platform --drop          # Remove the override`,
+	Args:         cobra.MaximumNArgs(2),
+	SilenceUsage: true,
+	RunE:         runReplaceDependency,
+}
+
+func init() {
+	VarDepsReplaceCmd.Flags().Bool("drop", false, "Remove the override for the given alias")
+}
+
+func runReplaceDependency(cmd *cobra.Command, args []string) error {
+	drop, _ := cmd.Flags().GetBool("drop")
+
+	projectDir, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	personal, err := config.LoadPersonal(projectDir)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		if drop {
+			return fmt.Errorf("--drop requires an alias")
+		}
+		printReplacements(personal.Replace)
+		return nil
+	}
+
+	alias := args[0]
+
+	if drop {
+		if _, ok := personal.Replace[alias]; !ok {
+			return fmt.Errorf("no override for %s", alias)
+		}
+		delete(personal.Replace, alias)
+		if err := personal.Save(projectDir); err != nil {
+			return err
+		}
+		ui.PrintSuccess(fmt.Sprintf("Removed override for %s", alias))
+		return relinkAlias(projectDir, alias)
+	}
+
+	if len(args) < 2 {
+		return fmt.Errorf("path is required (or use --drop to remove the override)")
+	}
+	path := args[1]
+
+	meta, err := metadata.LoadFromProject(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+	found := false
+	for _, dep := range meta.Dependencies {
+		if dep.Alias == alias {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("dependency not found: %s", alias)
+	}
+
+	localPath, err := deps.LocalSourcePath(path, projectDir)
+	if err != nil {
+		return err
+	}
+
+	if personal.Replace == nil {
+		personal.Replace = make(map[string]string)
+	}
+	personal.Replace[alias] = path
+	if err := personal.Save(projectDir); err != nil {
+		return err
+	}
+
+	ui.PrintSuccess(fmt.Sprintf("%s => %s", alias, localPath))
+	return relinkAlias(projectDir, alias)
+}
+
+// relinkAlias refreshes the symlink of a dependency if it is currently linked,
+// so an override takes effect immediately.
+func relinkAlias(projectDir, alias string) error {
+	meta, err := metadata.LoadFromProject(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+	target := filepath.Join(projectDir, meta.GetArtifactPath(), "deps", alias)
+	if info, err := os.Lstat(target); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return nil // Not linked
+	}
+
+	for _, dep := range meta.Dependencies {
+		if dep.Alias != alias {
+			continue
+		}
+		if err := linkDependency(projectDir, meta, dep); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Could not update link for %s: %v", alias, err))
+		}
+	}
+	return nil
+}
+
+// printReplacements lists active overrides sorted by alias.
+func printReplacements(replace map[string]string) {
+	if len(replace) == 0 {
+		fmt.Println("No dependency overrides.")
+		return
+	}
+
+	aliases := make([]string, 0, len(replace))
+	for alias := range replace {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	ui.PrintSection("Dependency Overrides")
+	for _, alias := range aliases {
+		fmt.Printf("  %s => %s\n", ui.Cyan(alias), replace[alias])
+	}
+	fmt.Println()
+}
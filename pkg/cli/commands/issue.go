@@ -9,6 +9,7 @@ import (
 	"text/tabwriter"
 
 	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/session"
 	"github.com/specledger/specledger/pkg/cli/ui"
 	"github.com/specledger/specledger/pkg/issues"
 	"github.com/spf13/cobra"
@@ -43,6 +44,7 @@ var (
 	issueCheckDoDFlag    string   // Mark DoD item as checked
 	issueUncheckDoDFlag  string   // Mark DoD item as unchecked
 	issueParentFlag      string   // Parent issue ID
+	issueSessionsFlag    bool     // List the AI sessions captured for the issue
 )
 
 // getArtifactPath loads the artifact_path from specledger.yaml
@@ -117,6 +119,7 @@ var issueShowCmd = &cobra.Command{
 	Short: "Show issue details",
 	Long:  `Display full details of an issue including all fields.`,
 	Example: `  sl issue show SL-a3f5d8
+  sl issue show SL-a3f5d8 --sessions
   sl issue show SL-a3f5d8 --json`,
 	Args: cobra.ExactArgs(1),
 	RunE: runIssueShow,
@@ -263,6 +266,7 @@ func init() {
 	// Show command flags
 	issueShowCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
 	issueShowCmd.Flags().BoolVar(&issueTreeFlag, "tree", false, "Show dependency tree")
+	issueShowCmd.Flags().BoolVar(&issueSessionsFlag, "sessions", false, "List AI sessions captured for the issue")
 
 	// Update command flags
 	issueUpdateCmd.Flags().StringVar(&issueTitleFlag, "title", "", "Update title")
@@ -1241,6 +1245,10 @@ func runIssueShow(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	if issueSessionsFlag {
+		printLinkedSessions(issue.ID)
+	}
+
 	fmt.Printf("Created: %s\n", issue.CreatedAt.Format("2006-01-02 15:04:05"))
 	fmt.Printf("Updated: %s\n", issue.UpdatedAt.Format("2006-01-02 15:04:05"))
 
@@ -1251,6 +1259,59 @@ func runIssueShow(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printLinkedSessions lists the AI sessions captured for an issue. Unlike the
+// session commands it never looks the project up remotely, so showing an issue
+// doesn't write project.id into specledger.yaml.
+func printLinkedSessions(issueID string) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	cfg := session.LoadStorageConfig(cwd)
+	var projectID string
+	if cfg.Backend == metadata.SessionBackendSupabase {
+		projectID, err = session.GetProjectID(cwd)
+	} else {
+		projectID, err = session.ResolveProjectID(cwd, cfg.Backend)
+	}
+	if err != nil {
+		fmt.Printf("Sessions: unavailable (%v)\n\n", err)
+		return
+	}
+	backend, err := session.OpenBackendWithConfig(cwd, cfg)
+	if err != nil {
+		fmt.Printf("Sessions: unavailable (%v)\n\n", err)
+		return
+	}
+	sessions, err := backend.Query(&session.QueryOptions{
+		ProjectID: projectID,
+		TaskID:    issueID,
+		OrderBy:   "created_at",
+		OrderDesc: true,
+	})
+	if err != nil {
+		fmt.Printf("Sessions: unavailable (%v)\n\n", err)
+		return
+	}
+	if len(sessions) == 0 {
+		fmt.Println("Sessions: none")
+		fmt.Println()
+		return
+	}
+
+	fmt.Println("Sessions:")
+	for _, s := range sessions {
+		ref := s.ID
+		if s.CommitHash != nil && len(*s.CommitHash) >= 7 {
+			ref = (*s.CommitHash)[:7]
+		}
+		fmt.Printf("  - %s %s (%d messages) [%s]\n",
+			ref, s.CreatedAt.Format("2006-01-02 15:04"), s.MessageCount, s.FeatureBranch)
+	}
+	fmt.Println("  View with: sl session get <commit>")
+	fmt.Println()
+}
+
 // renderIssueShowTree renders a tree showing parent-child hierarchy and blocking relationships
 func renderIssueShowTree(store *issues.Store, issue *issues.Issue) error {
 	tree, err := store.GetDependencyTree(issue.ID)
@@ -31,7 +31,18 @@ Examples:
   sl mockup help me gen mockup ui for spec     # With custom instructions
   sl mockup focus on the login form            # With custom instructions
   sl mockup -y                                 # Auto-confirm all prompts
-  sl mockup --format jsx                       # Generate JSX mockup`,
+  sl mockup --format jsx                       # Generate JSX mockup
+  sl mockup --app web                          # Target one app of a monorepo
+
+In pnpm/npm/yarn workspaces, Nx and Turborepo repositories, the frontend apps
+are detected in the workspace packages (e.g. apps/*). With several apps, pick
+one with --app (package name, directory name or path); design tokens of the
+shared workspace packages the app depends on (e.g. packages/ui) are merged
+into the design system.
+
+The prompt template can be overridden per project in
+.specledger/templates/mockup-prompt.tmpl (Go text/template, same data as the
+built-in template plus .ConstitutionPath and .PlanPath).`,
 	Args:         cobra.ArbitraryArgs,
 	RunE:         runMockup,
 	SilenceUsage: true,
@@ -51,6 +62,43 @@ Examples:
 	SilenceUsage: true,
 }
 
+var mockupTokensCmd = &cobra.Command{
+	Use:   "tokens",
+	Short: "Work with the project's design tokens",
+	Long: `Work with the project's design tokens: colors, spacing, radii, typography
+and shadows extracted from the Tailwind config (v3 JavaScript config or v4
+@theme blocks), CSS custom properties and SCSS variables.
+
+Subcommands:
+  export   Write the tokens as W3C Design Tokens JSON`,
+	Args:         cobra.NoArgs,
+	RunE:         func(cmd *cobra.Command, args []string) error { return cmd.Help() },
+	SilenceUsage: true,
+}
+
+var mockupTokensExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export design tokens as W3C Design Tokens JSON",
+	Long: `Export the project's design tokens in the W3C Design Tokens format, as read
+by Tokens Studio, Style Dictionary and other design tools.
+
+Tokens are grouped by category (color, spacing, radius, typography, shadow).
+When a variable is defined several times (e.g. in :root and .dark), the first
+definition wins. Variables that only reference another token are exported as
+aliases ({color.primary}); each token records its source file in $extensions.
+
+In a monorepo, tokens of the app (--app) and of the shared workspace
+packages it depends on are exported.
+
+Examples:
+  sl mockup tokens export                     # Print to stdout
+  sl mockup tokens export -o tokens.json      # Write to a file
+  sl mockup tokens export --app web -o web.tokens.json`,
+	Args:         cobra.NoArgs,
+	RunE:         runMockupTokensExport,
+	SilenceUsage: true,
+}
+
 var (
 	mockupFormat  string
 	mockupForce   bool
@@ -59,7 +107,9 @@ var (
 	mockupJSON    bool
 	mockupYes     bool
 	mockupPrompt  string
+	mockupApp     string
 	updateJSON    bool
+	tokensOutput  string
 )
 
 func init() {
@@ -70,10 +120,15 @@ func init() {
 	VarMockupCmd.Flags().BoolVar(&mockupJSON, "json", false, "Non-interactive path, output result as JSON")
 	VarMockupCmd.Flags().BoolVarP(&mockupYes, "yes", "y", false, "Auto-confirm all prompts and launch agent directly")
 	VarMockupCmd.Flags().StringVarP(&mockupPrompt, "prompt", "p", "", "Additional instructions for the AI agent")
+	VarMockupCmd.PersistentFlags().StringVar(&mockupApp, "app", "", "Workspace app to target in a monorepo (name or path)")
 
 	mockupUpdateCmd.Flags().BoolVar(&updateJSON, "json", false, "Output result as JSON")
 
+	mockupTokensExportCmd.Flags().StringVarP(&tokensOutput, "output", "o", "", "Write to a file instead of stdout")
+
 	VarMockupCmd.AddCommand(mockupUpdateCmd)
+	mockupTokensCmd.AddCommand(mockupTokensExportCmd)
+	VarMockupCmd.AddCommand(mockupTokensCmd)
 }
 
 func runMockup(cmd *cobra.Command, args []string) error {
@@ -110,21 +165,29 @@ func runMockup(cmd *cobra.Command, args []string) error {
 
 	// Step 2: Framework detection
 	fmt.Println("Detecting frontend framework...")
-	detection, err := mockup.DetectFramework(cwd)
+	target, err := resolveMockupTarget(cwd, mockupApp, !skipConfirm)
+	if err != nil {
+		return err
+	}
+	detection, err := mockup.DetectFramework(target.Dir)
 	if err != nil {
 		return fmt.Errorf("framework detection failed: %w", err)
 	}
 
 	if !detection.IsFrontend && !mockupForce {
-		return fmt.Errorf("Error: Not a frontend project\n\nNo frontend framework detected in this repository.\nUse --force to bypass this check, or run from a frontend project directory.")
+		return fmt.Errorf("Error: Not a frontend project\n\nNo frontend framework detected in %s.\nUse --force to bypass this check, or run from a frontend project directory.", target.describe())
 	}
 
 	if detection.IsFrontend {
 		headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
-		fmt.Printf("%s Detected: %s (confidence: %d%%)\n",
+		fmt.Printf("%s Detected: %s (confidence: %d%%)",
 			ui.Checkmark(),
 			headerStyle.Render(detection.Framework.String()),
 			detection.Confidence)
+		if target.App != nil {
+			fmt.Printf(" in %s", target.App.Path)
+		}
+		fmt.Println()
 	}
 
 	framework := detection.Framework
@@ -157,13 +220,7 @@ func runMockup(cmd *cobra.Command, args []string) error {
 		}
 		if !skipGenerate {
 			// Extract global CSS/design tokens and app structure
-			styleInfo := mockup.ScanStyles(cwd)
-			ds := &mockup.DesignSystem{
-				Version:      1,
-				Framework:    framework,
-				Style:        styleInfo,
-				AppStructure: mockup.ScanAppStructure(cwd, framework),
-			}
+			ds := target.scanDesignSystem(framework)
 			if err := mockup.WriteDesignSystem(dsPath, ds); err != nil {
 				return fmt.Errorf("Error: Cannot write to .specledger/memory/\n\nCheck file permissions and try again.")
 			}
@@ -175,13 +232,7 @@ func runMockup(cmd *cobra.Command, args []string) error {
 		_, loadErr := mockup.LoadDesignSystem(dsPath)
 		if loadErr != nil {
 			fmt.Printf("%s Design system is malformed, regenerating...\n", ui.WarningIcon())
-			styleInfo := mockup.ScanStyles(cwd)
-			ds := &mockup.DesignSystem{
-				Version:      1,
-				Framework:    framework,
-				Style:        styleInfo,
-				AppStructure: mockup.ScanAppStructure(cwd, framework),
-			}
+			ds := target.scanDesignSystem(framework)
 			if writeErr := mockup.WriteDesignSystem(dsPath, ds); writeErr != nil {
 				return fmt.Errorf("failed to write design system: %w", writeErr)
 			}
@@ -224,7 +275,15 @@ func runMockup(cmd *cobra.Command, args []string) error {
 	}
 
 	promptCtx := mockup.BuildMockupPromptContext(specName, specFile, specContent.Title, framework, format, outputPath, mockupPrompt)
-	promptText, err := mockup.RenderMockupPrompt(promptCtx)
+	root, err := cligit.GetRepoRoot(cwd)
+	if err != nil {
+		root = cwd
+	}
+	promptCtx.AddProjectPaths(root, filepath.Join(getArtifactPath(), specName))
+	if target.App != nil {
+		promptCtx.AppPath = target.App.Path
+	}
+	promptText, err := mockup.RenderProjectMockupPrompt(root, promptCtx)
 	if err != nil {
 		return fmt.Errorf("failed to render prompt: %w", err)
 	}
@@ -364,16 +423,25 @@ func runMockupUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load design system: %w", err)
 	}
 
+	appName := mockupApp
+	if appName == "" {
+		appName = existing.App
+	}
+	target, err := resolveMockupTarget(cwd, appName, !updateJSON)
+	if err != nil {
+		return err
+	}
+
 	framework := existing.Framework
 
-	// Detect framework if missing
-	if framework == "" || framework == mockup.FrameworkUnknown {
-		detection, err := mockup.DetectFramework(cwd)
+	// Detect framework if missing or for another app
+	if framework == "" || framework == mockup.FrameworkUnknown || target.relPath() != existing.App {
+		detection, err := mockup.DetectFramework(target.Dir)
 		if err != nil {
 			return fmt.Errorf("framework detection failed: %w", err)
 		}
 		if !detection.IsFrontend {
-			return fmt.Errorf("Error: Not a frontend project\n\nNo frontend framework detected in this repository.")
+			return fmt.Errorf("Error: Not a frontend project\n\nNo frontend framework detected in %s.", target.describe())
 		}
 		framework = detection.Framework
 	}
@@ -396,11 +464,12 @@ func runMockupUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Println("Re-extracting design tokens...")
-	styleInfo := mockup.ScanStyles(cwd)
-
+	scanned := target.scanDesignSystem(framework)
 	existing.Framework = framework
-	existing.Style = styleInfo
-	existing.AppStructure = mockup.ScanAppStructure(cwd, framework)
+	existing.App = scanned.App
+	existing.SharedPackages = scanned.SharedPackages
+	existing.Style = scanned.Style
+	existing.AppStructure = scanned.AppStructure
 	if err := mockup.WriteDesignSystem(dsPath, existing); err != nil {
 		return fmt.Errorf("Error: Cannot write to .specledger/memory/\n\nCheck file permissions and try again.")
 	}
@@ -418,6 +487,158 @@ func runMockupUpdate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runMockupTokensExport(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	target, err := resolveMockupTarget(cwd, mockupApp, tokensOutput != "")
+	if err != nil {
+		return err
+	}
+
+	var tokens *mockup.TokenSet
+	if target.App != nil {
+		tokens = mockup.ExtractWorkspaceTokens(target.Workspace, target.App)
+	} else {
+		tokens = mockup.ExtractTokens(target.Dir)
+	}
+	if len(tokens.Tokens) == 0 {
+		return fmt.Errorf("Error: No design tokens found in %s\n\nTokens are read from tailwind.config.*, global CSS files (e.g. src/index.css, app/globals.css) and SCSS variable files.", target.describe())
+	}
+
+	data, err := tokens.MarshalW3C()
+	if err != nil {
+		return fmt.Errorf("failed to encode tokens: %w", err)
+	}
+
+	if tokensOutput == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(tokensOutput, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tokensOutput, err)
+	}
+
+	var counts []string
+	for _, category := range mockup.TokenCategories {
+		if n := tokens.Count(category); n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, category))
+		}
+	}
+	fmt.Printf("%s Exported %d tokens to %s\n", ui.Checkmark(), len(tokens.Tokens), tokensOutput)
+	fmt.Printf("  %s\n", strings.Join(counts, ", "))
+	return nil
+}
+
+// mockupTarget is the project sl mockup scans: the repository, or one app of
+// a workspace.
+type mockupTarget struct {
+	Dir       string
+	Workspace *mockup.Workspace
+	App       *mockup.WorkspaceMember
+}
+
+// resolveMockupTarget finds the project to scan under cwd. In a workspace it
+// is the app named appName, the only frontend app, or one picked
+// interactively; outside a workspace it is cwd.
+func resolveMockupTarget(cwd, appName string, interactive bool) (*mockupTarget, error) {
+	ws, err := mockup.DetectWorkspace(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("workspace detection failed: %w", err)
+	}
+	if ws == nil {
+		if appName != "" {
+			return nil, fmt.Errorf("Error: --app requires a workspace\n\nNo pnpm, npm or yarn workspace, Nx or Turborepo configuration found in this repository.")
+		}
+		return &mockupTarget{Dir: cwd}, nil
+	}
+
+	target := &mockupTarget{Dir: cwd, Workspace: ws}
+	if appName != "" {
+		app, err := ws.FindApp(appName)
+		if err != nil {
+			return nil, fmt.Errorf("Error: %w", err)
+		}
+		target.App = app
+		target.Dir = filepath.Join(cwd, filepath.FromSlash(app.Path))
+		return target, nil
+	}
+
+	apps := ws.Apps()
+	switch {
+	case len(apps) == 0:
+		return target, nil
+	case len(apps) == 1:
+		target.App = &apps[0]
+	case !interactive:
+		names := make([]string, 0, len(apps))
+		for _, app := range apps {
+			names = append(names, app.Path)
+		}
+		return nil, fmt.Errorf("Error: Several frontend apps in this %s workspace\n\nApps: %s\nPick one with --app <name>", ws.Tool, strings.Join(names, ", "))
+	default:
+		options := make([]huh.Option[int], 0, len(apps))
+		for i, app := range apps {
+			options = append(options, huh.NewOption(fmt.Sprintf("%s (%s, %s)", app.Path, app.Name, app.Framework.String()), i))
+		}
+		var selected int
+		err := huh.NewForm(huh.NewGroup(
+			huh.NewSelect[int]().
+				Title(fmt.Sprintf("Select an app (%s workspace)", ws.Tool)).
+				Options(options...).
+				Value(&selected),
+		)).Run()
+		if err != nil {
+			return nil, fmt.Errorf("app selection: %w", err)
+		}
+		target.App = &apps[selected]
+	}
+	target.Dir = filepath.Join(cwd, filepath.FromSlash(target.App.Path))
+	return target, nil
+}
+
+// relPath returns the app's path, or "" for the repository.
+func (t *mockupTarget) relPath() string {
+	if t.App == nil {
+		return ""
+	}
+	return t.App.Path
+}
+
+// describe names the target in error messages.
+func (t *mockupTarget) describe() string {
+	if t.App == nil {
+		return "this repository"
+	}
+	return t.App.Path
+}
+
+// scanDesignSystem extracts the global CSS, design tokens and app structure
+// of the target. For a workspace app, the tokens of the shared packages it
+// depends on are merged in and paths are relative to the repository root.
+func (t *mockupTarget) scanDesignSystem(framework mockup.FrameworkType) *mockup.DesignSystem {
+	ds := &mockup.DesignSystem{
+		Version:   1,
+		Framework: framework,
+	}
+	if t.App == nil {
+		ds.Style = mockup.ScanStyles(t.Dir)
+		ds.AppStructure = mockup.ScanAppStructure(t.Dir, framework)
+		return ds
+	}
+
+	ds.App = t.App.Path
+	for _, pkg := range t.Workspace.SharedPackages(t.App) {
+		ds.SharedPackages = append(ds.SharedPackages, pkg.Path)
+	}
+	ds.Style = mockup.ScanWorkspaceStyles(t.Workspace, t.App)
+	ds.AppStructure = mockup.ScanAppStructure(t.Dir, framework)
+	ds.AppStructure.PrefixPaths(t.App.Path)
+	return ds
+}
+
 // resolveSpec determines the target spec name from args, branch, or interactive picker.
 func resolveSpec(cwd string, args []string) (string, error) {
 	if len(args) > 0 {
@@ -1,12 +1,17 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/specledger/specledger/internal/ref"
 	"github.com/specledger/specledger/internal/spec"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/deps"
 	"github.com/spf13/cobra"
 )
 
@@ -19,10 +24,21 @@ var VarRefsCmd = &cobra.Command{
 // VarValidateCmd represents the validate command
 var VarValidateCmd = &cobra.Command{
 	Use:   "validate [--strict] [--spec-path <path>]",
-	Short: "Validate all external references in a specification",
-	Long:  `Validate all external references in spec.md files against resolved dependencies.`,
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  runValidateReferences,
+	Short: "Validate cross-spec references against linked dependencies",
+	Long: `Validate @alias/path#heading references in every spec under artifact_path.
+
+Each reference is resolved through the dependency's cached checkout (or its
+local, replaced or vendored source). The target file must exist at the commit
+pinned in specledger.yaml, and the heading anchor, if any, must match a
+heading in that file. Broken references are reported per line.
+
+References to unknown aliases are warnings; use --strict to fail on them too.`,
+	Example: `  sl refs validate
+  sl refs validate --spec-path specledger/001-auth/spec.md
+  sl refs validate --strict --json`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runValidateReferences,
 }
 
 // VarListCmd represents the list command
@@ -36,71 +52,181 @@ func init() {
 	VarRefsCmd.AddCommand(VarValidateCmd, VarListCmd)
 
 	VarValidateCmd.Flags().BoolP("strict", "s", false, "Treat warnings as errors")
-	VarValidateCmd.Flags().StringP("spec-path", "p", "spec.md", "Path to the specification file")
+	VarValidateCmd.Flags().StringP("spec-path", "p", "", "Validate a single file instead of every spec under artifact_path")
+	VarValidateCmd.Flags().Bool("json", false, "Output results as JSON")
 	VarListCmd.Flags().StringP("spec-path", "p", "spec.md", "Path to the specification file")
 }
 
 func runValidateReferences(cmd *cobra.Command, args []string) error {
 	specPath, _ := cmd.Flags().GetString("spec-path")
 	strict, _ := cmd.Flags().GetBool("strict")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
 
-	// Read spec file
-	content, err := os.ReadFile(specPath)
+	projectDir, err := findProjectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to read spec file: %w", err)
+		return fmt.Errorf("failed to find project root: %w", err)
 	}
 
-	// Read lockfile
-	lockfile, err := spec.ReadLockfile("github.com/specledger/specledger/spec.sum")
+	meta, err := metadata.LoadFromProject(projectDir)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("no lockfile found. Run 'sl deps resolve' first")
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+
+	resolver := ref.NewResolver(filepath.Join(projectDir, metadata.DefaultMetadataFile))
+	resolver.SetSources(referenceSources(projectDir, meta))
+
+	var report *ref.ProjectReport
+	if specPath != "" {
+		rel := specPath
+		if abs, err := filepath.Abs(specPath); err == nil {
+			if r, err := filepath.Rel(projectDir, abs); err == nil {
+				rel = r
+			}
 		}
-		return fmt.Errorf("failed to read lockfile: %w", err)
+		report, err = resolver.ValidateFile(projectDir, meta.GetArtifactPath(), rel)
+	} else {
+		report, err = resolver.ValidateProject(projectDir, meta.GetArtifactPath())
+	}
+	if err != nil {
+		return err
 	}
 
-	// Build dependency mapping from lockfile entries
-	dependencies := make(map[string]string)
-	for _, entry := range lockfile.Entries {
-		// Try to use alias if available, otherwise use repository URL
-		alias := getAliasForEntry(entry, lockfile.Entries)
-		if alias != "" {
-			dependencies[alias] = entry.RepositoryURL
-		} else {
-			dependencies[entry.RepositoryURL] = entry.RepositoryURL
+	failures := report.ErrorCount()
+	if strict {
+		failures = len(report.Errors)
+	}
+
+	if jsonOutput {
+		if err := printReferenceReportJSON(report); err != nil {
+			return err
 		}
+	} else {
+		printReferenceReport(report)
 	}
 
-	// Create resolver and set dependencies
-	resolver := ref.NewResolver("github.com/specledger/specledger/spec.sum")
-	_ = resolver.SetDependencies(dependencies)
+	if failures > 0 {
+		return fmt.Errorf("%d broken reference(s) found", failures)
+	}
+	return nil
+}
 
-	// Parse references
-	references, err := resolver.ParseSpec(string(content))
+// referenceSources maps each dependency alias to the directory its artifacts
+// are read from. Cached dependencies must be checked out at the commit pinned
+// in specledger.yaml, otherwise references are validated against the wrong
+// revision.
+func referenceSources(projectDir string, meta *metadata.ProjectMetadata) map[string]ref.DependencySource {
+	replace := loadDependencyReplacements(projectDir)
+	vendorDir := deps.VendorDir(projectDir, meta.GetArtifactPath())
+
+	sources := make(map[string]ref.DependencySource, len(meta.Dependencies))
+	for _, dep := range meta.Dependencies {
+		if dep.Alias == "" {
+			continue
+		}
+		src := ref.DependencySource{Alias: dep.Alias, Commit: dep.ResolvedCommit}
+
+		dir, err := dependencySourceDir(projectDir, meta, dep)
+		if err != nil {
+			src.Err = err
+			sources[dep.Alias] = src
+			continue
+		}
+		src.Dir = dir
+
+		_, kind, _ := deps.ResolveSource(dep, projectDir, replace)
+		if kind != deps.SourceCache {
+			src.Commit = ""
+		} else if deps.VendoredPath(vendorDir, dep) == "" {
+			src.Err = checkCacheAtPinnedCommit(dep)
+		}
+		sources[dep.Alias] = src
+	}
+	return sources
+}
+
+// checkCacheAtPinnedCommit verifies that the cached checkout of dep is at its
+// resolved commit.
+func checkCacheAtPinnedCommit(dep metadata.Dependency) error {
+	if dep.ResolvedCommit == "" {
+		return fmt.Errorf("not resolved (run 'sl deps resolve' first)")
+	}
+	cachePath, err := deps.CachePathForDependency(dep.Alias, dep.URL)
 	if err != nil {
-		return fmt.Errorf("failed to parse references: %w", err)
+		return err
+	}
+	repo, err := deps.OpenRepository(cachePath)
+	if err != nil {
+		return fmt.Errorf("not cached (run 'sl deps resolve' first)")
 	}
+	head, err := deps.ResolveHead(repo)
+	if err != nil {
+		return err
+	}
+	if head != dep.ResolvedCommit {
+		return fmt.Errorf("cache is at %s but specledger.yaml pins %s (run 'sl deps resolve')", head[:8], dep.ResolvedCommit[:8])
+	}
+	return nil
+}
 
-	fmt.Printf("Found %d references in %s\n", len(references), specPath)
+// printReferenceReport prints broken references grouped by file.
+func printReferenceReport(report *ref.ProjectReport) {
+	ui.PrintSection("Cross-Spec References")
+	fmt.Printf("Checked %d references in %d files\n", report.References, report.Files)
 	fmt.Println()
 
-	// Validate references
-	errors := resolver.ValidateReferences(references)
+	if len(report.Errors) == 0 {
+		ui.PrintSuccess("All references resolved")
+		fmt.Println()
+		return
+	}
 
-	if len(errors) > 0 {
-		fmt.Println("Validation errors:")
-		for _, err := range errors {
-			fmt.Printf("  ✗ %s\n", err.Error())
+	file := ""
+	for _, e := range report.Errors {
+		if e.Reference.File != file {
+			file = e.Reference.File
+			fmt.Println(ui.Bold(file))
 		}
-		fmt.Printf("\n%d error(s) found\n", len(errors))
-
-		if strict {
-			return fmt.Errorf("%d validation error(s) found (strict mode)", len(errors))
+		marker := ui.Red("✗")
+		if e.Severity == ref.SeverityWarning {
+			marker = ui.Yellow("!")
 		}
-		return fmt.Errorf("%d validation error(s) found", len(errors))
+		fmt.Printf("  %s %s %s %s\n", marker, ui.Gray(fmt.Sprintf("%d:%d", e.Reference.Line, e.Reference.Column)), ui.Cyan(e.Reference.Raw), e.Message)
 	}
+	fmt.Println()
+}
+
+// printReferenceReportJSON prints the report as JSON for CI.
+func printReferenceReportJSON(report *ref.ProjectReport) error {
+	type jsonError struct {
+		File     string `json:"file"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+		Ref      string `json:"ref"`
+		Severity string `json:"severity"`
+		Message  string `json:"message"`
+	}
+	out := struct {
+		Files      int         `json:"files"`
+		References int         `json:"references"`
+		Errors     []jsonError `json:"errors"`
+	}{Files: report.Files, References: report.References, Errors: []jsonError{}}
 
-	fmt.Println("All references validated successfully! ✓")
+	for _, e := range report.Errors {
+		out.Errors = append(out.Errors, jsonError{
+			File:     e.Reference.File,
+			Line:     e.Reference.Line,
+			Column:   e.Reference.Column,
+			Ref:      e.Reference.Raw,
+			Severity: string(e.Severity),
+			Message:  e.Message,
+		})
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
 	return nil
 }
 
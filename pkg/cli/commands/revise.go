@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/huh"
@@ -38,22 +39,48 @@ Examples:
   sl revise 136-revise-comments      # Use the specified branch directly
   sl revise --summary                # Print compact comment listing and exit
   sl revise --auto fixture.json      # Non-interactive: fixture-driven prompt generation
-  sl revise --dry-run                # Interactive flow but write prompt to file instead of launching agent`,
+  sl revise --dry-run                # Interactive flow but write prompt to file instead of launching agent
+  sl revise --local                  # Use the local comment store (specledger/<spec>/review.jsonl)
+  sl revise --github                 # Also address review comments on the branch's GitHub pull request
+  sl revise --token-budget 4000      # Smaller prompts: more agent runs, fewer comments each
+
+Without credentials (or with --local), comments are read from and resolved in
+the spec's local comment store; push the resolutions with 'sl comment sync'.
+
+Comments whose prompt would exceed --token-budget are split into batches,
+grouped by file, and the agent runs once per batch.
+
+The prompt template can be overridden per project in
+.specledger/templates/revise-prompt.tmpl (Go text/template, same data as the
+built-in template plus .ConstitutionPath, .SpecPath and .PlanPath).
+
+With --github, review comments on the branch's open pull request are added to
+the comments to address (token from GITHUB_TOKEN, GH_TOKEN or 'gh auth token').
+Resolving one resolves its review thread on GitHub.`,
 	Args:         cobra.MaximumNArgs(1),
 	RunE:         runRevise,
 	SilenceUsage: true,
 }
 
 var (
-	reviseAutoFixture string
-	reviseDryRun      bool
-	reviseSummary     bool
+	reviseAutoFixture   string
+	reviseDryRun        bool
+	reviseSummary       bool
+	reviseLocal         bool
+	reviseGitHub        bool
+	reviseGitHubFixture string
+	reviseTokenBudget   int
 )
 
 func init() {
 	VarReviseCmd.Flags().StringVar(&reviseAutoFixture, "auto", "", "Non-interactive mode: path to fixture JSON file")
 	VarReviseCmd.Flags().BoolVar(&reviseDryRun, "dry-run", false, "Write prompt to file instead of launching agent")
 	VarReviseCmd.Flags().BoolVar(&reviseSummary, "summary", false, "Print compact comment listing and exit (for agent integration)")
+	VarReviseCmd.Flags().BoolVar(&reviseLocal, "local", false, "Use the local comment store instead of Supabase")
+	VarReviseCmd.Flags().BoolVar(&reviseGitHub, "github", false, "Include review comments of the branch's GitHub pull request")
+	VarReviseCmd.Flags().StringVar(&reviseGitHubFixture, "github-fixture", "", "Read GitHub review comments from a JSON file instead of the API (implies --github)")
+	_ = VarReviseCmd.Flags().MarkHidden("github-fixture")
+	VarReviseCmd.Flags().IntVar(&reviseTokenBudget, "token-budget", revise.DefaultTokenBudget, "Split comments into several agent runs with prompts of at most ~N tokens (0: no limit)")
 }
 
 func runRevise(cmd *cobra.Command, args []string) error {
@@ -72,13 +99,8 @@ func runRevise(cmd *cobra.Command, args []string) error {
 		return runAuto(cwd, args, reviseAutoFixture)
 	}
 
-	// Step 1: Auth check
-	accessToken, err := auth.GetValidAccessToken()
-	if err != nil {
-		return fmt.Errorf("authentication required: %w\n\nRun 'sl auth login' to authenticate.", err)
-	}
-
-	client := revise.NewReviseClient(accessToken)
+	// Step 1: Auth check (no client: local comment store)
+	client, authErr := reviseClient()
 
 	// Step 2: Branch selection → resolve specKey
 	specKey, needsCheckout, err := resolveBranch(cwd, args, client)
@@ -92,8 +114,13 @@ func runRevise(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Step 4: Fetch comments via PostgREST query chain
-	comments, changeID, err := fetchComments(cwd, specKey, client)
+	// Step 4: Fetch comments via PostgREST query chain (or the local store)
+	store, err := openReviseStore(cwd, specKey, client, authErr)
+	if err != nil {
+		return err
+	}
+
+	comments, err := fetchComments(cwd, store)
 	if err != nil {
 		return err
 	}
@@ -104,7 +131,7 @@ func runRevise(cmd *cobra.Command, args []string) error {
 	}
 
 	// Fetch thread replies for all comments in this change
-	replies, err := client.FetchReplies(changeID)
+	replies, err := store.FetchReplies()
 	if err != nil {
 		// Non-fatal: proceed without threads
 		fmt.Fprintf(os.Stderr, "warning: failed to fetch thread replies: %v\n", err)
@@ -143,59 +170,36 @@ func runRevise(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Processing %d comment(s).\n", len(processed))
 
-	// Step 7: Generate revision prompt (US4)
-	ctx := revise.BuildRevisionContext(specKey, processed, replies)
-	prompt, err := revise.RenderPrompt(ctx)
+	// Step 7: Generate revision prompts (US4), batched under the token budget
+	render := func(batch []revise.ProcessedComment) (string, error) {
+		return renderRevisePrompt(cwd, specKey, revise.BuildRevisionContext(specKey, batch, replies))
+	}
+	batches, err := revise.BatchComments(processed, reviseTokenBudget, render)
 	if err != nil {
 		return fmt.Errorf("failed to render prompt: %w", err)
 	}
+	if len(batches) > 1 {
+		fmt.Printf("Split into %d batches of up to ~%d tokens; the agent runs once per batch.\n", len(batches), reviseTokenBudget)
+	}
 
-	tokens := revise.EstimateTokens(prompt)
-	revise.PrintTokenWarnings(tokens)
+	// Snapshot the commented files to verify what the agent changed
+	root, err := cligit.GetRepoRoot(cwd)
+	if err != nil {
+		root = cwd
+	}
+	snapshot := revise.TakeSnapshot(root, processed)
 
-	// Step 8: Open editor for prompt refinement; confirm/re-edit/write-to-file/cancel.
-	finalPrompt, err := editAndConfirmPrompt(prompt, reviseDryRun)
+	// Steps 8-9: edit/confirm each prompt and launch the agent (US5)
+	addressed, err := runBatches(cwd, batches, render)
 	if err != nil {
 		return err
 	}
-	if finalPrompt == "" {
-		// User wrote to file (dry-run or manual), or cancelled.
+	if len(addressed) == 0 {
+		// Prompts written to file (dry-run or manual), or cancelled
 		return nil
 	}
-
-	// Step 9: Launch agent (US5) — inlined, called exactly once.
-	agentCmd := os.Getenv("SPECLEDGER_AGENT")
-	var agentOpt launcher.AgentOption
-	if agentCmd != "" {
-		agentOpt = launcher.AgentOption{Name: agentCmd, Command: agentCmd}
-	} else {
-		for _, a := range launcher.DefaultAgents {
-			if a.Command == "" {
-				continue
-			}
-			al := launcher.NewAgentLauncher(a, cwd)
-			if al.IsAvailable() {
-				agentOpt = a
-				break
-			}
-		}
-	}
-
-	al := launcher.NewAgentLauncher(agentOpt, cwd)
-	if !al.IsAvailable() {
-		fmt.Printf("No AI agent found. Install with: %s\n", al.InstallInstructions())
-		return writePromptToFile(finalPrompt)
-	}
-
-	// Inject config environment variables and CLI flags (base-url, auth-token, model overrides, etc.)
-	resolved := config.ResolveAgentConfig()
-	al.SetEnv(resolved.GetEnvVars())
-	al.SetFlags(resolved.GetCLIFlags())
-
-	fmt.Printf("Launching %s...\n", al.Name)
-	if err := al.LaunchWithPrompt(finalPrompt); err != nil {
-		return fmt.Errorf("agent exited with error: %w", err)
-	}
+	processed = addressed
+	verifications := snapshot.Verify(processed)
 
 	changesAfterAgent, err := cligit.HasUncommittedChanges(cwd)
 	if err != nil {
@@ -237,13 +241,91 @@ func runRevise(cmd *cobra.Command, args []string) error {
 	}
 
 	// US6: Comment resolution multi-select (sl-x1o)
-	if err := commentResolutionFlow(processed, replyMap, client, stashUsed); err != nil {
+	if err := commentResolutionFlow(processed, replyMap, store, stashUsed, verifications); err != nil {
 		return err
 	}
 
+	printLocalPending(store)
 	return nil
 }
 
+// reviseClient returns the API client, or nil with --local or when not
+// logged in (the auth error is returned for reporting).
+func reviseClient() (*revise.ReviseClient, error) {
+	if reviseLocal {
+		return nil, nil
+	}
+	accessToken, err := auth.GetValidAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	return revise.NewReviseClient(accessToken), nil
+}
+
+// openReviseStore returns the comment store of a spec, combined with the
+// review comments of its GitHub pull request with --github. The SpecLedger
+// store is then optional: its errors are reported as warnings.
+func openReviseStore(cwd, specKey string, client *revise.ReviseClient, authErr error) (comment.Store, error) {
+	if !reviseGitHub && reviseGitHubFixture == "" {
+		return openSpecLedgerStore(cwd, specKey, client, authErr)
+	}
+
+	github, err := openGitHubStore(cwd, specKey)
+	if err != nil {
+		return nil, err
+	}
+
+	primary, err := openSpecLedgerStore(cwd, specKey, client, authErr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: using GitHub review comments only: %v\n", err)
+		primary = nil
+	}
+	return comment.NewCombinedStore(primary, github), nil
+}
+
+// openGitHubStore returns the store of the review comments of the open pull
+// request of branch (or of the --github-fixture file).
+func openGitHubStore(cwd, branch string) (*comment.GitHubStore, error) {
+	if reviseGitHubFixture != "" {
+		return comment.NewGitHubStore(comment.NewGitHubFixture(reviseGitHubFixture)), nil
+	}
+
+	owner, repo, err := cligit.GetRepoOwnerName(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect GitHub repo: %w", err)
+	}
+	client := comment.NewGitHubClient(owner, repo, comment.GitHubToken())
+	if _, err := client.FindPullRequest(branch); err != nil {
+		return nil, networkHint(err)
+	}
+	return comment.NewGitHubStore(client), nil
+}
+
+// openSpecLedgerStore returns the remote change when client is set, otherwise
+// the local store. Without credentials the local store is only used if it
+// exists, so a missing login isn't mistaken for "no comments".
+func openSpecLedgerStore(cwd, specKey string, client *revise.ReviseClient, authErr error) (comment.Store, error) {
+	if client != nil {
+		changeID, err := fetchChangeID(cwd, specKey, client)
+		if err != nil {
+			return nil, err
+		}
+		return comment.NewRemoteStore(client.Client, changeID), nil
+	}
+
+	local, err := openLocalCommentStore(cwd, specKey)
+	if err != nil {
+		return nil, err
+	}
+	if authErr != nil {
+		if !local.Exists() {
+			return nil, fmt.Errorf("authentication required: %w\n\nRun 'sl auth login' to authenticate, or use --local for the local comment store.", authErr)
+		}
+		fmt.Fprintf(os.Stderr, "Not logged in: using local comments in %s\n", displayPath(cwd, local.Path()))
+	}
+	return local, nil
+}
+
 // stagingAndCommitFlow prints changed files, asks the user whether to commit,
 // and if confirmed: shows a file multi-select, prompts for a commit message,
 // then stages/commits/pushes. Returns committed=true when a commit was made,
@@ -341,11 +423,27 @@ func stagingAndCommitFlow(cwd string) (committed bool, err error) {
 // selected ones as resolved via the API (FR-017, FR-018, FR-021).
 // When a parent comment is resolved, its thread replies are also resolved (cascade).
 // Prints the stash pop reminder at session end if stashUsed.
-func commentResolutionFlow(processed []revise.ProcessedComment, replyMap map[string][]revise.ReviewComment, client *revise.ReviseClient, stashUsed bool) error {
+func commentResolutionFlow(processed []revise.ProcessedComment, replyMap map[string][]revise.ReviewComment, store comment.Store, stashUsed bool, verifications map[string]revise.Verification) error {
 	if len(processed) == 0 {
 		return nil
 	}
 
+	// Only comments whose commented text changed are pre-selected
+	unchanged := make([]string, 0)
+	if verifications != nil {
+		for _, p := range processed {
+			if v, ok := verifications[p.Comment.ID]; ok && !v.Changed {
+				unchanged = append(unchanged, p.Comment.ID)
+			}
+		}
+		fmt.Printf("\nVerification: %d of %d comment(s) had their commented text changed.\n", len(processed)-len(unchanged), len(processed))
+		for _, p := range processed {
+			if v, ok := verifications[p.Comment.ID]; ok && !v.Changed {
+				fmt.Printf("⚠ Not addressed: %s:%s (%s)\n", p.Comment.FilePath, formatLineRange(p.Comment.StartLine, p.Comment.Line), v.Reason)
+			}
+		}
+	}
+
 	options := make([]huh.Option[string], 0, len(processed))
 	for _, p := range processed {
 		label := p.Comment.FilePath
@@ -363,7 +461,12 @@ func commentResolutionFlow(processed []revise.ProcessedComment, replyMap map[str
 			}
 			label = fmt.Sprintf("%s [%d %s]", label, len(reps), noun)
 		}
-		options = append(options, huh.NewOption(label, p.Comment.ID).Selected(true))
+		preselect := true
+		if v, ok := verifications[p.Comment.ID]; ok && !v.Changed {
+			label += " [not changed]"
+			preselect = false
+		}
+		options = append(options, huh.NewOption(label, p.Comment.ID).Selected(preselect))
 	}
 
 	selected := make([]string, 0, len(processed))
@@ -378,6 +481,10 @@ func commentResolutionFlow(processed []revise.ProcessedComment, replyMap map[str
 		return fmt.Errorf("resolution selection: %w", err)
 	}
 
+	if err := notAddressedFlow(unchanged, selected, store); err != nil {
+		return err
+	}
+
 	if len(selected) == 0 {
 		// FR-021: all deferred
 		fmt.Println("Unresolved comments remain. Re-run sl revise after pushing to resolve them.")
@@ -398,13 +505,13 @@ func commentResolutionFlow(processed []revise.ProcessedComment, replyMap map[str
 		}
 
 		if len(replyIDs) > 0 {
-			if err := client.ResolveCommentWithReplies(id, replyIDs); err != nil {
+			if err := store.ResolveCommentWithReplies(id, replyIDs); err != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to resolve comment %s: %v\n", id, err)
 				continue
 			}
 			resolvedReplies += len(replyIDs)
 		} else {
-			if err := client.ResolveComment(id); err != nil {
+			if err := store.ResolveComment(id); err != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to resolve comment %s: %v\n", id, err)
 				continue
 			}
@@ -428,6 +535,49 @@ func commentResolutionFlow(processed []revise.ProcessedComment, replyMap map[str
 	return nil
 }
 
+// notAddressedFlow offers to reply "not addressed" on the unchanged comments
+// left unresolved; otherwise they just stay open.
+func notAddressedFlow(unchanged, selected []string, store comment.Store) error {
+	selectedSet := make(map[string]bool, len(selected))
+	for _, id := range selected {
+		selectedSet[id] = true
+	}
+	open := make([]string, 0, len(unchanged))
+	for _, id := range unchanged {
+		if !selectedSet[id] {
+			open = append(open, id)
+		}
+	}
+	if len(open) == 0 {
+		return nil
+	}
+
+	var reply bool
+	err := huh.NewForm(huh.NewGroup(
+		huh.NewConfirm().
+			Title(fmt.Sprintf("Reply \"not addressed\" on %d unchanged comment(s)?", len(open))).
+			Description("Otherwise they stay open without a reply.").
+			Value(&reply), // default false → stay open
+	)).Run()
+	if err != nil {
+		return fmt.Errorf("not addressed confirmation: %w", err)
+	}
+	if !reply {
+		return nil
+	}
+
+	replied := 0
+	for _, id := range open {
+		if _, err := store.CreateReply(id, revise.NotAddressedReply); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to reply to comment %s: %v\n", id, err)
+			continue
+		}
+		replied++
+	}
+	fmt.Printf("Replied on %d comment(s).\n", replied)
+	return nil
+}
+
 // resolveBranch determines the target spec key.
 // Returns (specKey, targetBranch, error). targetBranch is empty if already on the target branch.
 func resolveBranch(cwd string, args []string, client *revise.ReviseClient) (specKey, targetBranch string, err error) {
@@ -460,6 +610,9 @@ func resolveBranch(cwd string, args []string, client *revise.ReviseClient) (spec
 	}
 
 	// Not a feature branch or user wants to pick: show branch list from API
+	if client == nil {
+		return pickBranchFromLocal(cwd, currentBranch)
+	}
 	return pickBranchFromAPI(cwd, currentBranch, client)
 }
 
@@ -484,6 +637,38 @@ func pickBranchFromAPI(cwd, currentBranch string, client *revise.ReviseClient) (
 		return "", "", fmt.Errorf("no specs with unresolved comments found for %s/%s", repoOwner, repoName)
 	}
 
+	return pickBranch(specs, currentBranch)
+}
+
+// pickBranchFromLocal shows a branch picker over the specs whose local
+// comment store has unresolved comments.
+func pickBranchFromLocal(cwd, currentBranch string) (specKey, targetBranch string, err error) {
+	root, err := cligit.GetRepoRoot(cwd)
+	if err != nil {
+		return "", "", err
+	}
+
+	counts, err := comment.LocalSpecs(filepath.Join(root, getArtifactPath()))
+	if err != nil {
+		return "", "", err
+	}
+
+	specs := make([]revise.SpecWithCommentCount, 0, len(counts))
+	for _, key := range comment.SortedSpecKeys(counts) {
+		if counts[key] > 0 {
+			specs = append(specs, revise.SpecWithCommentCount{SpecKey: key, CommentCount: counts[key]})
+		}
+	}
+
+	if len(specs) == 0 {
+		return "", "", fmt.Errorf("no specs with unresolved local comments found")
+	}
+
+	return pickBranch(specs, currentBranch)
+}
+
+// pickBranch shows a branch picker and reports whether a checkout is needed.
+func pickBranch(specs []revise.SpecWithCommentCount, currentBranch string) (specKey, targetBranch string, err error) {
 	options := make([]huh.Option[string], 0, len(specs))
 	for _, s := range specs {
 		label := fmt.Sprintf("%s (%d comment(s))", s.SpecKey, s.CommentCount)
@@ -579,35 +764,41 @@ func checkoutIfNeeded(cwd, targetBranch string) (stashUsed bool, err error) {
 	return stashUsed, nil
 }
 
-// fetchComments runs the 4-step PostgREST query chain and returns unresolved comments
-// along with the changeID (needed for fetching thread replies).
-func fetchComments(cwd, specKey string, client *revise.ReviseClient) ([]revise.ReviewComment, string, error) {
+// fetchChangeID runs the first 3 steps of the PostgREST query chain and returns
+// the changeID of the spec (needed for fetching comments and thread replies).
+func fetchChangeID(cwd, specKey string, client *revise.ReviseClient) (string, error) {
 	repoOwner, repoName, err := cligit.GetRepoOwnerName(cwd)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to detect repo: %w", err)
+		return "", fmt.Errorf("failed to detect repo: %w", err)
 	}
 
 	project, err := client.GetProject(repoOwner, repoName)
 	if err != nil {
-		return nil, "", networkHint(fmt.Errorf("failed to fetch project: %w", err))
+		return "", networkHint(fmt.Errorf("failed to fetch project: %w", err))
 	}
 
 	spec, err := client.GetSpec(project.ID, specKey)
 	if err != nil {
-		return nil, "", networkHint(fmt.Errorf("failed to fetch spec %q: %w", specKey, err))
+		return "", networkHint(fmt.Errorf("failed to fetch spec %q: %w", specKey, err))
 	}
 
 	change, err := client.GetChange(spec.ID)
 	if err != nil {
-		return nil, "", networkHint(fmt.Errorf("failed to fetch change for spec %q: %w", specKey, err))
+		return "", networkHint(fmt.Errorf("failed to fetch change for spec %q: %w", specKey, err))
 	}
 
-	comments, err := client.FetchComments(change.ID)
+	return change.ID, nil
+}
+
+// fetchComments returns the unresolved comments of a store, re-anchored in
+// the current version of their files.
+func fetchComments(cwd string, store comment.Store) ([]revise.ReviewComment, error) {
+	comments, err := store.FetchComments()
 	if err != nil {
-		return nil, "", networkHint(fmt.Errorf("failed to fetch comments: %w", err))
+		return nil, networkHint(fmt.Errorf("failed to fetch comments: %w", err))
 	}
-
-	return comments, change.ID, nil
+	reanchorComments(cwd, comments)
+	return comments, nil
 }
 
 // selectArtifacts groups comments by file_path, shows a huh multi-select with counts,
@@ -713,7 +904,9 @@ func processComments(comments []revise.ReviewComment, replyMap map[string][]revi
 			// Try literal match first; fall back to markdown-stripped comparison to avoid
 			// false positives when the reviewer selected from a rendered view (backticks,
 			// bold markers etc. are invisible in rendered markdown but present in the raw file).
-			if fileExists {
+			if fileExists && c.AnchorStatus == comment.AnchorOutdated {
+				fmt.Println("⚠ Original selected text not found in current file version (outdated)")
+			} else if fileExists && c.AnchorStatus == "" {
 				content, readErr := os.ReadFile(c.FilePath)
 				if readErr == nil {
 					fileStr := string(content)
@@ -803,18 +996,13 @@ func processComments(comments []revise.ReviewComment, replyMap map[string][]revi
 
 // runAuto handles --auto mode: fixture-driven, non-interactive, prints prompt to stdout.
 func runAuto(cwd string, args []string, fixturePath string) error {
-	accessToken, err := auth.GetValidAccessToken()
-	if err != nil {
-		return fmt.Errorf("authentication required: %w\n\nRun 'sl auth login' to authenticate.", err)
-	}
+	client, authErr := reviseClient()
 
 	fixture, err := revise.ParseFixture(fixturePath)
 	if err != nil {
 		return err
 	}
 
-	client := revise.NewReviseClient(accessToken)
-
 	// Resolve branch from fixture or args
 	specKey := fixture.Branch
 	if len(args) > 0 {
@@ -827,13 +1015,18 @@ func runAuto(cwd string, args []string, fixturePath string) error {
 		}
 	}
 
-	comments, changeID, err := fetchComments(cwd, specKey, client)
+	store, err := openReviseStore(cwd, specKey, client, authErr)
+	if err != nil {
+		return err
+	}
+
+	comments, err := fetchComments(cwd, store)
 	if err != nil {
 		return err
 	}
 
 	// Fetch thread replies (non-fatal if it fails)
-	replies, _ := client.FetchReplies(changeID)
+	replies, _ := store.FetchReplies()
 
 	matched, warnings := revise.MatchFixtureComments(fixture, comments)
 	for _, w := range warnings {
@@ -845,16 +1038,151 @@ func runAuto(cwd string, args []string, fixturePath string) error {
 		os.Exit(1)
 	}
 
-	ctx := revise.BuildRevisionContext(specKey, matched, replies)
-	prompt, err := revise.RenderPrompt(ctx)
+	batches, err := revise.BatchComments(matched, reviseTokenBudget, func(batch []revise.ProcessedComment) (string, error) {
+		return renderRevisePrompt(cwd, specKey, revise.BuildRevisionContext(specKey, batch, replies))
+	})
 	if err != nil {
 		return fmt.Errorf("failed to render prompt: %w", err)
 	}
 
-	fmt.Print(prompt)
+	// Several batches: one prompt per batch, each preceded by a marker line
+	for _, b := range batches {
+		if len(batches) > 1 {
+			fmt.Printf("<!-- sl revise batch %d/%d: %s -->\n", b.Number, len(batches), strings.Join(b.CommentIDs(), ","))
+		}
+		fmt.Print(b.Prompt)
+	}
 	return nil
 }
 
+// runBatches runs the agent on each batch in turn, after the prompt has been
+// reviewed, and returns the comments of the batches it completed. The prompt
+// of a later batch is re-rendered with its comments re-anchored in the files
+// as edited by the earlier runs. It stops at the first cancelled or failed
+// batch; with --dry-run every prompt is written to a file instead.
+func runBatches(cwd string, batches []revise.Batch, render func([]revise.ProcessedComment) (string, error)) ([]revise.ProcessedComment, error) {
+	status := make([]string, len(batches))
+	for i := range status {
+		status[i] = "not run"
+	}
+
+	var al *launcher.AgentLauncher
+	addressed := make([]revise.ProcessedComment, 0)
+	for i, b := range batches {
+		prompt := b.Prompt
+		if len(batches) > 1 {
+			fmt.Printf("\nBatch %d of %d: %d comment(s) (%s)\n", b.Number, len(batches), len(b.Comments), strings.Join(b.CommentIDs(), ", "))
+			if len(addressed) > 0 {
+				if rerendered, err := rerenderBatch(cwd, b, render); err == nil {
+					prompt = rerendered
+				}
+			}
+		}
+		revise.PrintTokenWarnings(revise.EstimateTokens(prompt))
+
+		// Step 8: Open editor for prompt refinement; confirm/re-edit/write-to-file/cancel.
+		finalPrompt, err := editAndConfirmPrompt(prompt, reviseDryRun)
+		if err != nil {
+			return nil, err
+		}
+		if finalPrompt == "" {
+			if reviseDryRun {
+				continue
+			}
+			break
+		}
+
+		// Step 9: Launch agent
+		if al == nil {
+			al = reviseAgent(cwd)
+		}
+		if !al.IsAvailable() {
+			fmt.Printf("No AI agent found. Install with: %s\n", al.InstallInstructions())
+			if err := writePromptToFile(finalPrompt); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		fmt.Printf("Launching %s...\n", al.Name)
+		if err := al.LaunchWithPrompt(finalPrompt); err != nil {
+			if len(batches) == 1 {
+				return nil, fmt.Errorf("agent exited with error: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "agent exited with error on batch %d: %v\n", b.Number, err)
+			status[i] = "failed"
+			break
+		}
+		status[i] = "done"
+		addressed = append(addressed, b.Comments...)
+	}
+
+	if len(batches) > 1 {
+		fmt.Println("\nBatches:")
+		for i, b := range batches {
+			fmt.Printf("  %d. %-7s %d comment(s): %s\n", b.Number, status[i], len(b.Comments), strings.Join(b.CommentIDs(), ", "))
+		}
+	}
+	return addressed, nil
+}
+
+// rerenderBatch renders the prompt of a batch with its comments re-anchored
+// in the current version of their files.
+func rerenderBatch(cwd string, b revise.Batch, render func([]revise.ProcessedComment) (string, error)) (string, error) {
+	comments := make([]comment.ReviewComment, len(b.Comments))
+	for i, p := range b.Comments {
+		comments[i] = p.Comment
+	}
+	reanchorComments(cwd, comments)
+
+	batch := make([]revise.ProcessedComment, len(b.Comments))
+	for i, p := range b.Comments {
+		p.Comment = comments[i]
+		batch[i] = p
+	}
+	return render(batch)
+}
+
+// reviseAgent returns the launcher of the agent set by SPECLEDGER_AGENT, or
+// of the first available default agent, with the agent config applied.
+func reviseAgent(cwd string) *launcher.AgentLauncher {
+	agentCmd := os.Getenv("SPECLEDGER_AGENT")
+	var agentOpt launcher.AgentOption
+	if agentCmd != "" {
+		agentOpt = launcher.AgentOption{Name: agentCmd, Command: agentCmd}
+	} else {
+		for _, a := range launcher.DefaultAgents {
+			if a.Command == "" {
+				continue
+			}
+			al := launcher.NewAgentLauncher(a, cwd)
+			if al.IsAvailable() {
+				agentOpt = a
+				break
+			}
+		}
+	}
+
+	al := launcher.NewAgentLauncher(agentOpt, cwd)
+
+	// Inject config environment variables and CLI flags (base-url, auth-token, model overrides, etc.)
+	resolved := config.ResolveAgentConfig()
+	al.SetEnv(resolved.GetEnvVars())
+	al.SetFlags(resolved.GetCLIFlags())
+	return al
+}
+
+// renderRevisePrompt renders the project's revise prompt template override,
+// or the embedded template, with the project paths of the spec.
+func renderRevisePrompt(cwd, specKey string, ctx revise.RevisionContext) (string, error) {
+	root, err := cligit.GetRepoRoot(cwd)
+	if err != nil {
+		root = cwd
+	}
+	ctx.AddProjectPaths(root, filepath.Join(getArtifactPath(), specKey))
+	return revise.RenderProjectPrompt(root, ctx)
+}
+
 // editAndConfirmPrompt opens the prompt in the user's editor then shows a
 // Launch / Re-edit / Write-to-file / Cancel menu. Returns the final prompt
 // string (empty string signals cancellation or dry-run write).
@@ -1026,14 +1354,7 @@ func writePromptToFile(prompt string) error {
 // runSummary implements --summary: compact comment listing for agent callers.
 // Auth failures exit silently with code 1.
 func runSummary(cwd string, args []string) error {
-	accessToken, err := auth.GetValidAccessToken()
-	if err != nil {
-		// Silent exit on auth failure (FR-025)
-		os.Exit(1)
-		return nil
-	}
-
-	client := revise.NewReviseClient(accessToken)
+	client, authErr := reviseClient()
 
 	var specKey string
 	if len(args) > 0 {
@@ -1047,7 +1368,14 @@ func runSummary(cwd string, args []string) error {
 		specKey = currentBranch
 	}
 
-	comments, changeID, err := fetchComments(cwd, specKey, client)
+	store, err := openReviseStore(cwd, specKey, client, authErr)
+	if err != nil {
+		// Silent exit on auth failure (FR-025) or any fetch error
+		os.Exit(1)
+		return nil
+	}
+
+	comments, err := fetchComments(cwd, store)
 	if err != nil {
 		// Silent exit on any fetch error
 		os.Exit(1)
@@ -1060,7 +1388,7 @@ func runSummary(cwd string, args []string) error {
 	}
 
 	// Fetch thread replies (non-fatal)
-	replies, _ := client.FetchReplies(changeID)
+	replies, _ := store.FetchReplies()
 	replyMap := comment.BuildReplyMap(replies)
 
 	// Compact format: file_path:line  'selected_text'  (author)  [N replies]
@@ -1076,6 +1404,9 @@ func runSummary(cwd string, args []string) error {
 		} else {
 			lineStr = "-"
 		}
+		if c.AnchorStatus == comment.AnchorOutdated {
+			lineStr += " [outdated]"
+		}
 
 		selectedText := c.SelectedText
 		if len(selectedText) > 50 {
@@ -6,9 +6,10 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
-	"github.com/specledger/specledger/pkg/cli/auth"
 	"github.com/specledger/specledger/pkg/cli/session"
+	"github.com/specledger/specledger/pkg/cli/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -22,10 +23,33 @@ Sessions are automatically captured when you commit changes while working
 with Claude Code. They provide a record of the AI conversation that led
 to each commit.
 
+Sessions are stored in Supabase by default. To keep transcripts off the
+hosted service, select another backend in specledger/specledger.yaml:
+
+  session:
+    storage:
+      backend: local            # .specledger/sessions (or set path:)
+      # backend: s3             # S3-compatible; credentials from
+      # endpoint: https://...   # SPECLEDGER_S3_ACCESS_KEY_ID and
+      # bucket: sessions        # SPECLEDGER_S3_SECRET_ACCESS_KEY
+
+Secrets (API keys, tokens, .env values) are redacted from transcripts before
+they are compressed, queued or uploaded. Preview with 'sl session scan'.
+
+Sessions are linked to an issue when the commit message mentions its ID
+(e.g. a "Refs: SL-abc123" trailer), or else when it is the only in_progress
+issue of the current spec. 'sl issue show <id>' lists the linked sessions.
+
 Commands:
   list     List sessions for a branch
   get      Retrieve session content by ID, commit hash, or task ID
+  search   Full-text search over captured sessions
+  export   Export a session as Markdown or HTML
+  stats    Session statistics per feature branch and issue
   sync     Upload queued sessions (for offline captures)
+  daemon   Upload queued sessions in the background
+  prune    Remove old sessions from the local cache
+  scan     Dry-run secret redaction on a transcript or files
   capture  (Internal) Called by Claude Code hooks
 
 Examples:
@@ -39,20 +63,30 @@ Examples:
 
 // VarSessionCaptureCmd represents the capture command (called by hooks)
 var VarSessionCaptureCmd = &cobra.Command{
-	Use:   "capture",
+	Use:   "capture [payload]",
 	Short: "Capture session from hook input",
-	Long: `Capture an AI session from Claude Code hook input.
+	Long: `Capture an AI session from coding agent hook input.
 
-This command is designed to be called by Claude Code hooks, not manually.
+This command is designed to be called by agent hooks, not manually.
 It reads hook JSON from stdin, detects git commits, and captures the
 conversation delta since the last commit.
 
+Supported agents (--agent):
+  claude          Claude Code PostToolUse hook (default)
+  opencode        OpenCode plugin
+  github-copilot  Copilot CLI postToolUse hook
+  codex           Codex notify program; the payload is passed as an argument
+                  and a session is captured when a turn moved HEAD
+
+Install the hook for an agent with: sl auth hook --install --agent <name>
+
 Test mode (for manual testing):
   sl session capture --test-mode
 
 Exit codes:
   0 - Success (session captured/queued) or no-op (not a commit)
   1 - Fatal error (logged to stderr)`,
+	Args:         cobra.MaximumNArgs(1),
 	RunE:         runSessionCapture,
 	SilenceUsage: true,
 }
@@ -87,8 +121,12 @@ You can look up a session by:
   - Commit hash (full or partial, e.g., "abc1234" or full 40-char hash)
   - Task ID (e.g., SL-42)
 
+Sessions captured with schema 2.0 also record the tools the agent ran:
+shell commands with their exit status and file edits with line counts.
+
 Examples:
   sl session get abc1234               # Get by partial commit hash
+  sl session get abc1234 --tool-output # Include what each command printed
   sl session get SL-42                 # Get by task ID
   sl session get 550e8400-e29b...      # Get by full UUID
   sl session get abc1234 --json        # Output as JSON (for AI processing)
@@ -104,20 +142,29 @@ var VarSessionSyncCmd = &cobra.Command{
 	Long: `Upload sessions that were queued due to network failures.
 
 When session capture fails (e.g., network down), sessions are stored
-locally in ~/.specledger/session-queue/ and can be uploaded later.
+locally in ~/.specledger/ and can be uploaded later to the configured
+storage backend.
+
+Failed uploads are retried with exponential backoff (30s doubling up to
+1h, with jitter) and given up after 10 attempts; attempts made while
+offline don't count. 'sl session daemon' drains the queue in the
+background.
 
 Examples:
-  sl session sync            # Upload all queued sessions
-  sl session sync --status   # Check queue status without uploading
-  sl session sync --json     # Output results as JSON`,
+  sl session sync                  # Upload queued sessions that are due
+  sl session sync --force          # Retry the project's sessions now, ignoring backoff
+  sl session sync --status         # Queue and daemon status without uploading
+  sl session sync --status --json  # Same, as JSON
+  sl session sync --json           # Output results as JSON`,
 	RunE: runSessionSync,
 }
 
 func init() {
-	VarSessionCmd.AddCommand(VarSessionCaptureCmd, VarSessionListCmd, VarSessionGetCmd, VarSessionSyncCmd)
+	VarSessionCmd.AddCommand(VarSessionCaptureCmd, VarSessionListCmd, VarSessionGetCmd, VarSessionSyncCmd, VarSessionScanCmd, VarSessionSearchCmd, VarSessionExportCmd, VarSessionDaemonCmd, VarSessionPruneCmd, VarSessionStatsCmd)
 
 	// Capture flags
 	VarSessionCaptureCmd.Flags().Bool("test-mode", false, "Run in test mode with simulated hook input")
+	VarSessionCaptureCmd.Flags().String("agent", session.AgentClaude, "Agent that sent the hook input ("+strings.Join(session.TranscriptAgents(), ", ")+")")
 
 	// List flags
 	VarSessionListCmd.Flags().String("feature", "", "Feature branch to list sessions for (default: current branch)")
@@ -129,14 +176,18 @@ func init() {
 	// Get flags
 	VarSessionGetCmd.Flags().Bool("json", false, "Output as JSON (for AI processing)")
 	VarSessionGetCmd.Flags().Bool("raw", false, "Output raw gzip stream (for piping)")
+	VarSessionGetCmd.Flags().Bool("no-tools", false, "Hide tool calls (commands and file edits)")
+	VarSessionGetCmd.Flags().Bool("tool-output", false, "Show the captured output of each tool call")
 
 	// Sync flags
 	VarSessionSyncCmd.Flags().Bool("json", false, "Output results as JSON")
 	VarSessionSyncCmd.Flags().Bool("status", false, "Check queue status without uploading")
+	VarSessionSyncCmd.Flags().Bool("force", false, "Retry this project's queued sessions now, ignoring backoff and the retry limit")
 }
 
 func runSessionCapture(cmd *cobra.Command, args []string) error {
 	testMode, _ := cmd.Flags().GetBool("test-mode")
+	agent, _ := cmd.Flags().GetString("agent")
 
 	var result *session.CaptureResult
 
@@ -144,9 +195,12 @@ func runSessionCapture(cmd *cobra.Command, args []string) error {
 		// Run in test mode with simulated input
 		fmt.Fprintln(os.Stderr, "Running in test mode...")
 		result = session.CaptureTestMode()
+	} else if len(args) == 1 {
+		// Payload passed as an argument (Codex notify)
+		result = session.CaptureFromPayload(agent, []byte(args[0]))
 	} else {
 		// Normal mode: read from stdin
-		result = session.CaptureFromStdin()
+		result = session.CaptureFromStdin(agent)
 	}
 
 	if result.Error != nil {
@@ -162,6 +216,12 @@ func runSessionCapture(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "Session queued for upload: %s (%d messages, %d bytes)\n",
 			result.SessionID, result.MessageCount, result.SizeBytes)
 	}
+	if n := result.Redactions.Total(); n > 0 {
+		fmt.Fprintf(os.Stderr, "Redacted %d secret(s): %s\n", n, result.Redactions)
+	}
+	if result.TaskID != "" {
+		fmt.Fprintf(os.Stderr, "Linked to issue %s\n", result.TaskID)
+	}
 
 	return nil
 }
@@ -185,24 +245,12 @@ func runSessionList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Get project ID
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
-	}
-	projectID, err := session.GetProjectIDWithFallback(cwd)
+	backend, projectID, err := openSessionBackend()
 	if err != nil {
-		return fmt.Errorf("project not configured: %w\n\nHint: Ensure specledger.yaml has 'project.id' set, or the project is registered in Supabase with matching git remote.", err)
-	}
-
-	// Get access token
-	accessToken, err := auth.GetValidAccessToken()
-	if err != nil {
-		return fmt.Errorf("authentication required: run 'sl auth login' first\n\nDetails: %w", err)
+		return err
 	}
 
 	// Query sessions
-	client := session.NewMetadataClient()
 	opts := &session.QueryOptions{
 		ProjectID:     projectID,
 		FeatureBranch: featureBranch,
@@ -213,7 +261,7 @@ func runSessionList(cmd *cobra.Command, args []string) error {
 		OrderDesc:     true,
 	}
 
-	sessions, err := client.Query(accessToken, opts)
+	sessions, err := backend.Query(opts)
 	if err != nil {
 		return fmt.Errorf("failed to query sessions: %w", err)
 	}
@@ -259,55 +307,29 @@ func runSessionGet(cmd *cobra.Command, args []string) error {
 	identifier := args[0]
 	jsonOutput, _ := cmd.Flags().GetBool("json")
 	rawOutput, _ := cmd.Flags().GetBool("raw")
+	hideTools, _ := cmd.Flags().GetBool("no-tools")
+	showOutput, _ := cmd.Flags().GetBool("tool-output")
 
-	// Get project ID
-	cwd, err := os.Getwd()
+	backend, projectID, err := openSessionBackend()
 	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
-	}
-	projectID, err := session.GetProjectIDWithFallback(cwd)
-	if err != nil {
-		return fmt.Errorf("project not configured: %w\n\nHint: Ensure specledger.yaml has 'project.id' set, or the project is registered in Supabase with matching git remote.", err)
+		return err
 	}
 
-	// Get access token
-	accessToken, err := auth.GetValidAccessToken()
+	// Try to find the session by ID, commit hash, or task ID
+	sessionMeta, err := session.FindSession(backend, projectID, identifier)
 	if err != nil {
-		return fmt.Errorf("authentication required: run 'sl auth login' first\n\nDetails: %w", err)
-	}
-
-	// Try to find the session by different identifiers
-	metaClient := session.NewMetadataClient()
-	var sessionMeta *session.SessionMetadata
-
-	// Check if identifier looks like a UUID (36 chars with dashes)
-	isUUID := len(identifier) == 36 && identifier[8] == '-' && identifier[13] == '-'
-
-	// Try as UUID if it looks like one
-	if isUUID {
-		sessionMeta, _ = metaClient.GetByID(accessToken, identifier)
-	}
-
-	// Try as commit hash
-	if sessionMeta == nil {
-		sessionMeta, _ = metaClient.GetByCommitHash(accessToken, projectID, identifier)
+		return fmt.Errorf("failed to look up session: %w", err)
 	}
-
-	// Try as task ID
-	if sessionMeta == nil {
-		sessionMeta, _ = metaClient.GetByTaskID(accessToken, projectID, identifier)
-	}
-
 	if sessionMeta == nil {
 		return fmt.Errorf("session not found: %s", identifier)
 	}
 
 	// Download content
-	storageClient := session.NewStorageClient()
-	compressed, err := storageClient.Download(accessToken, sessionMeta.StoragePath)
+	compressed, err := backend.Download(sessionMeta.StoragePath)
 	if err != nil {
 		return fmt.Errorf("failed to download session: %w", err)
 	}
+	session.CacheSession(sessionMeta, compressed)
 
 	if rawOutput {
 		_, err = os.Stdout.Write(compressed)
@@ -341,6 +363,9 @@ func runSessionGet(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Task:    %s\n", content.TaskID)
 	}
 	fmt.Printf("Author:  %s\n", content.Author)
+	if content.Agent != "" {
+		fmt.Printf("Agent:   %s\n", content.Agent)
+	}
 	fmt.Printf("Date:    %s\n", content.CapturedAt.Format("2006-01-02 15:04:05"))
 	fmt.Printf("Messages: %d\n", len(content.Messages))
 	fmt.Println(strings.Repeat("-", 60))
@@ -348,15 +373,41 @@ func runSessionGet(cmd *cobra.Command, args []string) error {
 	for _, msg := range content.Messages {
 		role := strings.ToUpper(msg.Role)
 		fmt.Printf("\n[%s] %s\n", role, msg.Timestamp.Format("15:04:05"))
-		fmt.Println(msg.Content)
+		if msg.Content != "" {
+			fmt.Println(msg.Content)
+		}
+		if hideTools {
+			continue
+		}
+		for _, call := range msg.ToolCalls {
+			fmt.Printf("  %s %s\n", toolCallMarker(call), session.FormatToolCall(call))
+			if showOutput && call.Output != "" {
+				for _, line := range strings.Split(strings.TrimRight(call.Output, "\n"), "\n") {
+					fmt.Printf("      %s\n", line)
+				}
+			}
+		}
 	}
 
 	return nil
 }
 
+// toolCallMarker returns a status marker for a tool call line
+func toolCallMarker(call session.ToolCall) string {
+	switch call.Status {
+	case session.ToolStatusSuccess:
+		return ui.Success("✓")
+	case session.ToolStatusError:
+		return ui.Error("✗")
+	default:
+		return "•"
+	}
+}
+
 func runSessionSync(cmd *cobra.Command, args []string) error {
 	jsonOutput, _ := cmd.Flags().GetBool("json")
 	statusOnly, _ := cmd.Flags().GetBool("status")
+	force, _ := cmd.Flags().GetBool("force")
 
 	queue := session.NewQueue()
 
@@ -366,17 +417,23 @@ func runSessionSync(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to list queue: %w", err)
 		}
+		daemon, err := session.LoadDaemonStatus()
+		if err != nil {
+			return err
+		}
 
 		if jsonOutput {
 			result := map[string]interface{}{
 				"queued_count": len(entries),
 				"entries":      entries,
+				"daemon":       daemonStatusJSON(daemon),
 			}
 			data, _ := json.MarshalIndent(result, "", "  ")
 			fmt.Println(string(data))
 			return nil
 		}
 
+		printDaemonStatus(daemon)
 		if len(entries) == 0 {
 			fmt.Println("No sessions in queue")
 			return nil
@@ -394,48 +451,132 @@ func runSessionSync(cmd *cobra.Command, args []string) error {
 			if len(sessionIDShort) > 8 {
 				sessionIDShort = sessionIDShort[:8]
 			}
-			fmt.Printf("  %s  %s  (retries: %d)\n", sessionIDShort, commit, e.RetryCount)
+			retry := fmt.Sprintf("retries: %d", e.RetryCount)
+			switch {
+			case e.RetryCount >= session.MaxRetries:
+				retry += ", gave up"
+			case e.NextRetry != nil && time.Now().Before(*e.NextRetry):
+				retry += ", next " + e.NextRetry.Format("15:04:05")
+			}
+			fmt.Printf("  %s  %s  (%s)\n", sessionIDShort, commit, retry)
+			if e.LastError != "" {
+				fmt.Printf("      last error: %s\n", e.LastError)
+			}
 		}
 		return nil
 	}
 
-	// Get access token
-	accessToken, err := auth.GetValidAccessToken()
+	backend, projectID, err := openSessionBackend()
 	if err != nil {
-		return fmt.Errorf("authentication required: run 'sl auth login' first\n\nDetails: %w", err)
+		return err
+	}
+
+	if force {
+		if err := queue.ResetRetries(projectID); err != nil {
+			return fmt.Errorf("failed to reset retries: %w", err)
+		}
 	}
 
-	uploaded, failed, skipped, errors := queue.ProcessQueue(accessToken)
+	result := queue.ProcessQueue(backend, projectID)
+
+	pruned := 0
+	if report := autoPrune(projectID); report != nil {
+		pruned = len(report.Pruned)
+	}
 
 	if jsonOutput {
-		result := map[string]interface{}{
-			"uploaded": uploaded,
-			"failed":   failed,
-			"skipped":  skipped,
-			"errors":   errorsToStrings(errors),
+		out := map[string]interface{}{
+			"uploaded": result.Uploaded,
+			"failed":   result.Failed,
+			"skipped":  result.Skipped,
+			"deferred": result.Deferred,
+			"offline":  result.Offline,
+			"pruned":   pruned,
+			"errors":   errorsToStrings(result.Errors),
 		}
-		data, _ := json.MarshalIndent(result, "", "  ")
+		data, _ := json.MarshalIndent(out, "", "  ")
 		fmt.Println(string(data))
 		return nil
 	}
 
-	total := uploaded + failed + skipped
+	total := result.Uploaded + result.Failed + result.Skipped + result.Deferred
 	if total == 0 {
 		fmt.Println("No queued sessions to sync")
 		return nil
 	}
 
-	fmt.Printf("Uploaded %d session(s)\n", uploaded)
-	if failed > 0 {
-		fmt.Printf("%d session(s) failed (will retry on next sync)\n", failed)
+	fmt.Printf("Uploaded %d session(s)\n", result.Uploaded)
+	if result.Offline {
+		fmt.Println("Storage backend unreachable; sessions stay queued")
+	}
+	if result.Failed > 0 {
+		fmt.Printf("%d session(s) failed (will retry with backoff)\n", result.Failed)
 	}
-	if skipped > 0 {
-		fmt.Printf("%d session(s) skipped (max retries reached)\n", skipped)
+	if result.Deferred > 0 {
+		fmt.Printf("%d session(s) waiting to retry (use --force to retry now)\n", result.Deferred)
+	}
+	if result.Skipped > 0 {
+		fmt.Printf("%d session(s) skipped (max retries reached; use --force to retry)\n", result.Skipped)
+	}
+	if pruned > 0 {
+		fmt.Printf("Pruned %d old session(s) from the local cache\n", pruned)
 	}
 
 	return nil
 }
 
+// daemonStatusJSON returns the daemon status for JSON output, with whether
+// it is running
+func daemonStatusJSON(status *session.DaemonStatus) interface{} {
+	if status == nil {
+		return map[string]interface{}{"running": false}
+	}
+	return struct {
+		Running bool `json:"running"`
+		*session.DaemonStatus
+	}{status.Running(), status}
+}
+
+// printDaemonStatus prints a one-line summary of the upload daemon
+func printDaemonStatus(status *session.DaemonStatus) {
+	if !status.Running() {
+		fmt.Println("Daemon: not running (start with: sl session daemon)")
+		return
+	}
+	line := fmt.Sprintf("Daemon: running (pid %d), %d uploaded", status.PID, status.Uploaded)
+	if status.Offline {
+		line += ", offline"
+	}
+	if status.NextRun != nil {
+		line += ", next check " + status.NextRun.Format("15:04:05")
+	}
+	fmt.Println(line)
+	if status.LastError != "" {
+		fmt.Printf("  last error: %s\n", status.LastError)
+	}
+}
+
+// openSessionBackend returns the storage backend configured in specledger.yaml
+// and the project ID sessions are stored under.
+func openSessionBackend() (session.Backend, string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	cfg := session.LoadStorageConfig(cwd)
+	projectID, err := session.ResolveProjectID(cwd, cfg.Backend)
+	if err != nil {
+		return nil, "", fmt.Errorf("project not configured: %w\n\nHint: Ensure specledger.yaml has 'project.id' set, or the project is registered in Supabase with matching git remote.", err)
+	}
+
+	backend, err := session.OpenBackendWithConfig(cwd, cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	return backend, projectID, nil
+}
+
 func formatSize(bytes int64) string {
 	const unit = 1024
 	if bytes < unit {
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/specledger/specledger/pkg/cli/session"
+	"github.com/spf13/cobra"
+)
+
+// VarSessionDaemonCmd drains the session upload queue in the background
+var VarSessionDaemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Upload queued sessions in the background",
+	Long: `Drain the session upload queue continuously.
+
+The daemon checks the queue every --interval and uploads the sessions whose
+retry backoff has elapsed. While the storage backend is unreachable it
+checks again with exponential backoff (up to 15 minutes) without counting
+the attempts against the retry limit. Each session's last error is kept in
+the queue; see 'sl session sync --status'.
+
+The queue is shared by all projects: each session is uploaded to the storage
+backend configured in the specledger.yaml of the project it was captured in.
+Sessions queued by older versions use the directory the daemon runs in. Only
+one daemon runs per user; it stops on Ctrl-C or SIGTERM.
+
+Examples:
+  sl session daemon                  # Run in the foreground
+  sl session daemon --interval 5m    # Check less often
+  sl session daemon --once           # Single pass, e.g. from cron
+  nohup sl session daemon >~/.specledger/daemon.log 2>&1 &`,
+	RunE: runSessionDaemon,
+}
+
+func init() {
+	VarSessionDaemonCmd.Flags().Duration("interval", session.DefaultDaemonInterval, "How often to check the queue")
+	VarSessionDaemonCmd.Flags().Bool("once", false, "Drain the queue once and exit")
+}
+
+func runSessionDaemon(cmd *cobra.Command, args []string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	once, _ := cmd.Flags().GetBool("once")
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if !once {
+		fmt.Printf("Session upload daemon started (pid %d, every %s)\n", os.Getpid(), interval)
+	}
+	return session.RunDaemon(ctx, session.DaemonOptions{
+		Workdir:  cwd,
+		Interval: interval,
+		Once:     once,
+		Logf: func(format string, args ...interface{}) {
+			fmt.Printf("%s %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+		},
+	})
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/cli/session"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/spf13/cobra"
+)
+
+// VarSessionExportCmd exports a session as a readable transcript
+var VarSessionExportCmd = &cobra.Command{
+	Use:   "export <session-id|commit-hash|task-id>",
+	Short: "Export a session as Markdown or HTML",
+	Long: `Export a session as a readable transcript, e.g. to attach to a pull
+request or design doc.
+
+The transcript starts with a header linking the commit and branch (when the
+origin remote is a web host such as GitHub) and naming the task. Messages
+show their role and time; tool calls are collapsible blocks containing the
+command output.
+
+Formats (--format):
+  md    Markdown with <details> blocks, as rendered by GitHub (default)
+  html  Self-contained HTML page
+
+Examples:
+  sl session export abc1234                       # Markdown to stdout
+  sl session export abc1234 -o session.md         # Write to a file
+  sl session export SL-a1b2c3 --format html -o session.html
+  sl session export abc1234 --no-tools            # Conversation only`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionExport,
+}
+
+func init() {
+	VarSessionExportCmd.Flags().String("format", session.ExportMarkdown, "Output format ("+strings.Join(session.ExportFormats, ", ")+")")
+	VarSessionExportCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+	VarSessionExportCmd.Flags().Bool("no-tools", false, "Omit tool calls")
+}
+
+func runSessionExport(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	noTools, _ := cmd.Flags().GetBool("no-tools")
+
+	backend, projectID, err := openSessionBackend()
+	if err != nil {
+		return err
+	}
+
+	sessionMeta, err := session.FindSession(backend, projectID, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to look up session: %w", err)
+	}
+	if sessionMeta == nil {
+		return fmt.Errorf("session not found: %s", args[0])
+	}
+
+	compressed, err := backend.Download(sessionMeta.StoragePath)
+	if err != nil {
+		return fmt.Errorf("failed to download session: %w", err)
+	}
+	session.CacheSession(sessionMeta, compressed)
+
+	contentJSON, err := session.Decompress(compressed)
+	if err != nil {
+		return fmt.Errorf("failed to decompress session: %w", err)
+	}
+	var content session.SessionContent
+	if err := json.Unmarshal(contentJSON, &content); err != nil {
+		return fmt.Errorf("failed to parse session content: %w", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	rendered, err := session.Export(&content, format, session.ExportOptions{
+		RepoURL: session.RepoWebURL(cwd),
+		NoTools: noTools,
+	})
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		fmt.Print(rendered)
+		return nil
+	}
+	if err := os.WriteFile(output, []byte(rendered), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Println(ui.Success(fmt.Sprintf("Exported session to %s", output)))
+	return nil
+}
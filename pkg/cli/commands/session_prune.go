@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/specledger/specledger/pkg/cli/session"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/spf13/cobra"
+)
+
+// VarSessionPruneCmd applies the retention policy to the local session cache
+var VarSessionPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove old sessions from the local cache",
+	Long: `Apply the session retention policy to the project's sessions in the local
+cache (~/.specledger/<project-id>). Other projects' sessions are left to
+their own policy.
+
+Sessions older than max_age are removed, then the oldest sessions until the
+project's cache fits in max_size. Sessions still waiting for upload are kept unless
+uploaded_only is false. Capture logs (capture-errors.log and the
+sl-capture-debug.log temp file) are rotated when they exceed 1MB, and the
+sl-capture-raw.log temp file left by older versions is removed.
+
+The policy is read from specledger/specledger.yaml:
+
+  session:
+    retention:
+      max_age: 30d          # default 30d
+      max_size: 500MB       # default 500MB
+      uploaded_only: true   # default true
+
+Pruning also runs after 'sl session sync' and each daemon upload.
+
+Examples:
+  sl session prune             # Apply the policy
+  sl session prune --dry-run   # Show what would be removed
+  sl session prune --json      # Output the report as JSON`,
+	RunE: runSessionPrune,
+}
+
+func init() {
+	VarSessionPruneCmd.Flags().Bool("dry-run", false, "Show what would be removed without removing anything")
+	VarSessionPruneCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+func runSessionPrune(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	policy, err := session.LoadRetentionPolicy(cwd)
+	if err != nil {
+		return err
+	}
+	projectID, err := session.ResolveProjectID(cwd, session.LoadStorageConfig(cwd).Backend)
+	if err != nil {
+		return fmt.Errorf("project not configured: %w", err)
+	}
+
+	report, err := session.Prune(projectID, policy, dryRun)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	verb := "Removed"
+	if dryRun {
+		verb = "Would remove"
+	}
+	for _, s := range report.Pruned {
+		queued := ""
+		if s.Queued {
+			queued = ", not uploaded"
+		}
+		fmt.Printf("  %s  %s/%s  %s  (%s%s)\n", s.ModTime.Format("2006-01-02"), s.SpecKey, shortIdentifier(s.Identifier), formatSize(s.SizeBytes), s.Reason, queued)
+	}
+	for _, path := range report.RotatedLogs {
+		if dryRun {
+			fmt.Printf("  would rotate %s\n", path)
+		} else {
+			fmt.Printf("  rotated %s\n", path)
+		}
+	}
+	for _, path := range report.RemovedLogs {
+		if dryRun {
+			fmt.Printf("  would remove %s\n", path)
+		} else {
+			fmt.Printf("  removed %s\n", path)
+		}
+	}
+
+	summary := fmt.Sprintf("%s %d session(s), %s; %d kept (%s)",
+		verb, len(report.Pruned), formatSize(report.FreedBytes), report.KeptCount, formatSize(report.KeptBytes))
+	if len(report.Pruned) == 0 {
+		summary = fmt.Sprintf("Nothing to prune; %d session(s) cached (%s)", report.KeptCount, formatSize(report.KeptBytes))
+	}
+	fmt.Println(ui.Success(summary))
+	return nil
+}
+
+// autoPrune applies the project's retention policy after uploads. Failures
+// are warnings: a full cache is no reason to fail a sync.
+func autoPrune(projectID string) *session.PruneReport {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	policy, err := session.LoadRetentionPolicy(cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: session retention not applied: %v\n", err)
+		return nil
+	}
+	report, err := session.Prune(projectID, policy, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: session prune failed: %v\n", err)
+		return nil
+	}
+	return report
+}
+
+// shortIdentifier shortens commit hashes for display
+func shortIdentifier(identifier string) string {
+	if len(identifier) == 40 {
+		return identifier[:7]
+	}
+	return identifier
+}
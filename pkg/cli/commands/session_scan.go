@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/cli/session"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/spf13/cobra"
+)
+
+// VarSessionScanCmd reports what redaction would remove from a transcript
+var VarSessionScanCmd = &cobra.Command{
+	Use:   "scan [file...]",
+	Short: "Dry-run secret redaction on a transcript or files",
+	Long: `Show which secrets would be redacted before a session is uploaded.
+
+Without arguments, scans the most recent transcript of the agent (--agent)
+for the current directory, or the transcript of --session. With file
+arguments, scans the files instead, e.g. a .env file.
+
+Nothing is uploaded or modified. Findings show the rule that matched and a
+redacted excerpt; the secret values themselves are never printed.
+
+Redaction rules:
+  - built-in patterns for common key formats (AWS, GitHub, Anthropic,
+    OpenAI, Slack, Stripe, Google, JWTs, bearer tokens, private keys,
+    URL passwords and KEY=value assignments of secret-looking names)
+  - the auth token and API keys from the resolved agent config
+  - project patterns in specledger/specledger.yaml:
+
+      session:
+        redaction:
+          patterns:
+            - 'ACME-[0-9a-f]{32}'
+
+Examples:
+  sl session scan                    # Scan the latest Claude Code transcript
+  sl session scan --agent codex      # Scan the latest Codex rollout
+  sl session scan .env config.json   # Scan files`,
+	RunE: runSessionScan,
+}
+
+func init() {
+	VarSessionScanCmd.Flags().String("agent", session.AgentClaude, "Agent whose transcript to scan ("+strings.Join(session.TranscriptAgents(), ", ")+")")
+	VarSessionScanCmd.Flags().String("session", "", "Agent session ID of the transcript to scan (default: most recent)")
+	VarSessionScanCmd.Flags().Bool("json", false, "Output findings as JSON")
+}
+
+// sessionScanReport is the JSON output of sl session scan
+type sessionScanReport struct {
+	Sources  []string                   `json:"sources"`
+	Total    int                        `json:"total"`
+	Counts   session.RedactionCounts    `json:"counts"`
+	Findings []session.RedactionFinding `json:"findings"`
+}
+
+func runSessionScan(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	agentName, _ := cmd.Flags().GetString("agent")
+	sessionID, _ := cmd.Flags().GetString("session")
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	redactor, err := session.LoadRedactor(cwd)
+	if err != nil {
+		return err
+	}
+
+	report := &sessionScanReport{Counts: make(session.RedactionCounts), Findings: []session.RedactionFinding{}}
+	add := func(f *session.RedactionFinding) {
+		if f == nil {
+			return
+		}
+		report.Findings = append(report.Findings, *f)
+		for rule, n := range f.Counts {
+			report.Counts[rule] += n
+		}
+	}
+
+	if len(args) > 0 {
+		for _, path := range args {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			report.Sources = append(report.Sources, path)
+			add(redactor.Scan(path, string(data)))
+		}
+	} else {
+		adapter, err := session.GetTranscriptAdapter(agentName)
+		if err != nil {
+			return err
+		}
+		path, err := adapter.FindTranscript(sessionID, cwd)
+		if err != nil {
+			return fmt.Errorf("no %s transcript found: %w", adapter.Name(), err)
+		}
+		messages, _, err := adapter.ReadDelta(path, 0)
+		if err != nil {
+			return fmt.Errorf("failed to read transcript: %w", err)
+		}
+		report.Sources = append(report.Sources, path)
+		for i, msg := range messages {
+			source := fmt.Sprintf("message %d (%s)", i+1, msg.Role)
+			add(redactor.Scan(source, msg.Content))
+			for _, call := range msg.ToolCalls {
+				add(redactor.Scan(source+" "+call.Name+" command", call.Command))
+				add(redactor.Scan(source+" "+call.Name+" output", call.Output))
+			}
+		}
+	}
+	report.Total = report.Counts.Total()
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, source := range report.Sources {
+		fmt.Printf("Scanned: %s\n", source)
+	}
+	if report.Total == 0 {
+		fmt.Println(ui.Success("No secrets found"))
+		return nil
+	}
+
+	fmt.Println()
+	for _, f := range report.Findings {
+		fmt.Printf("%s: %s\n", f.Source, f.Counts)
+		fmt.Printf("  %s\n", f.Excerpt)
+	}
+
+	rules := make([]string, 0, len(report.Counts))
+	for rule := range report.Counts {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	fmt.Printf("\n%d value(s) would be redacted before upload:\n", report.Total)
+	for _, rule := range rules {
+		fmt.Printf("  %-18s %d\n", rule, report.Counts[rule])
+	}
+	return nil
+}
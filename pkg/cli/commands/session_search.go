@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/session"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/spf13/cobra"
+)
+
+// VarSessionSearchCmd searches the content of cached sessions
+var VarSessionSearchCmd = &cobra.Command{
+	Use:   "search <text>",
+	Short: "Full-text search over captured sessions",
+	Long: `Search the messages of captured sessions, e.g. to find the conversation
+where a decision was made.
+
+Searches the local session cache in ~/.specledger/<project-id>/, which holds
+sessions captured, synced or retrieved on this machine. Use --fetch to first
+download the project's other sessions from the storage backend.
+
+Sessions are indexed once into a persistent index
+(~/.specledger/<project-id>/.search-index.json) that is updated
+incrementally on each search. A session matches when one of its messages
+contains every word of the query (case-insensitive).
+
+Examples:
+  sl session search "retry policy"                # Search all cached sessions
+  sl session search migration --feature 012-auth  # Only one feature branch
+  sl session search "rate limit" --since 14d      # Captured in the last 14 days
+  sl session search supabase --since 2026-01-01   # Captured since a date
+  sl session search redis --fetch                 # Download missing sessions first
+  sl session search redis --json                  # Output as JSON`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSessionSearch,
+}
+
+func init() {
+	VarSessionSearchCmd.Flags().String("feature", "", "Only search sessions of this feature branch")
+	VarSessionSearchCmd.Flags().String("since", "", "Only search sessions captured since a date (YYYY-MM-DD) or duration (e.g. 7d, 12h)")
+	VarSessionSearchCmd.Flags().Int("limit", 20, "Maximum number of sessions to return")
+	VarSessionSearchCmd.Flags().Bool("fetch", false, "Download sessions missing from the local cache before searching")
+	VarSessionSearchCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+func runSessionSearch(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
+	featureBranch, _ := cmd.Flags().GetString("feature")
+	sinceValue, _ := cmd.Flags().GetString("since")
+	limit, _ := cmd.Flags().GetInt("limit")
+	fetch, _ := cmd.Flags().GetBool("fetch")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	var since time.Time
+	if sinceValue != "" {
+		var err error
+		if since, err = parseSince(sinceValue, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	cfg := session.LoadStorageConfig(cwd)
+	projectID, err := session.ResolveProjectID(cwd, cfg.Backend)
+	if err != nil {
+		return fmt.Errorf("project not configured: %w", err)
+	}
+
+	if fetch {
+		fetched, err := fetchMissingSessions(projectID, featureBranch)
+		if err != nil {
+			return err
+		}
+		if fetched > 0 && !jsonOutput {
+			fmt.Fprintf(os.Stderr, "Fetched %d session(s) into the local cache\n", fetched)
+		}
+	}
+
+	index, err := session.LoadSearchIndex(projectID)
+	if err != nil {
+		return err
+	}
+	indexed, removed, err := index.Refresh()
+	if err != nil {
+		return err
+	}
+	if indexed > 0 || removed > 0 {
+		if err := index.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	results, err := index.Search(query, session.SearchOptions{
+		FeatureBranch: featureBranch,
+		Since:         since,
+		Limit:         limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No sessions match %q (%d cached session(s) searched)\n", query, len(index.Sessions))
+		if !fetch {
+			fmt.Println("Hint: use --fetch to search sessions not yet downloaded to this machine")
+		}
+		return nil
+	}
+
+	for i, r := range results {
+		if i > 0 {
+			fmt.Println()
+		}
+		ref := r.SessionID
+		if len(r.CommitHash) >= 7 {
+			ref = r.CommitHash[:7]
+		} else if r.TaskID != "" {
+			ref = r.TaskID
+		}
+		fmt.Printf("%s  %s  %s  (%d matching message(s))\n",
+			ui.Bold(ref), r.FeatureBranch, r.CapturedAt.Format("2006-01-02 15:04"), r.Matches)
+		for _, s := range r.Snippets {
+			fmt.Printf("  [%s] %s\n", strings.ToUpper(s.Role), s.Text)
+		}
+	}
+	fmt.Printf("\n%d session(s) found. View one with: sl session get <commit>\n", len(results))
+	return nil
+}
+
+// fetchMissingSessions downloads the project's sessions that aren't in the
+// local cache yet
+func fetchMissingSessions(projectID, featureBranch string) (int, error) {
+	backend, _, err := openSessionBackend()
+	if err != nil {
+		return 0, err
+	}
+	sessions, err := backend.Query(&session.QueryOptions{
+		ProjectID:     projectID,
+		FeatureBranch: featureBranch,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query sessions: %w", err)
+	}
+
+	fetched := 0
+	for i := range sessions {
+		s := &sessions[i]
+		identifier := session.LocalIdentifier(s.CommitHash, s.TaskID, s.ID)
+		if _, err := os.Stat(session.GetSessionPath(s.ProjectID, s.FeatureBranch, identifier)); err == nil {
+			continue
+		}
+		compressed, err := backend.Download(s.StoragePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to download session %s: %v\n", s.ID, err)
+			continue
+		}
+		session.CacheSession(s, compressed)
+		fetched++
+	}
+	return fetched, nil
+}
+
+// parseSince parses a --since value: a date (YYYY-MM-DD), an RFC 3339
+// timestamp, or a duration before now such as 7d, 2w or 12h
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if d, err := metadata.ParseAge(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since value %q: use a date (YYYY-MM-DD) or a duration such as 7d or 12h", value)
+}
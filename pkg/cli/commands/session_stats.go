@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/specledger/specledger/pkg/cli/session"
+	"github.com/spf13/cobra"
+)
+
+// VarSessionStatsCmd reports AI session usage per feature and issue
+var VarSessionStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Session statistics per feature branch and issue",
+	Long: `Aggregate captured sessions per feature branch and per issue.
+
+For each feature branch: sessions, messages, sizes, authors, and how many of
+the branch's commits (those not on the default branch) have a session, i.e.
+how much of the feature was agent-assisted. Commit counts need the branch to
+exist in the local clone; otherwise they show as "-".
+
+For each issue: the sessions linked to it (see 'sl issue show').
+
+Examples:
+  sl session stats                       # All features
+  sl session stats --feature 012-auth    # One feature
+  sl session stats --since 30d           # Sessions of the last 30 days
+  sl session stats --json                # JSON (for scripts/AI)
+  sl session stats --csv > usage.csv     # CSV, one row per feature`,
+	RunE: runSessionStats,
+}
+
+func init() {
+	VarSessionStatsCmd.Flags().String("feature", "", "Only include this feature branch")
+	VarSessionStatsCmd.Flags().String("since", "", "Only include sessions since a date (YYYY-MM-DD) or duration (e.g. 30d)")
+	VarSessionStatsCmd.Flags().Bool("json", false, "Output as JSON")
+	VarSessionStatsCmd.Flags().Bool("csv", false, "Output features as CSV")
+}
+
+func runSessionStats(cmd *cobra.Command, args []string) error {
+	featureBranch, _ := cmd.Flags().GetString("feature")
+	sinceValue, _ := cmd.Flags().GetString("since")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	csvOutput, _ := cmd.Flags().GetBool("csv")
+
+	opts := &session.QueryOptions{FeatureBranch: featureBranch}
+	var since time.Time
+	if sinceValue != "" {
+		var err error
+		if since, err = parseSince(sinceValue, time.Now()); err != nil {
+			return err
+		}
+		opts.StartDate = &since
+	}
+
+	backend, projectID, err := openSessionBackend()
+	if err != nil {
+		return err
+	}
+	opts.ProjectID = projectID
+
+	sessions, err := backend.Query(opts)
+	if err != nil {
+		return fmt.Errorf("failed to query sessions: %w", err)
+	}
+
+	report := session.ComputeStats(sessions)
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	for _, f := range report.Features {
+		if commits, err := session.BranchCommits(cwd, f.FeatureBranch, since); err == nil {
+			f.AddBranchCommits(commits)
+		}
+	}
+
+	switch {
+	case jsonOutput:
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	case csvOutput:
+		return writeStatsCSV(report)
+	}
+
+	if report.Sessions == 0 {
+		fmt.Println("No sessions found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FEATURE\tSESSIONS\tMESSAGES\tSIZE\tCOMMITS W/ SESSION\tW/O\tASSISTED\tAUTHORS\tLAST SESSION")
+	for _, f := range report.Features {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%d\t%s\t%s\t%d\t%s\n",
+			f.FeatureBranch, f.Sessions, f.Messages, formatSize(f.SizeBytes), f.CommitsWithSessions,
+			unknownIfNegative(f.CommitsWithoutSessions()), formatAssisted(f.AssistedPercent()),
+			len(f.Authors), f.LastSession.Format("2006-01-02"))
+	}
+	w.Flush()
+
+	if len(report.Issues) > 0 {
+		fmt.Println()
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ISSUE\tFEATURE\tSESSIONS\tMESSAGES\tCOMMITS\tAUTHORS")
+		for _, issue := range report.Issues {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\n",
+				issue.TaskID, issue.FeatureBranch, issue.Sessions, issue.Messages, issue.Commits, strings.Join(issue.Authors, ", "))
+		}
+		w.Flush()
+	}
+
+	fmt.Printf("\n%d session(s), %d message(s), %d author(s)\n", report.Sessions, report.Messages, len(report.Authors))
+	return nil
+}
+
+// writeStatsCSV writes one row per feature branch
+func writeStatsCSV(report *session.StatsReport) error {
+	w := csv.NewWriter(os.Stdout)
+	_ = w.Write([]string{"feature_branch", "sessions", "messages", "size_bytes", "raw_size_bytes",
+		"commits_with_sessions", "commits_without_sessions", "total_commits", "assisted_percent",
+		"authors", "first_session", "last_session"})
+	for _, f := range report.Features {
+		assisted := ""
+		if p := f.AssistedPercent(); p >= 0 {
+			assisted = strconv.FormatFloat(p, 'f', 1, 64)
+		}
+		_ = w.Write([]string{
+			f.FeatureBranch,
+			strconv.Itoa(f.Sessions),
+			strconv.Itoa(f.Messages),
+			strconv.FormatInt(f.SizeBytes, 10),
+			strconv.FormatInt(f.RawSizeBytes, 10),
+			strconv.Itoa(f.CommitsWithSessions),
+			csvCount(f.CommitsWithoutSessions()),
+			csvCount(f.TotalCommits),
+			assisted,
+			strings.Join(f.Authors, ";"),
+			f.FirstSession.Format(time.RFC3339),
+			f.LastSession.Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// csvCount leaves unknown (negative) counts empty
+func csvCount(n int) string {
+	if n < 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func unknownIfNegative(n int) string {
+	if n < 0 {
+		return "-"
+	}
+	return strconv.Itoa(n)
+}
+
+func formatAssisted(p float64) string {
+	if p < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", p)
+}
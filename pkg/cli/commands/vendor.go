@@ -2,203 +2,176 @@ package commands
 
 import (
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
-	"strings"
+	"time"
 
-	"github.com/specledger/specledger/internal/spec"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/deps"
 	"github.com/spf13/cobra"
 )
 
-// VarVendorCmd represents the vendor command
-var VarVendorCmd = &cobra.Command{
-	Use:   "vendor",
-	Short: "Vendor dependencies for offline use",
-}
-
-// VarVendorAllCmd represents the vendor all command
-var VarVendorAllCmd = &cobra.Command{
-	Use:   "vendor --output <path>",
-	Short: "Copy all dependencies to vendor directory",
-	Long:  `Copy all external dependencies to the local vendor directory for offline use.`,
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  runVendorAll,
-}
-
-// VarVendorUpdateCmd represents the vendor update command
-var VarVendorUpdateCmd = &cobra.Command{
-	Use:   "update [--vendor-path <path>] [--force]",
-	Short: "Update vendored dependencies",
-	RunE:  runVendorUpdate,
-}
-
-// VarCleanCmd represents the clean command
-var VarCleanCmd = &cobra.Command{
-	Use:   "clean",
-	Short: "Remove vendored dependencies",
-	RunE:  runVendorClean,
+// VarDepsVendorCmd represents the deps vendor command
+var VarDepsVendorCmd = &cobra.Command{
+	Use:   "vendor [alias...]",
+	Short: "Copy resolved dependency artifacts into the repository",
+	Long: `Copy the artifacts of resolved dependencies into <artifact_path>/vendor/<alias>/
+so builds can run without network access.
+
+Only each dependency's artifact_path is copied, at the commit pinned in
+specledger.yaml. A vendor.yaml manifest records the commit of every vendored
+dependency; 'sl deps resolve' and 'sl deps link' use vendored copies whose
+commit matches instead of cloning, so CI can run fully offline.
+
+If no alias is given, all resolved dependencies are vendored.`,
+	Example: `  sl deps vendor              # Vendor all resolved dependencies
+  sl deps vendor api          # Vendor a single dependency
+  sl deps vendor --clean      # Remove the vendor directory`,
+	SilenceUsage: true,
+	RunE:         runVendorDependencies,
 }
 
 func init() {
-	VarVendorCmd.AddCommand(VarVendorAllCmd, VarVendorUpdateCmd, VarCleanCmd)
-
-	VarVendorAllCmd.Flags().StringP("output", "o", "github.com/specledger/specledger/vendor", "Output vendor directory path")
-	VarVendorUpdateCmd.Flags().StringP("vendor-path", "p", "github.com/specledger/specledger/vendor", "Vendor directory path")
-	VarVendorUpdateCmd.Flags().BoolP("force", "f", false, "Force update all vendored specs")
+	VarDepsVendorCmd.Flags().Bool("clean", false, "Remove vendored dependencies instead of vendoring")
 }
 
-func runVendorAll(cmd *cobra.Command, args []string) error {
-	vendorPath, _ := cmd.Flags().GetString("output")
-	force, _ := cmd.Flags().GetBool("force")
+func runVendorDependencies(cmd *cobra.Command, args []string) error {
+	clean, _ := cmd.Flags().GetBool("clean")
 
-	// Read lockfile
-	lockfile, err := spec.ReadLockfile("github.com/specledger/specledger/spec.sum")
+	projectDir, err := findProjectRoot()
 	if err != nil {
-		return fmt.Errorf("failed to read lockfile: %w", err)
+		return fmt.Errorf("failed to find project root: %w", err)
 	}
 
-	if len(lockfile.Entries) == 0 {
-		return fmt.Errorf("no vendored specs found. Run 'sl deps resolve' first")
+	meta, err := metadata.LoadFromProject(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
 	}
 
-	fmt.Printf("Vendoring %d spec(s) to %s...\n", len(lockfile.Entries), vendorPath)
+	vendorDir := deps.VendorDir(projectDir, meta.GetArtifactPath())
 
-	// Create vendor directory
-	if err := os.MkdirAll(vendorPath, 0755); err != nil {
-		return fmt.Errorf("failed to create vendor directory: %w", err)
+	if clean {
+		return cleanVendorDir(vendorDir, args)
 	}
 
-	// Vendor each entry
-	for _, entry := range lockfile.Entries {
-		if err := vendorEntry(entry, vendorPath, force); err != nil {
-			return fmt.Errorf("failed to vendor %s: %w", entry.RepositoryURL, err)
-		}
+	if len(meta.Dependencies) == 0 {
+		ui.PrintWarning("No dependencies to vendor")
+		return nil
 	}
 
-	fmt.Printf("Successfully vendored %d spec(s)\n", len(lockfile.Entries))
-	return nil
-}
-
-func runVendorUpdate(cmd *cobra.Command, args []string) error {
-	vendorPath, _ := cmd.Flags().GetString("vendor-path")
-	force, _ := cmd.Flags().GetBool("force")
-
-	// Read lockfile
-	lockfile, err := spec.ReadLockfile("github.com/specledger/specledger/spec.sum")
+	manifest, err := deps.LoadVendorManifest(vendorDir)
 	if err != nil {
-		return fmt.Errorf("failed to read lockfile: %w", err)
-	}
-
-	if len(lockfile.Entries) == 0 {
-		return fmt.Errorf("no vendored specs found. Run 'sl deps resolve' first")
+		return err
 	}
 
-	fmt.Printf("Updating %d vendored spec(s) in %s...\n", len(lockfile.Entries), vendorPath)
+	ui.PrintSection("Vendoring Dependencies")
+	fmt.Printf("Copying artifacts to %s\n", ui.Bold(vendorDir))
+	fmt.Println()
 
-	// Update each entry
-	for _, entry := range lockfile.Entries {
-		if err := vendorEntry(entry, vendorPath, force); err != nil {
-			return fmt.Errorf("failed to update %s: %w", entry.RepositoryURL, err)
-		}
+	selected := make(map[string]bool, len(args))
+	for _, a := range args {
+		selected[a] = true
 	}
 
-	fmt.Printf("Successfully updated %d vendored spec(s)\n", len(lockfile.Entries))
-	return nil
-}
+	vendoredCount := 0
+	attempted := 0
+	for _, dep := range meta.Dependencies {
+		if len(selected) > 0 && !selected[dep.Alias] {
+			continue
+		}
+		if deps.IsLocalSource(dep.URL) {
+			fmt.Printf("  %s %s %s\n", ui.Gray("-"), ui.Cyan(dep.Alias), ui.Gray("local source, nothing to vendor"))
+			continue
+		}
+		attempted++
 
-func runVendorClean(cmd *cobra.Command, args []string) error {
-	vendorPath, _ := cmd.Flags().GetString("vendor-path")
+		if err := vendorDependency(dep, vendorDir); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Skipping %s: %v", dep.Alias, err))
+			continue
+		}
 
-	// Remove vendor directory
-	if err := os.RemoveAll(vendorPath); err != nil {
-		return fmt.Errorf("failed to remove vendor directory: %w", err)
+		manifest.Set(deps.VendoredDependency{
+			Alias:        dep.Alias,
+			URL:          dep.URL,
+			Branch:       dep.Branch,
+			ArtifactPath: dep.ArtifactPath,
+			Commit:       dep.ResolvedCommit,
+			VendoredAt:   time.Now(),
+		})
+		fmt.Printf("  %s %s %s\n", ui.Green("✓"), ui.Cyan(dep.Alias), ui.Gray(dep.ResolvedCommit[:8]))
+		vendoredCount++
 	}
 
-	fmt.Printf("Removed vendored specs from %s\n", vendorPath)
-	return nil
-}
-
-// vendorEntry vendors a single spec entry
-func vendorEntry(entry spec.LockfileEntry, vendorPath string, force bool) error {
-	// Check if vendor directory exists for this dependency
-	depVendorPath := filepath.Join(vendorPath, getVendorDirName(entry))
-
-	// If force is not set and vendor already exists, skip
-	if !force {
-		if _, err := os.Stat(depVendorPath); err == nil {
-			return nil
-		}
+	if len(selected) > 0 && attempted == 0 {
+		return fmt.Errorf("no remote dependency matches the given alias(es)")
 	}
 
-	// Create dependency vendor directory
-	if err := os.MkdirAll(depVendorPath, 0755); err != nil {
-		return fmt.Errorf("failed to create vendor directory: %w", err)
+	if vendoredCount > 0 {
+		if err := manifest.Save(vendorDir); err != nil {
+			return err
+		}
 	}
 
-	// Copy the spec file
-	srcPath := filepath.Join(".spec-cache", getVendorDirName(entry), entry.SpecPath)
-	dstPath := filepath.Join(depVendorPath, entry.SpecPath)
+	fmt.Println()
+	ui.PrintSuccess(fmt.Sprintf("Vendored %d/%d dependencies", vendoredCount, attempted))
+	fmt.Println()
 
-	if _, err := os.Stat(srcPath); err != nil {
-		return fmt.Errorf("spec not found in cache: %s", srcPath)
+	if vendoredCount < attempted {
+		return fmt.Errorf("%d dependencies could not be vendored", attempted-vendoredCount)
 	}
-
-	if err := copyFile(srcPath, dstPath); err != nil {
-		return fmt.Errorf("failed to copy spec file: %w", err)
-	}
-
 	return nil
 }
 
-// getVendorDirName generates a vendor directory name from the repository URL
-func getVendorDirName(entry spec.LockfileEntry) string {
-	// Use Branch field if it looks like an alias (starts with #)
-	if entry.Branch != "" && strings.HasPrefix(entry.Branch, "#") {
-		return entry.Branch
+// vendorDependency copies a single dependency from the cache after checking
+// that the cached checkout matches the commit pinned in specledger.yaml.
+func vendorDependency(dep metadata.Dependency, vendorDir string) error {
+	if dep.ResolvedCommit == "" {
+		return fmt.Errorf("not resolved (run 'sl deps resolve' first)")
 	}
 
-	// Extract repo name from URL
-	parts := strings.Split(entry.RepositoryURL, "/")
-	if len(parts) > 0 {
-		repoName := parts[len(parts)-1]
-		repoName = strings.TrimSuffix(repoName, ".git")
-		return repoName
+	cachePath, err := deps.CachePathForDependency(dep.Alias, dep.URL)
+	if err != nil {
+		return err
 	}
 
-	return "unknown"
-}
+	repo, err := deps.OpenRepository(cachePath)
+	if err != nil {
+		return fmt.Errorf("not cached (run 'sl deps resolve' first)")
+	}
 
-// copyFile copies a file from src to dst
-func copyFile(src, dst string) error {
-	input, err := os.ReadFile(src)
+	head, err := deps.ResolveHead(repo)
 	if err != nil {
 		return err
 	}
+	if head != dep.ResolvedCommit {
+		return fmt.Errorf("cache is at %s but specledger.yaml pins %s (run 'sl deps resolve')", head[:8], dep.ResolvedCommit[:8])
+	}
 
-	// #nosec G306 -- vendored spec files need to be readable, 0644 is appropriate
-	return os.WriteFile(dst, input, 0644)
+	return deps.VendorDependency(cachePath, vendorDir, dep)
 }
 
-// ListVendorSpecs lists all vendored specs
-func ListVendorSpecs(vendorPath string) ([]string, error) {
-	var specs []string
-
-	err := filepath.WalkDir(vendorPath, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if !d.IsDir() && d.Name() == "spec.md" {
-			relPath, err := filepath.Rel(vendorPath, path)
-			if err != nil {
-				return err
-			}
-			specs = append(specs, relPath)
+// cleanVendorDir removes the whole vendor directory, or only the given aliases.
+func cleanVendorDir(vendorDir string, aliases []string) error {
+	if len(aliases) == 0 {
+		if err := os.RemoveAll(vendorDir); err != nil {
+			return fmt.Errorf("failed to remove vendor directory: %w", err)
 		}
-
+		ui.PrintSuccess(fmt.Sprintf("Removed %s", vendorDir))
 		return nil
-	})
+	}
 
-	return specs, err
+	manifest, err := deps.LoadVendorManifest(vendorDir)
+	if err != nil {
+		return err
+	}
+	for _, alias := range aliases {
+		if err := os.RemoveAll(filepath.Join(vendorDir, alias)); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", alias, err)
+		}
+		if manifest.Remove(alias) {
+			fmt.Printf("  Removed: %s\n", ui.Cyan(alias))
+		}
+	}
+	return manifest.Save(vendorDir)
 }
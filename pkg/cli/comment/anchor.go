@@ -0,0 +1,43 @@
+package comment
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SelectLines returns lines startLine..line (1-based, inclusive) of content.
+// A startLine of 0 selects the single line.
+func SelectLines(content string, startLine, line int) (string, error) {
+	if startLine == 0 {
+		startLine = line
+	}
+	if line < 1 || startLine < 1 {
+		return "", fmt.Errorf("line numbers start at 1")
+	}
+	if startLine > line {
+		return "", fmt.Errorf("start line %d is after line %d", startLine, line)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if line > len(lines) {
+		return "", fmt.Errorf("line %d is past the end of the file (%d lines)", line, len(lines))
+	}
+
+	return strings.Join(lines[startLine-1:line], "\n"), nil
+}
+
+// ValidateAnchor checks that selectedText occurs within lines startLine..line
+// of content, so the comment points at what the author meant.
+func ValidateAnchor(content, selectedText string, startLine, line int) error {
+	region, err := SelectLines(content, startLine, line)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(region, selectedText) {
+		if startLine == 0 || startLine == line {
+			return fmt.Errorf("selected text not found on line %d", line)
+		}
+		return fmt.Errorf("selected text not found on lines %d-%d", startLine, line)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package comment
+
+import "testing"
+
+func TestSelectLines(t *testing.T) {
+	content := "one\ntwo\nthree\nfour\n"
+
+	tests := []struct {
+		name      string
+		startLine int
+		line      int
+		want      string
+		wantErr   bool
+	}{
+		{name: "single line", line: 2, want: "two"},
+		{name: "range", startLine: 2, line: 4, want: "two\nthree\nfour"},
+		{name: "same start and end", startLine: 3, line: 3, want: "three"},
+		{name: "past end", line: 5, wantErr: true},
+		{name: "zero line", line: 0, wantErr: true},
+		{name: "start after end", startLine: 3, line: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectLines(content, tt.startLine, tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectLines() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("SelectLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAnchor(t *testing.T) {
+	content := "# Spec\nTokens expire after 1h.\nRefresh is automatic.\n"
+
+	if err := ValidateAnchor(content, "expire after 1h", 0, 2); err != nil {
+		t.Errorf("expected anchor on line 2 to be valid: %v", err)
+	}
+	if err := ValidateAnchor(content, "1h.\nRefresh", 2, 3); err != nil {
+		t.Errorf("expected multi-line anchor to be valid: %v", err)
+	}
+	if err := ValidateAnchor(content, "Refresh", 0, 2); err == nil {
+		t.Error("expected error for text on another line")
+	}
+}
@@ -3,6 +3,7 @@ package comment
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,6 +16,9 @@ import (
 
 const clientTimeout = 30 * time.Second
 
+// ErrCommentNotFound is returned when a comment ID does not exist.
+var ErrCommentNotFound = errors.New("comment not found")
+
 type apiProject struct {
 	ID            string `json:"id"`
 	DefaultBranch string `json:"default_branch"`
@@ -229,7 +233,7 @@ func (c *Client) GetChange(specID string) (*apiChange, error) {
 func (c *Client) FetchComments(changeID string) ([]ReviewComment, error) {
 	path := fmt.Sprintf(
 		"/rest/v1/review_comments?change_id=eq.%s&is_resolved=eq.false&parent_comment_id=is.null"+
-			"&select=id,file_path,content,selected_text,line,start_line,author_name,author_email,created_at"+
+			"&select=id,file_path,content,selected_text,line,start_line,author_name,author_email,created_at,updated_at"+
 			"&order=created_at.asc",
 		url.QueryEscape(changeID),
 	)
@@ -252,7 +256,7 @@ func (c *Client) FetchComments(changeID string) ([]ReviewComment, error) {
 func (c *Client) FetchCommentByID(commentID string) (*ReviewComment, error) {
 	path := fmt.Sprintf(
 		"/rest/v1/review_comments?id=eq.%s"+
-			"&select=id,change_id,file_path,content,selected_text,line,start_line,author_name,author_email,is_resolved,created_at",
+			"&select=id,change_id,file_path,content,selected_text,line,start_line,author_name,author_email,is_resolved,created_at,updated_at",
 		url.QueryEscape(commentID),
 	)
 
@@ -269,7 +273,7 @@ func (c *Client) FetchCommentByID(commentID string) (*ReviewComment, error) {
 	}
 
 	if len(comments) == 0 {
-		return nil, fmt.Errorf("comment not found: %s", commentID)
+		return nil, fmt.Errorf("%w: %s", ErrCommentNotFound, commentID)
 	}
 
 	return &comments[0], nil
@@ -324,7 +328,7 @@ func (c *Client) FetchRepliesByParentID(parentID string) ([]ReviewComment, error
 func (c *Client) FetchResolvedComments(changeID string) ([]ReviewComment, error) {
 	path := fmt.Sprintf(
 		"/rest/v1/review_comments?change_id=eq.%s&is_resolved=eq.true&parent_comment_id=is.null"+
-			"&select=id,file_path,content,selected_text,line,start_line,author_name,author_email,created_at"+
+			"&select=id,file_path,content,selected_text,line,start_line,author_name,author_email,created_at,updated_at"+
 			"&order=created_at.asc",
 		url.QueryEscape(changeID),
 	)
@@ -344,6 +348,60 @@ func (c *Client) FetchResolvedComments(changeID string) ([]ReviewComment, error)
 	return comments, nil
 }
 
+// ActivityPage is a page of comment activity returned by FetchActivity.
+type ActivityPage struct {
+	Comments    []ReviewComment // comments and replies, oldest first
+	ETag        string          // pass to the next poll
+	NotModified bool            // nothing changed since the poll with the given ETag
+}
+
+// FetchActivity returns the comments and replies of a change created at or
+// after since (RFC 3339; "" for all), oldest first. etag is the ETag of the
+// previous poll; when the result hasn't changed the server answers 304 and
+// NotModified is set.
+func (c *Client) FetchActivity(changeID, since, etag string) (*ActivityPage, error) {
+	path := fmt.Sprintf(
+		"/rest/v1/review_comments?change_id=eq.%s"+
+			"&select=id,file_path,content,selected_text,line,start_line,parent_comment_id,author_name,author_email,is_resolved,created_at,updated_at"+
+			"&order=created_at.asc",
+		url.QueryEscape(changeID),
+	)
+	if since != "" {
+		path += "&created_at=gte." + url.QueryEscape(since)
+	}
+
+	resp, err := c.DoWithRetry(func(token string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, c.BaseURL+path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("apikey", c.AnonKey)
+		req.Header.Set("Accept", "application/json")
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		return c.HTTPClient.Do(req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("FetchActivity: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return &ActivityPage{ETag: etag, NotModified: true}, nil
+	}
+
+	page := &ActivityPage{ETag: resp.Header.Get("ETag")}
+	if err := ReadJSON(resp, &page.Comments); err != nil {
+		return nil, fmt.Errorf("FetchActivity: %w", err)
+	}
+	for i := range page.Comments {
+		page.Comments[i].ChangeID = changeID
+	}
+	return page, nil
+}
+
 func BuildReplyMap(replies []ReviewComment) ReplyMap {
 	m := make(ReplyMap)
 	for _, r := range replies {
@@ -498,3 +556,72 @@ func (c *Client) ResolveCommentWithReplies(commentID string, replyIDs []string)
 
 	return nil
 }
+
+// NewComment is a top-level review comment to post with CreateComment.
+// Line is the last line of the anchored region and StartLine its first line
+// (nil for single-line comments); both are 1-based.
+type NewComment struct {
+	ChangeID     string
+	FilePath     string
+	Content      string
+	SelectedText string
+	Line         *int
+	StartLine    *int
+}
+
+func (c *Client) CreateComment(nc NewComment) (*ReviewComment, error) {
+	// Load credentials to get author_id (NOT NULL constraint).
+	creds, err := c.AuthProvider.LoadCredentials()
+	if err != nil || creds == nil {
+		return nil, fmt.Errorf("CreateComment: failed to load credentials for author_id")
+	}
+
+	reqURL := fmt.Sprintf("%s/rest/v1/review_comments", c.BaseURL)
+
+	body := map[string]interface{}{
+		"change_id":     nc.ChangeID,
+		"file_path":     nc.FilePath,
+		"author_id":     creds.UserID,
+		"content":       nc.Content,
+		"selected_text": nc.SelectedText,
+		"line":          nc.Line,
+		"start_line":    nc.StartLine,
+	}
+	if creds.UserEmail != "" {
+		body["author_email"] = creds.UserEmail
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("CreateComment: failed to marshal request: %w", err)
+	}
+
+	postFn := func(token string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodPost, reqURL, bytes.NewReader(bodyBytes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("apikey", c.AnonKey)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Prefer", "return=representation")
+
+		return c.HTTPClient.Do(req)
+	}
+
+	resp, err := c.DoWithRetry(postFn)
+	if err != nil {
+		return nil, fmt.Errorf("CreateComment: %w", err)
+	}
+
+	var comments []ReviewComment
+	if err := ReadJSON(resp, &comments); err != nil {
+		return nil, fmt.Errorf("CreateComment: %w", err)
+	}
+
+	if len(comments) == 0 {
+		return nil, fmt.Errorf("CreateComment: no comment returned from API")
+	}
+
+	return &comments[0], nil
+}
@@ -1,6 +1,7 @@
 package comment
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -310,3 +311,64 @@ func TestDoWithRetry_401_NilDiskCreds_ForceRefreshes(t *testing.T) {
 		t.Fatalf("expected 1 refresh call, got %d", mock.refreshCalls)
 	}
 }
+
+func TestCreateComment_PostsAnchoredComment(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/v1/review_comments" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `[{"id":"c1","change_id":"ch1","file_path":"specledger/010-auth/spec.md","line":12,"start_line":10}]`)
+	}))
+	defer srv.Close()
+
+	mock := &mockAuthProvider{creds: &auth.Credentials{UserID: "u1", UserEmail: "dev@example.com"}}
+	client := newTestClient(srv.URL, "token", mock)
+
+	line, startLine := 12, 10
+	created, err := client.CreateComment(NewComment{
+		ChangeID:     "ch1",
+		FilePath:     "specledger/010-auth/spec.md",
+		Content:      "Clarify this",
+		SelectedText: "token lifetime",
+		Line:         &line,
+		StartLine:    &startLine,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if created.ID != "c1" || created.Line == nil || *created.Line != 12 {
+		t.Fatalf("unexpected comment: %+v", created)
+	}
+	if body["author_id"] != "u1" || body["author_email"] != "dev@example.com" {
+		t.Fatalf("expected author fields from credentials, got %v", body)
+	}
+	if body["line"] != float64(12) || body["start_line"] != float64(10) || body["selected_text"] != "token lifetime" {
+		t.Fatalf("expected anchor fields, got %v", body)
+	}
+	if _, ok := body["parent_comment_id"]; ok {
+		t.Fatalf("top-level comment must not have a parent: %v", body)
+	}
+}
+
+func TestCreateComment_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"new row violates row-level security policy"}`)
+	}))
+	defer srv.Close()
+
+	mock := &mockAuthProvider{creds: &auth.Credentials{UserID: "u1"}}
+	client := newTestClient(srv.URL, "token", mock)
+
+	line := 1
+	_, err := client.CreateComment(NewComment{ChangeID: "ch1", FilePath: "spec.md", Content: "x", Line: &line})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
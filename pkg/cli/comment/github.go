@@ -0,0 +1,457 @@
+package comment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// GitHubAPIURL is the base URL of the GitHub REST API.
+const GitHubAPIURL = "https://api.github.com"
+
+// linkNextRe extracts the next page URL from a GitHub Link header.
+var linkNextRe = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// GitHubReviewComment is a pull request review comment as returned by the
+// GitHub REST API (GET /repos/{owner}/{repo}/pulls/{number}/comments).
+type GitHubReviewComment struct {
+	ID                int64      `json:"id"`
+	Path              string     `json:"path"`
+	Line              *int       `json:"line"`
+	StartLine         *int       `json:"start_line"`
+	OriginalLine      *int       `json:"original_line"`
+	OriginalStartLine *int       `json:"original_start_line"`
+	Side              string     `json:"side"`
+	DiffHunk          string     `json:"diff_hunk"`
+	Body              string     `json:"body"`
+	InReplyToID       int64      `json:"in_reply_to_id,omitempty"`
+	User              GitHubUser `json:"user"`
+	HTMLURL           string     `json:"html_url"`
+	CreatedAt         string     `json:"created_at"`
+	UpdatedAt         string     `json:"updated_at"`
+}
+
+// GitHubUser is the author of a review comment.
+type GitHubUser struct {
+	Login string `json:"login"`
+}
+
+// GitHubReviewThread is the resolution state of a review comment thread.
+// Threads only exist in the GraphQL API (PullRequest.reviewThreads).
+type GitHubReviewThread struct {
+	ID          string `json:"id"` // GraphQL node ID
+	IsResolved  bool   `json:"is_resolved"`
+	RootComment int64  `json:"root_comment_id"` // REST ID of the thread's first comment
+}
+
+// GitHubAPI reads, replies to and resolves the review comments of one pull
+// request. GitHubClient talks to the REST API, and to the GraphQL API for
+// threads; GitHubFixture reads a JSON file, for tests and offline runs.
+type GitHubAPI interface {
+	ListReviewComments() ([]GitHubReviewComment, error)
+	ListReviewThreads() ([]GitHubReviewThread, error)
+	CreateReply(commentID int64, body string) (*GitHubReviewComment, error)
+	ResolveThread(threadID string) error
+}
+
+// GitHubClient is a GitHubAPI for a pull request on github.com.
+type GitHubClient struct {
+	BaseURL    string
+	Token      string
+	Owner      string
+	Repo       string
+	PullNumber int
+	HTTPClient *http.Client
+}
+
+// NewGitHubClient returns a client for owner/repo. Set PullNumber, or call
+// FindPullRequest, before listing comments.
+func NewGitHubClient(owner, repo, token string) *GitHubClient {
+	return &GitHubClient{
+		BaseURL:    GitHubAPIURL,
+		Token:      token,
+		Owner:      owner,
+		Repo:       repo,
+		HTTPClient: &http.Client{Timeout: clientTimeout},
+	}
+}
+
+// FindPullRequest selects the open pull request whose head is branch.
+func (c *GitHubClient) FindPullRequest(branch string) (int, error) {
+	params := url.Values{}
+	params.Set("head", c.Owner+":"+branch)
+	params.Set("state", "open")
+
+	var pulls []struct {
+		Number int `json:"number"`
+	}
+	if _, err := c.do(http.MethodGet, c.repoURL("/pulls?"+params.Encode()), nil, &pulls); err != nil {
+		return 0, fmt.Errorf("failed to find pull request: %w", err)
+	}
+	if len(pulls) == 0 {
+		return 0, fmt.Errorf("no open pull request for branch %s in %s/%s", branch, c.Owner, c.Repo)
+	}
+
+	c.PullNumber = pulls[0].Number
+	return c.PullNumber, nil
+}
+
+// ListReviewComments returns every review comment of the pull request,
+// following pagination.
+func (c *GitHubClient) ListReviewComments() ([]GitHubReviewComment, error) {
+	if c.PullNumber == 0 {
+		return nil, fmt.Errorf("no pull request selected")
+	}
+
+	all := []GitHubReviewComment{}
+	next := c.repoURL(fmt.Sprintf("/pulls/%d/comments?per_page=100", c.PullNumber))
+	for next != "" {
+		var page []GitHubReviewComment
+		resp, err := c.do(http.MethodGet, next, nil, &page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list review comments: %w", err)
+		}
+		all = append(all, page...)
+
+		next = ""
+		if m := linkNextRe.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+			next = m[1]
+		}
+	}
+	return all, nil
+}
+
+// CreateReply posts a reply in the thread of a review comment.
+func (c *GitHubClient) CreateReply(commentID int64, body string) (*GitHubReviewComment, error) {
+	if c.PullNumber == 0 {
+		return nil, fmt.Errorf("no pull request selected")
+	}
+
+	var reply GitHubReviewComment
+	path := fmt.Sprintf("/pulls/%d/comments/%d/replies", c.PullNumber, commentID)
+	if _, err := c.do(http.MethodPost, c.repoURL(path), map[string]string{"body": body}, &reply); err != nil {
+		return nil, fmt.Errorf("failed to reply to review comment %d: %w", commentID, err)
+	}
+	return &reply, nil
+}
+
+// reviewThreadsQuery pages through the review threads of a pull request,
+// with the REST ID of each thread's first comment
+const reviewThreadsQuery = `query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $cursor) {
+        nodes { id isResolved comments(first: 1) { nodes { databaseId } } }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+const resolveThreadMutation = `mutation($threadId: ID!) {
+  resolveReviewThread(input: {threadId: $threadId}) { thread { isResolved } }
+}`
+
+// ListReviewThreads returns the review threads of the pull request, following
+// pagination. The GraphQL API requires a token.
+func (c *GitHubClient) ListReviewThreads() ([]GitHubReviewThread, error) {
+	if c.PullNumber == 0 {
+		return nil, fmt.Errorf("no pull request selected")
+	}
+
+	threads := []GitHubReviewThread{}
+	var cursor *string
+	for {
+		var data struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						Nodes []struct {
+							ID         string `json:"id"`
+							IsResolved bool   `json:"isResolved"`
+							Comments   struct {
+								Nodes []struct {
+									DatabaseID int64 `json:"databaseId"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+		vars := map[string]any{"owner": c.Owner, "repo": c.Repo, "number": c.PullNumber, "cursor": cursor}
+		if err := c.graphql(reviewThreadsQuery, vars, &data); err != nil {
+			return nil, fmt.Errorf("failed to list review threads: %w", err)
+		}
+
+		page := data.Repository.PullRequest.ReviewThreads
+		for _, n := range page.Nodes {
+			t := GitHubReviewThread{ID: n.ID, IsResolved: n.IsResolved}
+			if len(n.Comments.Nodes) > 0 {
+				t.RootComment = n.Comments.Nodes[0].DatabaseID
+			}
+			threads = append(threads, t)
+		}
+		if !page.PageInfo.HasNextPage {
+			return threads, nil
+		}
+		end := page.PageInfo.EndCursor
+		cursor = &end
+	}
+}
+
+// ResolveThread marks a review thread as resolved.
+func (c *GitHubClient) ResolveThread(threadID string) error {
+	var data struct{}
+	if err := c.graphql(resolveThreadMutation, map[string]any{"threadId": threadID}, &data); err != nil {
+		return fmt.Errorf("failed to resolve review thread: %w", err)
+	}
+	return nil
+}
+
+// graphql runs a GraphQL query and decodes its data into dest.
+func (c *GitHubClient) graphql(query string, vars map[string]any, dest any) error {
+	if c.Token == "" {
+		return fmt.Errorf("the GitHub GraphQL API requires a token (set GITHUB_TOKEN or run 'gh auth login')")
+	}
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err := c.do(http.MethodPost, c.graphqlURL(), map[string]any{"query": query, "variables": vars}, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		messages := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			messages[i] = e.Message
+		}
+		return fmt.Errorf("GraphQL error: %s", strings.Join(messages, "; "))
+	}
+	if err := json.Unmarshal(resp.Data, dest); err != nil {
+		return fmt.Errorf("failed to parse GraphQL response: %w", err)
+	}
+	return nil
+}
+
+// graphqlURL returns the GraphQL endpoint: /graphql on github.com, /api/graphql
+// on GitHub Enterprise Server (whose REST API is under /api/v3).
+func (c *GitHubClient) graphqlURL() string {
+	base := strings.TrimSuffix(c.BaseURL, "/")
+	if strings.HasSuffix(base, "/api/v3") {
+		return strings.TrimSuffix(base, "/v3") + "/graphql"
+	}
+	return base + "/graphql"
+}
+
+func (c *GitHubClient) repoURL(path string) string {
+	return fmt.Sprintf("%s/repos/%s/%s%s", strings.TrimSuffix(c.BaseURL, "/"), c.Owner, c.Repo, path)
+}
+
+func (c *GitHubClient) do(method, reqURL string, body, dest any) (*http.Response, error) {
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, reqURL, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, ReadJSON(resp, dest)
+}
+
+// GitHubToken returns a GitHub token from GITHUB_TOKEN, GH_TOKEN or the gh
+// CLI, or "" when none is available (public repositories still work, with
+// a low rate limit).
+func GitHubToken() string {
+	for _, key := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if token := os.Getenv(key); token != "" {
+			return token
+		}
+	}
+	out, err := exec.Command("gh", "auth", "token").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// GitHubFixture is a GitHubAPI backed by a JSON file holding the review
+// comments of a pull request, in the REST API format, and its threads:
+//
+//	{"pull_number": 12, "comments": [{"id": 1, "path": "...", ...}],
+//	 "threads": [{"id": "T1", "is_resolved": true, "root_comment_id": 1}]}
+//
+// Top-level comments without a thread entry are in an unresolved thread.
+// Replies and resolutions are written to the file.
+type GitHubFixture struct {
+	path string
+	mu   sync.Mutex
+}
+
+type gitHubFixtureFile struct {
+	PullNumber int                   `json:"pull_number"`
+	Comments   []GitHubReviewComment `json:"comments"`
+	Threads    []GitHubReviewThread  `json:"threads,omitempty"`
+}
+
+// NewGitHubFixture returns the fixture at path.
+func NewGitHubFixture(path string) *GitHubFixture {
+	return &GitHubFixture{path: path}
+}
+
+func (f *GitHubFixture) ListReviewComments() ([]GitHubReviewComment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	return data.Comments, nil
+}
+
+func (f *GitHubFixture) ListReviewThreads() ([]GitHubReviewThread, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	return data.threads(), nil
+}
+
+func (f *GitHubFixture) ResolveThread(threadID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := f.read()
+	if err != nil {
+		return err
+	}
+	threads := data.threads()
+	found := false
+	for i := range threads {
+		if threads[i].ID == threadID {
+			threads[i].IsResolved = true
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("review thread %s not found", threadID)
+	}
+	data.Threads = threads
+	return f.write(data)
+}
+
+func (f *GitHubFixture) CreateReply(commentID int64, body string) (*GitHubReviewComment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *GitHubReviewComment
+	var maxID int64
+	for i := range data.Comments {
+		if data.Comments[i].ID == commentID {
+			parent = &data.Comments[i]
+		}
+		if data.Comments[i].ID > maxID {
+			maxID = data.Comments[i].ID
+		}
+	}
+	if parent == nil {
+		return nil, fmt.Errorf("%w: %d", ErrCommentNotFound, commentID)
+	}
+
+	reply := GitHubReviewComment{
+		ID:          maxID + 1,
+		Path:        parent.Path,
+		Body:        body,
+		InReplyToID: commentID,
+		CreatedAt:   localTimestamp(),
+	}
+	reply.UpdatedAt = reply.CreatedAt
+	data.Comments = append(data.Comments, reply)
+	if err := f.write(data); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func (f *GitHubFixture) read() (*gitHubFixtureFile, error) {
+	raw, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GitHub fixture: %w", err)
+	}
+	var data gitHubFixtureFile
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid GitHub fixture %s: %w", f.path, err)
+	}
+	return &data, nil
+}
+
+func (f *GitHubFixture) write(data *gitHubFixtureFile) error {
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+	if err := os.WriteFile(f.path, append(out, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+// threads returns the recorded threads plus an unresolved thread for every
+// other top-level comment.
+func (d *gitHubFixtureFile) threads() []GitHubReviewThread {
+	threads := append([]GitHubReviewThread{}, d.Threads...)
+	known := make(map[int64]bool)
+	for _, t := range threads {
+		known[t.RootComment] = true
+	}
+	for _, c := range d.Comments {
+		if c.InReplyToID == 0 && !known[c.ID] {
+			threads = append(threads, GitHubReviewThread{ID: fmt.Sprintf("thread-%d", c.ID), RootComment: c.ID})
+		}
+	}
+	return threads
+}
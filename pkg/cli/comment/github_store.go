@@ -0,0 +1,245 @@
+package comment
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// GitHubIDPrefix marks comments that come from a GitHub pull request
+const GitHubIDPrefix = "gh-"
+
+// GitHubStore is the Store of a pull request's review comments. Resolving a
+// comment resolves its review thread on GitHub. New top-level comments must
+// be made on GitHub.
+type GitHubStore struct {
+	API GitHubAPI
+
+	mu       sync.Mutex
+	comments []ReviewComment              // cached, in API order
+	threads  map[int64]GitHubReviewThread // cached, by root comment ID
+}
+
+func NewGitHubStore(api GitHubAPI) *GitHubStore {
+	return &GitHubStore{API: api}
+}
+
+// IsGitHubID reports whether id belongs to a GitHub review comment.
+func IsGitHubID(id string) bool {
+	return strings.HasPrefix(id, GitHubIDPrefix)
+}
+
+func (s *GitHubStore) FetchComments() ([]ReviewComment, error) {
+	return s.filter(func(c *ReviewComment) bool { return c.ParentCommentID == "" && !c.IsResolved })
+}
+
+func (s *GitHubStore) FetchResolvedComments() ([]ReviewComment, error) {
+	return s.filter(func(c *ReviewComment) bool { return c.ParentCommentID == "" && c.IsResolved })
+}
+
+func (s *GitHubStore) FetchReplies() ([]ReviewComment, error) {
+	return s.filter(func(c *ReviewComment) bool { return c.ParentCommentID != "" && !c.IsResolved })
+}
+
+func (s *GitHubStore) FetchRepliesByParentID(parentID string) ([]ReviewComment, error) {
+	return s.filter(func(c *ReviewComment) bool { return c.ParentCommentID == parentID })
+}
+
+func (s *GitHubStore) FetchCommentByID(commentID string) (*ReviewComment, error) {
+	comments, err := s.filter(func(c *ReviewComment) bool { return c.ID == commentID })
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrCommentNotFound, commentID)
+	}
+	return &comments[0], nil
+}
+
+func (s *GitHubStore) CreateComment(nc NewComment) (*ReviewComment, error) {
+	return nil, fmt.Errorf("new comments on a GitHub pull request must be made on GitHub")
+}
+
+func (s *GitHubStore) CreateReply(parentID, content string) (*ThreadReply, error) {
+	id, err := parseGitHubID(parentID)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := s.API.CreateReply(id, content)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return &ThreadReply{
+		ID:         gitHubID(reply.ID),
+		ParentID:   parentID,
+		AuthorName: reply.User.Login,
+		Content:    reply.Body,
+		CreatedAt:  reply.CreatedAt,
+	}, nil
+}
+
+func (s *GitHubStore) ResolveComment(commentID string) error {
+	c, err := s.FetchCommentByID(commentID)
+	if err != nil {
+		return err
+	}
+	if c.IsResolved {
+		return nil
+	}
+	if c.ParentCommentID != "" {
+		return fmt.Errorf("%s is a reply; resolve its thread %s instead", commentID, c.ParentCommentID)
+	}
+
+	id, err := parseGitHubID(commentID)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	thread, ok := s.threads[id]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no review thread found for %s", commentID)
+	}
+	if err := s.API.ResolveThread(thread.ID); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// ResolveCommentWithReplies resolves the thread; replies are resolved with it.
+func (s *GitHubStore) ResolveCommentWithReplies(commentID string, replyIDs []string) error {
+	return s.ResolveComment(commentID)
+}
+
+func (s *GitHubStore) filter(keep func(c *ReviewComment) bool) ([]ReviewComment, error) {
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ReviewComment, 0, len(all))
+	for i := range all {
+		if keep(&all[i]) {
+			result = append(result, all[i])
+		}
+	}
+	return result, nil
+}
+
+func (s *GitHubStore) load() ([]ReviewComment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.comments != nil {
+		return s.comments, nil
+	}
+	raw, err := s.API.ListReviewComments()
+	if err != nil {
+		return nil, err
+	}
+	threads, err := s.API.ListReviewThreads()
+	if err != nil {
+		return nil, err
+	}
+	s.threads = make(map[int64]GitHubReviewThread, len(threads))
+	for _, t := range threads {
+		s.threads[t.RootComment] = t
+	}
+	s.comments = MapGitHubComments(raw, threads)
+	return s.comments, nil
+}
+
+func (s *GitHubStore) invalidate() {
+	s.mu.Lock()
+	s.comments = nil
+	s.threads = nil
+	s.mu.Unlock()
+}
+
+// MapGitHubComments converts pull request review comments to review comments.
+// Replies point at the first comment of their thread, and every comment of a
+// resolved thread is resolved. The commented text is taken from the end of
+// the diff hunk, so comments can be re-anchored.
+func MapGitHubComments(raw []GitHubReviewComment, threads []GitHubReviewThread) []ReviewComment {
+	resolved := make(map[int64]bool)
+	for _, t := range threads {
+		if t.IsResolved {
+			resolved[t.RootComment] = true
+		}
+	}
+
+	comments := make([]ReviewComment, 0, len(raw))
+	for _, g := range raw {
+		c := ReviewComment{
+			ID:         gitHubID(g.ID),
+			FilePath:   g.Path,
+			Content:    g.Body,
+			AuthorName: g.User.Login,
+			CreatedAt:  g.CreatedAt,
+			UpdatedAt:  g.UpdatedAt,
+		}
+
+		if g.InReplyToID != 0 {
+			c.ParentCommentID = gitHubID(g.InReplyToID)
+			c.IsResolved = resolved[g.InReplyToID]
+			comments = append(comments, c)
+			continue
+		}
+		c.IsResolved = resolved[g.ID]
+
+		// Outdated on GitHub: line is null, fall back to the original lines
+		line, startLine := g.Line, g.StartLine
+		if line == nil {
+			line, startLine = g.OriginalLine, g.OriginalStartLine
+		}
+		span := 1
+		if line != nil && startLine != nil && *startLine <= *line {
+			span = *line - *startLine + 1
+		}
+		c.SelectedText = hunkTail(g.DiffHunk, span, g.Side == "LEFT")
+		if g.Side != "LEFT" {
+			c.Line = line
+			c.StartLine = startLine
+		}
+		comments = append(comments, c)
+	}
+	return comments
+}
+
+// hunkTail returns the last n lines of a diff hunk on one side (the lines a
+// review comment is on), without the diff markers.
+func hunkTail(diffHunk string, n int, left bool) string {
+	skip := "-"
+	if left {
+		skip = "+"
+	}
+
+	var lines []string
+	for _, l := range strings.Split(strings.TrimRight(diffHunk, "\n"), "\n") {
+		if strings.HasPrefix(l, "@@") || strings.HasPrefix(l, skip) || strings.HasPrefix(l, `\`) {
+			continue
+		}
+		if l != "" {
+			l = l[1:]
+		}
+		lines = append(lines, l)
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func gitHubID(id int64) string {
+	return GitHubIDPrefix + strconv.FormatInt(id, 10)
+}
+
+func parseGitHubID(id string) (int64, error) {
+	n, err := strconv.ParseInt(strings.TrimPrefix(id, GitHubIDPrefix), 10, 64)
+	if err != nil || !IsGitHubID(id) {
+		return 0, fmt.Errorf("%w: %s", ErrCommentNotFound, id)
+	}
+	return n, nil
+}
@@ -0,0 +1,237 @@
+package comment
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testGitHubComments() []GitHubReviewComment {
+	return []GitHubReviewComment{
+		{
+			ID: 1, Path: "specledger/010-x/spec.md", Line: intPtr(12), Side: "RIGHT",
+			DiffHunk: "@@ -10,2 +10,3 @@\n context\n-old\n+Tokens expire after 1h.",
+			Body:     "Why 1h?", User: GitHubUser{Login: "alice"}, CreatedAt: "2026-01-01T00:00:00Z",
+		},
+		{ID: 2, Path: "specledger/010-x/spec.md", Body: "Security asked for it", InReplyToID: 1, User: GitHubUser{Login: "bob"}},
+		{
+			ID: 3, Path: "specledger/010-x/plan.md", Line: intPtr(5), StartLine: intPtr(4), Side: "RIGHT",
+			DiffHunk: "@@ -1,3 +1,5 @@\n a\n+Step one\n+Step two",
+			Body:     "Merge these", User: GitHubUser{Login: "alice"},
+		},
+		{ID: 4, Path: "specledger/010-x/plan.md", Body: "Done", InReplyToID: 3, User: GitHubUser{Login: "carol"}},
+	}
+}
+
+func testGitHubThreads() []GitHubReviewThread {
+	return []GitHubReviewThread{
+		{ID: "T1", RootComment: 1},
+		{ID: "T3", RootComment: 3, IsResolved: true},
+	}
+}
+
+func TestMapGitHubComments(t *testing.T) {
+	comments := MapGitHubComments(testGitHubComments(), testGitHubThreads())
+	if len(comments) != 4 {
+		t.Fatalf("expected 4 comments, got %d", len(comments))
+	}
+
+	c := comments[0]
+	if c.ID != "gh-1" || c.AuthorName != "alice" || c.IsResolved {
+		t.Errorf("comment 1 = %+v", c)
+	}
+	if c.SelectedText != "Tokens expire after 1h." {
+		t.Errorf("selected text = %q", c.SelectedText)
+	}
+	if c.Line == nil || *c.Line != 12 || c.StartLine != nil {
+		t.Errorf("lines = %v/%v, want 12", c.StartLine, c.Line)
+	}
+
+	if comments[1].ParentCommentID != "gh-1" {
+		t.Errorf("reply parent = %q, want gh-1", comments[1].ParentCommentID)
+	}
+
+	if comments[2].SelectedText != "Step one\nStep two" {
+		t.Errorf("multi-line selected text = %q", comments[2].SelectedText)
+	}
+	if !comments[2].IsResolved || !comments[3].IsResolved {
+		t.Errorf("comments of a resolved thread should be resolved")
+	}
+}
+
+func TestGitHubClient(t *testing.T) {
+	var replies []map[string]string
+	var resolved []string
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q", got)
+		}
+		switch {
+		case r.URL.Path == "/repos/o/r/pulls":
+			if r.URL.Query().Get("head") != "o:010-x" {
+				t.Errorf("head = %q", r.URL.Query().Get("head"))
+			}
+			fmt.Fprint(w, `[{"number": 7}]`)
+		case r.URL.Path == "/repos/o/r/pulls/7/comments" && r.URL.Query().Get("page") == "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/o/r/pulls/7/comments?page=2>; rel="next"`, srv.URL))
+			fmt.Fprint(w, `[{"id": 1, "path": "spec.md", "line": 3, "body": "a"}]`)
+		case r.URL.Path == "/repos/o/r/pulls/7/comments":
+			fmt.Fprint(w, `[{"id": 2, "path": "spec.md", "body": "b", "in_reply_to_id": 1}]`)
+		case r.URL.Path == "/graphql" && r.Method == http.MethodPost:
+			var req struct {
+				Query     string         `json:"query"`
+				Variables map[string]any `json:"variables"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if strings.HasPrefix(req.Query, "mutation") {
+				resolved = append(resolved, req.Variables["threadId"].(string))
+				fmt.Fprint(w, `{"data": {"resolveReviewThread": {"thread": {"isResolved": true}}}}`)
+				return
+			}
+			if req.Variables["number"] != float64(7) {
+				t.Errorf("GraphQL variables = %v", req.Variables)
+			}
+			if req.Variables["cursor"] == nil {
+				fmt.Fprint(w, `{"data": {"repository": {"pullRequest": {"reviewThreads": {
+					"nodes": [{"id": "T1", "isResolved": false, "comments": {"nodes": [{"databaseId": 1}]}}],
+					"pageInfo": {"hasNextPage": true, "endCursor": "c1"}}}}}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data": {"repository": {"pullRequest": {"reviewThreads": {
+				"nodes": [], "pageInfo": {"hasNextPage": false, "endCursor": ""}}}}}}`)
+		case r.URL.Path == "/repos/o/r/pulls/7/comments/1/replies" && r.Method == http.MethodPost:
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			replies = append(replies, body)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id": 3, "body": %q, "in_reply_to_id": 1}`, body["body"])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewGitHubClient("o", "r", "tok")
+	client.BaseURL = srv.URL
+
+	if n, err := client.FindPullRequest("010-x"); err != nil || n != 7 {
+		t.Fatalf("FindPullRequest() = %d, %v", n, err)
+	}
+
+	store := NewGitHubStore(client)
+	open, err := store.FetchComments()
+	if err != nil {
+		t.Fatalf("FetchComments() error: %v", err)
+	}
+	if len(open) != 1 || open[0].ID != "gh-1" {
+		t.Fatalf("FetchComments() = %+v", open)
+	}
+	threadReplies, _ := store.FetchReplies()
+	if len(threadReplies) != 1 {
+		t.Fatalf("expected the reply from page 2, got %d", len(threadReplies))
+	}
+
+	if _, err := store.CreateReply("gh-1", "Fixed"); err != nil {
+		t.Fatalf("CreateReply() error: %v", err)
+	}
+	if len(replies) != 1 || replies[0]["body"] != "Fixed" {
+		t.Errorf("expected a reply, got %v", replies)
+	}
+
+	if err := store.ResolveComment("gh-1"); err != nil {
+		t.Fatalf("ResolveComment() error: %v", err)
+	}
+	if len(resolved) != 1 || resolved[0] != "T1" {
+		t.Errorf("expected thread T1 resolved, got %v", resolved)
+	}
+	if len(replies) != 1 {
+		t.Errorf("resolving should not post a reply, got %v", replies)
+	}
+}
+
+func TestGitHubClient_GraphQLNeedsToken(t *testing.T) {
+	client := NewGitHubClient("o", "r", "")
+	client.PullNumber = 7
+	if _, err := client.ListReviewThreads(); err == nil || !strings.Contains(err.Error(), "token") {
+		t.Errorf("expected a token error, got %v", err)
+	}
+}
+
+func TestGitHubClient_NoPullRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	}))
+	defer srv.Close()
+
+	client := NewGitHubClient("o", "r", "")
+	client.BaseURL = srv.URL
+	if _, err := client.FindPullRequest("010-x"); err == nil {
+		t.Fatal("expected an error when the branch has no pull request")
+	}
+}
+
+func TestGitHubFixture_Resolve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pr.json")
+	data, _ := json.Marshal(gitHubFixtureFile{PullNumber: 7, Comments: testGitHubComments(), Threads: testGitHubThreads()[1:]})
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewGitHubStore(NewGitHubFixture(path))
+	if err := store.ResolveComment("gh-1"); err != nil {
+		t.Fatalf("ResolveComment() error: %v", err)
+	}
+
+	// A fresh store sees the resolution written to the fixture
+	open, err := NewGitHubStore(NewGitHubFixture(path)).FetchComments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 0 {
+		t.Errorf("expected no open comments after resolving, got %d", len(open))
+	}
+}
+
+func TestCombinedStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pr.json")
+	data, _ := json.Marshal(gitHubFixtureFile{PullNumber: 7, Comments: testGitHubComments(), Threads: testGitHubThreads()[1:]})
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	local := NewLocalStore(t.TempDir(), "010-x")
+	created, err := local.CreateComment(NewComment{FilePath: "specledger/010-x/spec.md", Content: "local", Line: intPtr(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewCombinedStore(local, NewGitHubStore(NewGitHubFixture(path)))
+	open, err := store.FetchComments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 2 {
+		t.Fatalf("expected local + GitHub comment, got %d", len(open))
+	}
+
+	if err := store.ResolveComment(created.ID); err != nil {
+		t.Fatalf("resolve local: %v", err)
+	}
+	if err := store.ResolveComment("gh-1"); err != nil {
+		t.Fatalf("resolve GitHub: %v", err)
+	}
+	if open, _ := store.FetchComments(); len(open) != 0 {
+		t.Errorf("expected all comments resolved, got %d open", len(open))
+	}
+
+	if _, err := NewCombinedStore(nil, NewGitHubStore(NewGitHubFixture(path))).FetchCommentByID(created.ID); err == nil {
+		t.Error("expected not found for a non-GitHub ID without a primary store")
+	}
+}
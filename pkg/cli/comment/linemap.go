@@ -0,0 +1,219 @@
+package comment
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+type hunk struct {
+	oldStart, oldCount int
+	newStart, newCount int
+}
+
+// LineMap maps line numbers of an old version of a file to the current
+// version, from a unified diff between the two.
+type LineMap struct {
+	hunks []hunk
+}
+
+// ParseLineMap builds a LineMap from `git diff` output for a single file.
+func ParseLineMap(diff string) *LineMap {
+	m := &LineMap{}
+	for _, line := range strings.Split(diff, "\n") {
+		match := hunkHeaderRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		m.hunks = append(m.hunks, hunk{
+			oldStart: atoiDefault(match[1], 0),
+			oldCount: atoiDefault(match[2], 1),
+			newStart: atoiDefault(match[3], 0),
+			newCount: atoiDefault(match[4], 1),
+		})
+	}
+	return m
+}
+
+// Map returns the current line number of an old line, and false when the
+// line was changed or deleted.
+func (m *LineMap) Map(oldLine int) (int, bool) {
+	shift := 0
+	for _, h := range m.hunks {
+		if h.oldCount == 0 {
+			// Pure insertion after oldStart
+			if oldLine <= h.oldStart {
+				break
+			}
+			shift += h.newCount
+			continue
+		}
+		if oldLine < h.oldStart {
+			break
+		}
+		if oldLine < h.oldStart+h.oldCount {
+			return 0, false
+		}
+		shift += h.newCount - h.oldCount
+	}
+	return oldLine + shift, true
+}
+
+// RegionChanged reports whether any of the old lines start..end were
+// changed or deleted, or lines were inserted between them.
+func (m *LineMap) RegionChanged(start, end int) bool {
+	if start <= 0 {
+		start = end
+	}
+	prev := 0
+	for line := start; line <= end; line++ {
+		mapped, ok := m.Map(line)
+		if !ok {
+			return true
+		}
+		if line > start && mapped != prev+1 {
+			return true
+		}
+		prev = mapped
+	}
+	return false
+}
+
+// ContentLineMap maps the lines of before to after, two versions of a file.
+func ContentLineMap(before, after string) (*LineMap, error) {
+	dir, err := os.MkdirTemp("", "sl-linemap-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	oldPath := filepath.Join(dir, "before")
+	newPath := filepath.Join(dir, "after")
+	if err := os.WriteFile(oldPath, []byte(before), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.WriteFile(newPath, []byte(after), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	// Exit code 1 means the files differ
+	cmd := exec.Command("git", "diff", "--no-index", "-U0", "--no-color", oldPath, newPath)
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+		return nil, fmt.Errorf("git diff failed: %w", err)
+	}
+	return ParseLineMap(string(out)), nil
+}
+
+// DiffLineMap maps the lines of filePath as of the last commit before since
+// (the version a comment was made on) to its current working tree version.
+// It returns nil when git history isn't available.
+func DiffLineMap(root, filePath, since string) *LineMap {
+	return newLineMapCache(root).lineMap(filePath, since)
+}
+
+// fileCommit is a commit that touched a file
+type fileCommit struct {
+	hash string
+	when time.Time // committer date, as used by git log --before
+}
+
+// lineMapCache builds the LineMaps of many comments with one git log per
+// file and one git diff per file and base commit, however many comments
+// share them.
+type lineMapCache struct {
+	root    string
+	history map[string][]fileCommit // by file, newest first; nil if unavailable
+	maps    map[[2]string]*LineMap  // by file and base commit
+}
+
+func newLineMapCache(root string) *lineMapCache {
+	return &lineMapCache{
+		root:    root,
+		history: make(map[string][]fileCommit),
+		maps:    make(map[[2]string]*LineMap),
+	}
+}
+
+// lineMap returns the LineMap of filePath from its last commit before since,
+// or nil when git history isn't available.
+func (m *lineMapCache) lineMap(filePath, since string) *LineMap {
+	if since == "" {
+		return nil
+	}
+	base := m.baseCommit(filePath, since)
+	if base == "" {
+		return nil
+	}
+
+	key := [2]string{filePath, base}
+	if lineMap, ok := m.maps[key]; ok {
+		return lineMap
+	}
+	var lineMap *LineMap
+	cmd := exec.Command("git", "diff", "-U0", "--no-color", base, "--", filePath)
+	cmd.Dir = m.root
+	if out, err := cmd.Output(); err == nil {
+		lineMap = ParseLineMap(string(out))
+	}
+	m.maps[key] = lineMap
+	return lineMap
+}
+
+// baseCommit returns the last commit touching filePath before since
+func (m *lineMapCache) baseCommit(filePath, since string) string {
+	t, err := time.Parse(time.RFC3339Nano, since)
+	if err != nil {
+		// Let git parse other date formats
+		return gitOutput(m.root, "log", "-1", "--format=%H", "--before="+since, "--", filePath)
+	}
+
+	history, ok := m.history[filePath]
+	if !ok {
+		for _, line := range strings.Split(gitOutput(m.root, "log", "--format=%H %cI", "--", filePath), "\n") {
+			hash, date, _ := strings.Cut(line, " ")
+			when, err := time.Parse(time.RFC3339, date)
+			if err != nil {
+				continue
+			}
+			history = append(history, fileCommit{hash: hash, when: when})
+		}
+		m.history[filePath] = history
+	}
+	for _, c := range history {
+		if !c.when.After(t) {
+			return c.hash
+		}
+	}
+	return ""
+}
+
+func gitOutput(dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
@@ -0,0 +1,394 @@
+package comment
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+const (
+	// LocalStoreFile is the local comment store of a spec, next to its artifacts
+	LocalStoreFile = "review.jsonl"
+	// LocalIDPrefix marks comments that have not been pushed to the remote yet
+	LocalIDPrefix = "local-"
+)
+
+// ErrLocalStoreLocked is returned when another process holds the local store lock.
+var ErrLocalStoreLocked = errors.New("local comment store is locked by another process")
+
+// LocalComment is a review comment in the local store, with its sync state.
+type LocalComment struct {
+	ReviewComment
+	// Synced is set once the comment exists remotely (pulled or pushed).
+	Synced bool `json:"synced,omitempty"`
+	// PendingResolve marks a comment resolved locally but not yet remotely.
+	PendingResolve bool `json:"pending_resolve,omitempty"`
+	// RemoteUpdatedAt is the remote updated_at at the last sync, used to
+	// detect remote edits to a comment resolved locally.
+	RemoteUpdatedAt string `json:"remote_updated_at,omitempty"`
+}
+
+// Pending reports whether the comment has local changes to push.
+func (c *LocalComment) Pending() bool {
+	return !c.Synced || c.PendingResolve
+}
+
+// LocalStore is the Store of a spec's comments in specledger/<spec>/review.jsonl,
+// used offline and when not authenticated. 'sl comment sync' reconciles it
+// with the remote.
+type LocalStore struct {
+	path        string
+	lock        *flock.Flock
+	mu          sync.Mutex
+	AuthorName  string // author of comments created locally
+	AuthorEmail string
+}
+
+// NewLocalStore returns the local store of a spec. basePath is the artifact
+// directory (e.g. "specledger"); the file is created on first write.
+func NewLocalStore(basePath, specKey string) *LocalStore {
+	path := filepath.Join(basePath, specKey, LocalStoreFile)
+	return &LocalStore{
+		path: path,
+		lock: flock.New(path + ".lock"),
+	}
+}
+
+// Path returns the path of the review.jsonl file.
+func (s *LocalStore) Path() string {
+	return s.path
+}
+
+// Exists reports whether the store file exists.
+func (s *LocalStore) Exists() bool {
+	_, err := os.Stat(s.path)
+	return err == nil
+}
+
+// All returns every comment and reply in the store, in creation order.
+func (s *LocalStore) All() ([]LocalComment, error) {
+	if !s.Exists() {
+		return []LocalComment{}, nil
+	}
+	var all []LocalComment
+	err := s.withLock(func(comments []LocalComment) ([]LocalComment, error) {
+		all = comments
+		return nil, nil
+	})
+	return all, err
+}
+
+// PendingCount returns the number of comments with local changes to push.
+func (s *LocalStore) PendingCount() (int, error) {
+	all, err := s.All()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for i := range all {
+		if all[i].Pending() {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *LocalStore) FetchComments() ([]ReviewComment, error) {
+	return s.filter(func(c *LocalComment) bool { return c.ParentCommentID == "" && !c.IsResolved })
+}
+
+func (s *LocalStore) FetchResolvedComments() ([]ReviewComment, error) {
+	return s.filter(func(c *LocalComment) bool { return c.ParentCommentID == "" && c.IsResolved })
+}
+
+func (s *LocalStore) FetchReplies() ([]ReviewComment, error) {
+	return s.filter(func(c *LocalComment) bool { return c.ParentCommentID != "" && !c.IsResolved })
+}
+
+func (s *LocalStore) FetchRepliesByParentID(parentID string) ([]ReviewComment, error) {
+	return s.filter(func(c *LocalComment) bool { return c.ParentCommentID == parentID })
+}
+
+func (s *LocalStore) FetchCommentByID(commentID string) (*ReviewComment, error) {
+	comments, err := s.filter(func(c *LocalComment) bool { return c.ID == commentID })
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrCommentNotFound, commentID)
+	}
+	return &comments[0], nil
+}
+
+func (s *LocalStore) CreateComment(nc NewComment) (*ReviewComment, error) {
+	c := LocalComment{ReviewComment: ReviewComment{
+		ID:           newLocalID(),
+		ChangeID:     nc.ChangeID,
+		FilePath:     nc.FilePath,
+		Content:      nc.Content,
+		SelectedText: nc.SelectedText,
+		Line:         nc.Line,
+		StartLine:    nc.StartLine,
+		AuthorName:   s.AuthorName,
+		AuthorEmail:  s.AuthorEmail,
+		CreatedAt:    localTimestamp(),
+	}}
+	c.UpdatedAt = c.CreatedAt
+
+	err := s.withLock(func(comments []LocalComment) ([]LocalComment, error) {
+		return append(comments, c), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &c.ReviewComment, nil
+}
+
+func (s *LocalStore) CreateReply(parentID, content string) (*ThreadReply, error) {
+	var reply LocalComment
+	err := s.withLock(func(comments []LocalComment) ([]LocalComment, error) {
+		parent := findLocal(comments, parentID)
+		if parent == nil {
+			return nil, fmt.Errorf("%w: %s", ErrCommentNotFound, parentID)
+		}
+		reply = LocalComment{ReviewComment: ReviewComment{
+			ID:              newLocalID(),
+			ChangeID:        parent.ChangeID,
+			FilePath:        parent.FilePath,
+			Content:         content,
+			AuthorName:      s.AuthorName,
+			AuthorEmail:     s.AuthorEmail,
+			ParentCommentID: parentID,
+			CreatedAt:       localTimestamp(),
+		}}
+		reply.UpdatedAt = reply.CreatedAt
+		return append(comments, reply), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ThreadReply{
+		ID:         reply.ID,
+		ParentID:   parentID,
+		AuthorName: reply.AuthorName,
+		Content:    reply.Content,
+		CreatedAt:  reply.CreatedAt,
+	}, nil
+}
+
+func (s *LocalStore) ResolveComment(commentID string) error {
+	return s.ResolveCommentWithReplies(commentID, nil)
+}
+
+// ResolveCommentWithReplies resolves a comment and the given replies locally.
+// The resolution is pushed by the next sync, which cascades to the remote
+// replies.
+func (s *LocalStore) ResolveCommentWithReplies(commentID string, replyIDs []string) error {
+	return s.withLock(func(comments []LocalComment) ([]LocalComment, error) {
+		c := findLocal(comments, commentID)
+		if c == nil {
+			return nil, fmt.Errorf("%w: %s", ErrCommentNotFound, commentID)
+		}
+		if !c.IsResolved {
+			c.IsResolved = true
+			c.PendingResolve = true
+			c.UpdatedAt = localTimestamp()
+		}
+		for _, id := range replyIDs {
+			if r := findLocal(comments, id); r != nil {
+				r.IsResolved = true
+			}
+		}
+		return comments, nil
+	})
+}
+
+func (s *LocalStore) filter(keep func(c *LocalComment) bool) ([]ReviewComment, error) {
+	all, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ReviewComment, 0, len(all))
+	for i := range all {
+		if keep(&all[i]) {
+			result = append(result, all[i].ReviewComment)
+		}
+	}
+	return result, nil
+}
+
+// withLock reads the store while holding the file lock and, when fn returns
+// a non-nil slice, writes it back.
+func (s *LocalStore) withLock(fn func(comments []LocalComment) ([]LocalComment, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	locked, err := s.lock.TryLock()
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !locked {
+		return ErrLocalStoreLocked
+	}
+	defer func() { _ = s.lock.Unlock() }()
+
+	comments, err := s.readAllUnlocked()
+	if err != nil {
+		return err
+	}
+
+	updated, err := fn(comments)
+	if updated != nil {
+		if writeErr := s.writeAllUnlocked(updated); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+	return err
+}
+
+func (s *LocalStore) readAllUnlocked() ([]LocalComment, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []LocalComment{}, nil
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	comments := []LocalComment{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var c LocalComment
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			// Skip invalid lines, like the issue store
+			continue
+		}
+		comments = append(comments, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", s.path, err)
+	}
+	return comments, nil
+}
+
+func (s *LocalStore) writeAllUnlocked(comments []LocalComment) error {
+	tmpPath := s.path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	writer := bufio.NewWriter(f)
+	for i := range comments {
+		data, err := json.Marshal(&comments[i])
+		if err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to marshal comment: %w", err)
+		}
+		if _, err := fmt.Fprintf(writer, "%s\n", data); err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to write comment: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to flush writer: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+	return nil
+}
+
+// LocalSpecs returns the specs under basePath with a local store and their
+// number of unresolved top-level comments.
+func LocalSpecs(basePath string) (map[string]int, error) {
+	entries, err := os.ReadDir(basePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]int{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", basePath, err)
+	}
+
+	specs := make(map[string]int)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		store := NewLocalStore(basePath, e.Name())
+		if !store.Exists() {
+			continue
+		}
+		open, err := store.FetchComments()
+		if err != nil {
+			return nil, err
+		}
+		specs[e.Name()] = len(open)
+	}
+	return specs, nil
+}
+
+// SortedSpecKeys returns the keys of a LocalSpecs result in order.
+func SortedSpecKeys(specs map[string]int) []string {
+	keys := make([]string, 0, len(specs))
+	for k := range specs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func findLocal(comments []LocalComment, id string) *LocalComment {
+	for i := range comments {
+		if comments[i].ID == id {
+			return &comments[i]
+		}
+	}
+	return nil
+}
+
+// IsLocalID reports whether id belongs to a comment not pushed yet.
+func IsLocalID(id string) bool {
+	return strings.HasPrefix(id, LocalIDPrefix)
+}
+
+func newLocalID() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return LocalIDPrefix + hex.EncodeToString(b)
+}
+
+func localTimestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
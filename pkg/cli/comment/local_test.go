@@ -0,0 +1,89 @@
+package comment
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStore_CreateReplyResolve(t *testing.T) {
+	base := t.TempDir()
+	store := NewLocalStore(base, "010-auth")
+	store.AuthorName = "Dev"
+
+	if store.Exists() {
+		t.Fatal("store should not exist before the first write")
+	}
+	if comments, err := store.FetchComments(); err != nil || len(comments) != 0 {
+		t.Fatalf("FetchComments() on empty store = %v, %v", comments, err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "010-auth")); !os.IsNotExist(err) {
+		t.Fatal("reading must not create the spec directory")
+	}
+
+	line := 3
+	c, err := store.CreateComment(NewComment{FilePath: "specledger/010-auth/spec.md", Content: "Clarify", SelectedText: "tokens", Line: &line})
+	if err != nil {
+		t.Fatalf("CreateComment() error: %v", err)
+	}
+	if !IsLocalID(c.ID) || c.AuthorName != "Dev" || c.CreatedAt == "" {
+		t.Fatalf("unexpected comment: %+v", c)
+	}
+
+	reply, err := store.CreateReply(c.ID, "Agreed")
+	if err != nil {
+		t.Fatalf("CreateReply() error: %v", err)
+	}
+	if _, err := store.CreateReply("local-missing", "x"); !errors.Is(err, ErrCommentNotFound) {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+
+	replies, _ := store.FetchReplies()
+	if len(replies) != 1 || replies[0].ParentCommentID != c.ID || replies[0].FilePath != c.FilePath {
+		t.Fatalf("unexpected replies: %+v", replies)
+	}
+
+	if err := store.ResolveCommentWithReplies(c.ID, []string{reply.ID}); err != nil {
+		t.Fatalf("ResolveCommentWithReplies() error: %v", err)
+	}
+	open, _ := store.FetchComments()
+	resolved, _ := store.FetchResolvedComments()
+	replies, _ = store.FetchReplies()
+	if len(open) != 0 || len(resolved) != 1 || len(replies) != 0 {
+		t.Fatalf("expected comment and reply resolved: open=%d resolved=%d replies=%d", len(open), len(resolved), len(replies))
+	}
+
+	// Created comment + reply, and the comment's resolution, all pending
+	if n, _ := store.PendingCount(); n != 2 {
+		t.Fatalf("PendingCount() = %d, want 2", n)
+	}
+
+	data, err := os.ReadFile(store.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Fatalf("expected 2 JSONL lines, got %d:\n%s", lines, data)
+	}
+}
+
+func TestLocalSpecs(t *testing.T) {
+	base := t.TempDir()
+	line := 1
+	a := NewLocalStore(base, "010-a")
+	_, _ = a.CreateComment(NewComment{FilePath: "spec.md", Content: "x", Line: &line})
+	_, _ = a.CreateComment(NewComment{FilePath: "spec.md", Content: "y", Line: &line})
+	if err := os.MkdirAll(filepath.Join(base, "011-b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	specs, err := LocalSpecs(base)
+	if err != nil {
+		t.Fatalf("LocalSpecs() error: %v", err)
+	}
+	if len(specs) != 1 || specs["010-a"] != 2 {
+		t.Fatalf("LocalSpecs() = %v", specs)
+	}
+}
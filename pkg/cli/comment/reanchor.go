@@ -0,0 +1,269 @@
+package comment
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Anchor statuses of a comment in the current version of its file
+const (
+	AnchorCurrent  = "current"  // the commented text is still at the stored lines
+	AnchorMoved    = "moved"    // relocated; Line and StartLine were updated
+	AnchorOutdated = "outdated" // the commented text (or the file) is gone
+)
+
+const (
+	// fuzzyThreshold is the minimum word similarity of a fuzzy match
+	fuzzyThreshold = 0.7
+	// maxSlidingWords bounds the sliding search within lines to selections
+	// of up to this many words; longer ones are compared to whole lines
+	maxSlidingWords = 60
+	// fuzzyWindow is how many lines around the expected line the fuzzy
+	// search covers; text moved further only matches exactly
+	fuzzyWindow = 100
+)
+
+// ReanchorComments locates each comment in the current version of its file
+// under root, updating Line, StartLine and AnchorStatus. Comments without
+// selected text or line (general feedback, replies) are left as they are.
+func ReanchorComments(root string, comments []ReviewComment) {
+	contents := make(map[string]string)
+	missing := make(map[string]bool)
+	lineMaps := newLineMapCache(root)
+
+	for i := range comments {
+		c := &comments[i]
+		if c.FilePath == "" || (c.SelectedText == "" && c.Line == nil) {
+			continue
+		}
+
+		content, ok := contents[c.FilePath]
+		if !ok && !missing[c.FilePath] {
+			data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(c.FilePath)))
+			if err != nil {
+				missing[c.FilePath] = true
+			} else {
+				content = string(data)
+				contents[c.FilePath] = content
+			}
+		}
+		if missing[c.FilePath] {
+			c.AnchorStatus = AnchorOutdated
+			continue
+		}
+
+		var lineMap *LineMap
+		if c.Line != nil {
+			lineMap = lineMaps.lineMap(c.FilePath, c.CreatedAt)
+		}
+		c.AnchorStatus = Locate(content, c, lineMap)
+	}
+}
+
+// Locate finds a comment in content, the current version of its file,
+// updating c.Line and c.StartLine, and returns its anchor status. The
+// selected text is looked up at the stored lines, then anywhere in the file
+// (closest to where lineMap places the stored lines), then fuzzily to survive
+// rewording and rewrapping. Without selected text the stored lines are moved
+// with lineMap. lineMap may be nil.
+func Locate(content string, c *ReviewComment, lineMap *LineMap) string {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+
+	start, end := 0, 0
+	if c.Line != nil {
+		end = *c.Line
+		start = end
+		if c.StartLine != nil && *c.StartLine > 0 {
+			start = *c.StartLine
+		}
+	}
+
+	if c.SelectedText == "" {
+		if end == 0 {
+			return ""
+		}
+		if lineMap == nil {
+			if end <= len(lines) {
+				return AnchorCurrent
+			}
+			return AnchorOutdated
+		}
+		newStart, okStart := lineMap.Map(start)
+		newEnd, okEnd := lineMap.Map(end)
+		if !okStart || !okEnd || newEnd > len(lines) {
+			return AnchorOutdated
+		}
+		if newStart == start && newEnd == end {
+			return AnchorCurrent
+		}
+		setAnchorLines(c, newStart, newEnd)
+		return AnchorMoved
+	}
+
+	if end > 0 {
+		if region, err := SelectLines(content, start, end); err == nil && strings.Contains(region, c.SelectedText) {
+			return AnchorCurrent
+		}
+	}
+
+	expected := start
+	if lineMap != nil && start > 0 {
+		if l, ok := lineMap.Map(start); ok {
+			expected = l
+		}
+	}
+
+	if s, e, ok := findExact(content, c.SelectedText, expected); ok {
+		setAnchorLines(c, s, e)
+		return AnchorMoved
+	}
+	if s, e, ok := findFuzzy(lines, c.SelectedText, expected); ok {
+		setAnchorLines(c, s, e)
+		return AnchorMoved
+	}
+	return AnchorOutdated
+}
+
+func setAnchorLines(c *ReviewComment, start, end int) {
+	line := end
+	c.Line = &line
+	if start != end {
+		startLine := start
+		c.StartLine = &startLine
+	} else {
+		c.StartLine = nil
+	}
+}
+
+// findExact returns the lines of the occurrence of text closest to expected
+// (the first one when expected is 0).
+func findExact(content, text string, expected int) (start, end int, found bool) {
+	span := strings.Count(text, "\n")
+	offset := 0
+	for {
+		i := strings.Index(content[offset:], text)
+		if i < 0 {
+			return start, end, found
+		}
+		line := 1 + strings.Count(content[:offset+i], "\n")
+		if !found || (expected > 0 && absInt(line-expected) < absInt(start-expected)) {
+			start, end, found = line, line+span, true
+		}
+		if expected == 0 {
+			return start, end, found
+		}
+		offset += i + 1
+	}
+}
+
+// findFuzzy returns the lines most similar to text, word by word. Windows of
+// the selection's line count are preferred, then the one closest to expected.
+// When expected is set, only the fuzzyWindow lines around it are searched.
+func findFuzzy(lines []string, text string, expected int) (start, end int, found bool) {
+	selected := normalizeWords(text)
+	if len(selected) == 0 {
+		return 0, 0, false
+	}
+
+	first, last := 0, len(lines)
+	if expected > 0 {
+		first = max(0, expected-1-fuzzyWindow)
+		last = min(len(lines), expected-1+fuzzyWindow)
+	}
+
+	span := strings.Count(text, "\n") + 1
+	bestScore := 0.0
+	for _, w := range []int{span, span + 1, span - 1} {
+		if w < 1 || w > last-first {
+			continue
+		}
+		for i := first; i+w <= last; i++ {
+			words := normalizeWords(strings.Join(lines[i:i+w], " "))
+			score := bestSliceSimilarity(selected, words)
+			if score < fuzzyThreshold {
+				continue
+			}
+			line := i + 1
+			closer := expected > 0 && absInt(line-expected) < absInt(start-expected)
+			if score > bestScore || (score == bestScore && w == end-start+1 && closer) {
+				bestScore = score
+				start, end, found = line, line+w-1, true
+			}
+		}
+	}
+	return start, end, found
+}
+
+// bestSliceSimilarity compares selected to the most similar run of words
+// in words.
+func bestSliceSimilarity(selected, words []string) float64 {
+	k := len(selected)
+	if len(words) <= k+1 || k > maxSlidingWords {
+		return wordSimilarity(selected, words)
+	}
+	best := 0.0
+	for i := 0; i+k <= len(words); i++ {
+		if s := wordSimilarity(selected, words[i:i+k]); s > best {
+			best = s
+		}
+	}
+	return best
+}
+
+// wordSimilarity is 1 minus the word-level edit distance, relative to the
+// longer sequence
+func wordSimilarity(a, b []string) float64 {
+	longest := len(a)
+	if len(b) > longest {
+		longest = len(b)
+	}
+	if longest == 0 {
+		return 1
+	}
+
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, minInt(cur[j-1]+1, prev[j-1]+cost))
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(b)])/float64(longest)
+}
+
+// normalizeWords lowercases text and splits it into words without the
+// surrounding punctuation and Markdown markup
+func normalizeWords(text string) []string {
+	fields := strings.Fields(strings.ToLower(text))
+	words := fields[:0]
+	for _, f := range fields {
+		if w := strings.Trim(f, ".,;:!?\"'()[]{}*_`#>-|"); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
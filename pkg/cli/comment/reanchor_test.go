@@ -0,0 +1,215 @@
+package comment
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestLineMap(t *testing.T) {
+	// Two lines inserted after line 2, line 5 changed, line 8 deleted
+	diff := `diff --git a/spec.md b/spec.md
+--- a/spec.md
++++ b/spec.md
+@@ -2,0 +3,2 @@
++new a
++new b
+@@ -5 +7 @@
+-old
++changed
+@@ -8 +9,0 @@
+-gone
+`
+	m := ParseLineMap(diff)
+
+	tests := []struct {
+		old    int
+		want   int
+		wantOK bool
+	}{
+		{old: 1, want: 1, wantOK: true},
+		{old: 2, want: 2, wantOK: true},
+		{old: 3, want: 5, wantOK: true},
+		{old: 5, wantOK: false},
+		{old: 6, want: 8, wantOK: true},
+		{old: 8, wantOK: false},
+		{old: 9, want: 10, wantOK: true},
+	}
+	for _, tt := range tests {
+		got, ok := m.Map(tt.old)
+		if ok != tt.wantOK || (ok && got != tt.want) {
+			t.Errorf("Map(%d) = %d, %v; want %d, %v", tt.old, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	if m.RegionChanged(3, 4) {
+		t.Error("RegionChanged(3, 4): lines only shifted")
+	}
+	if !m.RegionChanged(4, 6) {
+		t.Error("RegionChanged(4, 6): line 5 changed")
+	}
+	if !m.RegionChanged(2, 3) {
+		t.Error("RegionChanged(2, 3): lines inserted between them")
+	}
+}
+
+func TestLocate(t *testing.T) {
+	content := "# Spec\n\nIntro paragraph.\n\nTokens expire after one hour.\nRefresh is automatic.\n"
+
+	tests := []struct {
+		name          string
+		comment       ReviewComment
+		lineMap       *LineMap
+		wantStatus    string
+		wantLine      int
+		wantStartLine int
+	}{
+		{
+			name:       "unchanged",
+			comment:    ReviewComment{SelectedText: "expire after one hour", Line: intPtr(5)},
+			wantStatus: AnchorCurrent,
+			wantLine:   5,
+		},
+		{
+			name:       "moved down",
+			comment:    ReviewComment{SelectedText: "Refresh is automatic.", Line: intPtr(3)},
+			wantStatus: AnchorMoved,
+			wantLine:   6,
+		},
+		{
+			name:          "multi-line moved",
+			comment:       ReviewComment{SelectedText: "one hour.\nRefresh", StartLine: intPtr(1), Line: intPtr(2)},
+			wantStatus:    AnchorMoved,
+			wantLine:      6,
+			wantStartLine: 5,
+		},
+		{
+			name:       "reworded",
+			comment:    ReviewComment{SelectedText: "Tokens expire after 1 hour.", Line: intPtr(5)},
+			wantStatus: AnchorMoved,
+			wantLine:   5,
+		},
+		{
+			name:       "removed",
+			comment:    ReviewComment{SelectedText: "Sessions are stored in Redis with a TTL.", Line: intPtr(4)},
+			wantStatus: AnchorOutdated,
+			wantLine:   4,
+		},
+		{
+			name:       "line only without history",
+			comment:    ReviewComment{Line: intPtr(3)},
+			wantStatus: AnchorCurrent,
+			wantLine:   3,
+		},
+		{
+			name:       "line only shifted",
+			comment:    ReviewComment{Line: intPtr(3)},
+			lineMap:    ParseLineMap("@@ -1,0 +2,2 @@\n"),
+			wantStatus: AnchorMoved,
+			wantLine:   5,
+		},
+		{
+			name:       "line only changed",
+			comment:    ReviewComment{Line: intPtr(3)},
+			lineMap:    ParseLineMap("@@ -3 +3 @@\n"),
+			wantStatus: AnchorOutdated,
+			wantLine:   3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.comment
+			if got := Locate(content, &c, tt.lineMap); got != tt.wantStatus {
+				t.Fatalf("Locate() = %q, want %q", got, tt.wantStatus)
+			}
+			if c.Line == nil || *c.Line != tt.wantLine {
+				t.Errorf("Line = %v, want %d", c.Line, tt.wantLine)
+			}
+			gotStart := 0
+			if c.StartLine != nil {
+				gotStart = *c.StartLine
+			}
+			if gotStart != tt.wantStartLine {
+				t.Errorf("StartLine = %d, want %d", gotStart, tt.wantStartLine)
+			}
+		})
+	}
+}
+
+func TestReanchorComments(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(root, "specledger", "001-x"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, "specledger", "001-x", "spec.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("a\nb\nc\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "spec", "--date", "2020-01-01T00:00:00Z")
+
+	// Edited after the comments were made
+	write("new\na\nb\nc\n")
+
+	comments := []ReviewComment{
+		{ID: "1", FilePath: "specledger/001-x/spec.md", Line: intPtr(2), CreatedAt: time.Now().UTC().Format(time.RFC3339)},
+		{ID: "2", FilePath: "specledger/001-x/spec.md", SelectedText: "c", Line: intPtr(3)},
+		{ID: "3", FilePath: "specledger/001-x/plan.md", SelectedText: "x", Line: intPtr(1)},
+		{ID: "4", FilePath: "specledger/001-x/spec.md", Content: "general"},
+	}
+	ReanchorComments(root, comments)
+
+	if comments[0].AnchorStatus != AnchorMoved || *comments[0].Line != 3 {
+		t.Errorf("line comment: status %q line %d, want moved to 3", comments[0].AnchorStatus, *comments[0].Line)
+	}
+	if comments[1].AnchorStatus != AnchorMoved || *comments[1].Line != 4 {
+		t.Errorf("selection comment: status %q line %d, want moved to 4", comments[1].AnchorStatus, *comments[1].Line)
+	}
+	if comments[2].AnchorStatus != AnchorOutdated {
+		t.Errorf("missing file: status %q, want outdated", comments[2].AnchorStatus)
+	}
+	if comments[3].AnchorStatus != "" {
+		t.Errorf("general comment: status %q, want none", comments[3].AnchorStatus)
+	}
+}
+
+func TestFindFuzzyWindow(t *testing.T) {
+	lines := make([]string, 300)
+	for i := range lines {
+		lines[i] = "filler"
+	}
+	lines[249] = "Tokens expire after one hour."
+
+	if _, _, ok := findFuzzy(lines, "Tokens expire after 1 hour.", 10); ok {
+		t.Error("expected no match outside the window around line 10")
+	}
+	start, end, ok := findFuzzy(lines, "Tokens expire after 1 hour.", 220)
+	if !ok || start != 250 || end != 250 {
+		t.Errorf("findFuzzy() = %d-%d, %v, want 250", start, end, ok)
+	}
+	if start, _, ok := findFuzzy(lines, "Tokens expire after 1 hour.", 0); !ok || start != 250 {
+		t.Errorf("without an expected line the whole file is searched, got %d, %v", start, ok)
+	}
+}
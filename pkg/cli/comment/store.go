@@ -0,0 +1,204 @@
+package comment
+
+import "fmt"
+
+// Store is where the review comments of a spec are read and written: the
+// remote API (RemoteStore) or the spec's local review.jsonl (LocalStore).
+type Store interface {
+	// FetchComments returns the unresolved top-level comments.
+	FetchComments() ([]ReviewComment, error)
+	// FetchResolvedComments returns the resolved top-level comments.
+	FetchResolvedComments() ([]ReviewComment, error)
+	// FetchReplies returns the unresolved thread replies of all comments.
+	FetchReplies() ([]ReviewComment, error)
+	FetchCommentByID(commentID string) (*ReviewComment, error)
+	FetchRepliesByParentID(parentID string) ([]ReviewComment, error)
+	CreateComment(nc NewComment) (*ReviewComment, error)
+	CreateReply(parentID, content string) (*ThreadReply, error)
+	ResolveComment(commentID string) error
+	ResolveCommentWithReplies(commentID string, replyIDs []string) error
+}
+
+// RemoteStore is the Store of a change's comments in Supabase.
+// ChangeID may be empty for operations on comments by ID (show, reply,
+// resolve); listing and creating comments need it.
+type RemoteStore struct {
+	Client   *Client
+	ChangeID string
+}
+
+func NewRemoteStore(client *Client, changeID string) *RemoteStore {
+	return &RemoteStore{Client: client, ChangeID: changeID}
+}
+
+func (s *RemoteStore) changeID() (string, error) {
+	if s.ChangeID == "" {
+		return "", fmt.Errorf("no change selected")
+	}
+	return s.ChangeID, nil
+}
+
+func (s *RemoteStore) FetchComments() ([]ReviewComment, error) {
+	changeID, err := s.changeID()
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.Client.FetchComments(changeID)
+	for i := range comments {
+		comments[i].ChangeID = changeID
+	}
+	return comments, err
+}
+
+func (s *RemoteStore) FetchResolvedComments() ([]ReviewComment, error) {
+	changeID, err := s.changeID()
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.Client.FetchResolvedComments(changeID)
+	for i := range comments {
+		comments[i].ChangeID = changeID
+		comments[i].IsResolved = true
+	}
+	return comments, err
+}
+
+func (s *RemoteStore) FetchReplies() ([]ReviewComment, error) {
+	changeID, err := s.changeID()
+	if err != nil {
+		return nil, err
+	}
+	replies, err := s.Client.FetchReplies(changeID)
+	for i := range replies {
+		replies[i].ChangeID = changeID
+	}
+	return replies, err
+}
+
+func (s *RemoteStore) FetchCommentByID(commentID string) (*ReviewComment, error) {
+	return s.Client.FetchCommentByID(commentID)
+}
+
+func (s *RemoteStore) FetchRepliesByParentID(parentID string) ([]ReviewComment, error) {
+	return s.Client.FetchRepliesByParentID(parentID)
+}
+
+func (s *RemoteStore) CreateComment(nc NewComment) (*ReviewComment, error) {
+	if nc.ChangeID == "" {
+		changeID, err := s.changeID()
+		if err != nil {
+			return nil, err
+		}
+		nc.ChangeID = changeID
+	}
+	return s.Client.CreateComment(nc)
+}
+
+func (s *RemoteStore) CreateReply(parentID, content string) (*ThreadReply, error) {
+	return s.Client.CreateReply(parentID, content)
+}
+
+func (s *RemoteStore) ResolveComment(commentID string) error {
+	return s.Client.ResolveComment(commentID)
+}
+
+func (s *RemoteStore) ResolveCommentWithReplies(commentID string, replyIDs []string) error {
+	return s.Client.ResolveCommentWithReplies(commentID, replyIDs)
+}
+
+// CombinedStore reads the comments of a spec from its SpecLedger store
+// (Primary, may be nil) and a GitHub pull request together. Operations on a
+// comment go to the store it came from; new comments go to Primary.
+type CombinedStore struct {
+	Primary Store
+	GitHub  *GitHubStore
+}
+
+func NewCombinedStore(primary Store, github *GitHubStore) *CombinedStore {
+	return &CombinedStore{Primary: primary, GitHub: github}
+}
+
+func (s *CombinedStore) FetchComments() ([]ReviewComment, error) {
+	return s.fetchBoth(Store.FetchComments)
+}
+
+func (s *CombinedStore) FetchResolvedComments() ([]ReviewComment, error) {
+	return s.fetchBoth(Store.FetchResolvedComments)
+}
+
+func (s *CombinedStore) FetchReplies() ([]ReviewComment, error) {
+	return s.fetchBoth(Store.FetchReplies)
+}
+
+func (s *CombinedStore) FetchCommentByID(commentID string) (*ReviewComment, error) {
+	store, err := s.storeFor(commentID)
+	if err != nil {
+		return nil, err
+	}
+	return store.FetchCommentByID(commentID)
+}
+
+func (s *CombinedStore) FetchRepliesByParentID(parentID string) ([]ReviewComment, error) {
+	store, err := s.storeFor(parentID)
+	if err != nil {
+		return nil, err
+	}
+	return store.FetchRepliesByParentID(parentID)
+}
+
+func (s *CombinedStore) CreateComment(nc NewComment) (*ReviewComment, error) {
+	if s.Primary == nil {
+		return s.GitHub.CreateComment(nc)
+	}
+	return s.Primary.CreateComment(nc)
+}
+
+func (s *CombinedStore) CreateReply(parentID, content string) (*ThreadReply, error) {
+	store, err := s.storeFor(parentID)
+	if err != nil {
+		return nil, err
+	}
+	return store.CreateReply(parentID, content)
+}
+
+func (s *CombinedStore) ResolveComment(commentID string) error {
+	store, err := s.storeFor(commentID)
+	if err != nil {
+		return err
+	}
+	return store.ResolveComment(commentID)
+}
+
+func (s *CombinedStore) ResolveCommentWithReplies(commentID string, replyIDs []string) error {
+	store, err := s.storeFor(commentID)
+	if err != nil {
+		return err
+	}
+	return store.ResolveCommentWithReplies(commentID, replyIDs)
+}
+
+func (s *CombinedStore) storeFor(commentID string) (Store, error) {
+	if IsGitHubID(commentID) {
+		return s.GitHub, nil
+	}
+	if s.Primary == nil {
+		return nil, fmt.Errorf("%w: %s", ErrCommentNotFound, commentID)
+	}
+	return s.Primary, nil
+}
+
+func (s *CombinedStore) fetchBoth(fetch func(Store) ([]ReviewComment, error)) ([]ReviewComment, error) {
+	var comments []ReviewComment
+	if s.Primary != nil {
+		primary, err := fetch(s.Primary)
+		if err != nil {
+			return nil, err
+		}
+		comments = primary
+	}
+	github, err := fetch(s.GitHub)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub: %w", err)
+	}
+	return append(comments, github...), nil
+}
@@ -0,0 +1,260 @@
+package comment
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sync conflict reasons
+const (
+	ConflictDeletedRemotely = "deleted remotely"
+	ConflictEditedRemotely  = "edited remotely since last sync"
+	ConflictNewReplies      = "new remote replies since last sync"
+)
+
+// SyncConflict is a local change that was not pushed because the remote
+// comment changed. Local resolutions in conflict are reopened so the new
+// remote activity gets reviewed before resolving again.
+type SyncConflict struct {
+	CommentID string `json:"comment_id"`
+	FilePath  string `json:"file_path"`
+	Reason    string `json:"reason"`
+}
+
+// SyncResult is the outcome of Sync.
+type SyncResult struct {
+	Pushed    int            `json:"pushed"`   // comments and replies created remotely
+	Resolved  int            `json:"resolved"` // local resolutions applied remotely
+	Pulled    int            `json:"pulled"`   // remote comments added or updated locally
+	Removed   int            `json:"removed"`  // comments deleted remotely, removed locally
+	Conflicts []SyncConflict `json:"conflicts"`
+}
+
+// SyncOptions controls Sync.
+type SyncOptions struct {
+	// Force pushes local resolutions even when the remote comment changed.
+	Force bool
+}
+
+// Sync reconciles a spec's local store with the remote change: comments,
+// replies and resolutions made locally are pushed, then remote comments are
+// pulled. Progress is saved even when Sync fails part-way, so comments are
+// never pushed twice.
+func Sync(local *LocalStore, remote *RemoteStore, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{Conflicts: []SyncConflict{}}
+	err := local.withLock(func(comments []LocalComment) ([]LocalComment, error) {
+		comments, err := push(comments, remote, opts, result)
+		if err != nil {
+			return comments, err
+		}
+		return pull(comments, remote, result)
+	})
+	return result, err
+}
+
+func push(comments []LocalComment, remote *RemoteStore, opts SyncOptions, result *SyncResult) ([]LocalComment, error) {
+	// Comments and replies created locally, in creation order so parents
+	// are pushed before their replies
+	dropped := make(map[string]bool)
+	for i := range comments {
+		c := &comments[i]
+		if c.Synced {
+			continue
+		}
+		oldID := c.ID
+
+		if c.ParentCommentID == "" {
+			created, err := remote.CreateComment(NewComment{
+				ChangeID:     remote.ChangeID,
+				FilePath:     c.FilePath,
+				Content:      c.Content,
+				SelectedText: c.SelectedText,
+				Line:         c.Line,
+				StartLine:    c.StartLine,
+			})
+			if err != nil {
+				return comments, fmt.Errorf("failed to push comment %s: %w", oldID, err)
+			}
+			c.ID = created.ID
+			c.ChangeID = remote.ChangeID
+			c.CreatedAt = created.CreatedAt
+			c.UpdatedAt = created.UpdatedAt
+			c.RemoteUpdatedAt = created.UpdatedAt
+		} else {
+			if IsLocalID(c.ParentCommentID) {
+				continue // Parent not pushed
+			}
+			reply, err := remote.CreateReply(c.ParentCommentID, c.Content)
+			if errors.Is(err, ErrCommentNotFound) {
+				result.Conflicts = append(result.Conflicts, SyncConflict{CommentID: oldID, FilePath: c.FilePath, Reason: "parent " + ConflictDeletedRemotely})
+				dropped[oldID] = true
+				continue
+			}
+			if err != nil {
+				return comments, fmt.Errorf("failed to push reply %s: %w", oldID, err)
+			}
+			c.ID = reply.ID
+			c.CreatedAt = reply.CreatedAt
+			c.UpdatedAt = reply.CreatedAt
+		}
+		c.Synced = true
+		result.Pushed++
+
+		for j := range comments {
+			if comments[j].ParentCommentID == oldID {
+				comments[j].ParentCommentID = c.ID
+			}
+		}
+	}
+
+	if len(dropped) > 0 {
+		kept := comments[:0]
+		for _, c := range comments {
+			if !dropped[c.ID] {
+				kept = append(kept, c)
+			}
+		}
+		comments = kept
+	}
+
+	// Local resolutions
+	for i := range comments {
+		c := &comments[i]
+		if !c.PendingResolve || !c.Synced {
+			continue
+		}
+
+		current, err := remote.FetchCommentByID(c.ID)
+		if errors.Is(err, ErrCommentNotFound) {
+			result.Conflicts = append(result.Conflicts, SyncConflict{CommentID: c.ID, FilePath: c.FilePath, Reason: ConflictDeletedRemotely})
+			c.PendingResolve = false // Dropped by pull
+			continue
+		}
+		if err != nil {
+			return comments, fmt.Errorf("failed to fetch comment %s: %w", c.ID, err)
+		}
+		if current.IsResolved {
+			c.PendingResolve = false
+			continue
+		}
+
+		replies, err := remote.FetchRepliesByParentID(c.ID)
+		if err != nil {
+			return comments, fmt.Errorf("failed to fetch replies of %s: %w", c.ID, err)
+		}
+
+		if !opts.Force {
+			reason := ""
+			if c.RemoteUpdatedAt != "" && current.UpdatedAt != "" && current.UpdatedAt != c.RemoteUpdatedAt {
+				reason = ConflictEditedRemotely
+			} else if hasUnknownReplies(comments, replies) {
+				reason = ConflictNewReplies
+			}
+			if reason != "" {
+				result.Conflicts = append(result.Conflicts, SyncConflict{CommentID: c.ID, FilePath: c.FilePath, Reason: reason})
+				c.PendingResolve = false
+				c.IsResolved = false
+				continue
+			}
+		}
+
+		replyIDs := make([]string, 0, len(replies))
+		for _, r := range replies {
+			replyIDs = append(replyIDs, r.ID)
+		}
+		if len(replyIDs) > 0 {
+			err = remote.ResolveCommentWithReplies(c.ID, replyIDs)
+		} else {
+			err = remote.ResolveComment(c.ID)
+		}
+		if err != nil {
+			return comments, fmt.Errorf("failed to resolve comment %s: %w", c.ID, err)
+		}
+		c.PendingResolve = false
+		result.Resolved++
+	}
+
+	return comments, nil
+}
+
+func pull(comments []LocalComment, remote *RemoteStore, result *SyncResult) ([]LocalComment, error) {
+	open, err := remote.FetchComments()
+	if err != nil {
+		return comments, fmt.Errorf("failed to fetch comments: %w", err)
+	}
+	resolved, err := remote.FetchResolvedComments()
+	if err != nil {
+		return comments, fmt.Errorf("failed to fetch resolved comments: %w", err)
+	}
+	replies, err := remote.FetchReplies()
+	if err != nil {
+		return comments, fmt.Errorf("failed to fetch replies: %w", err)
+	}
+
+	remoteByID := make(map[string]ReviewComment)
+	for _, list := range [][]ReviewComment{open, resolved, replies} {
+		for _, rc := range list {
+			remoteByID[rc.ID] = rc
+		}
+	}
+
+	merged := make([]LocalComment, 0, len(comments)+len(remoteByID))
+	seen := make(map[string]bool)
+	for _, c := range comments {
+		if !c.Synced {
+			merged = append(merged, c) // Not pushed yet (parent pending or deleted)
+			continue
+		}
+
+		rc, ok := remoteByID[c.ID]
+		if !ok {
+			// Resolved replies aren't fetched: keep replies of resolved threads
+			if c.ParentCommentID != "" {
+				if parent, ok := remoteByID[c.ParentCommentID]; ok && parent.IsResolved {
+					c.IsResolved = true
+					merged = append(merged, c)
+					seen[c.ID] = true
+					continue
+				}
+			}
+			result.Removed++
+			continue
+		}
+
+		seen[c.ID] = true
+		updated := LocalComment{ReviewComment: rc, Synced: true, RemoteUpdatedAt: rc.UpdatedAt}
+		if c.PendingResolve {
+			// Resolution not pushed (e.g. a failed sync): keep it
+			updated.IsResolved = true
+			updated.PendingResolve = true
+		}
+		if updated.Content != c.Content || updated.IsResolved != c.IsResolved || (updated.UpdatedAt != "" && updated.UpdatedAt != c.UpdatedAt) {
+			result.Pulled++
+		}
+		merged = append(merged, updated)
+	}
+
+	for _, list := range [][]ReviewComment{open, resolved, replies} {
+		for _, rc := range list {
+			if seen[rc.ID] {
+				continue
+			}
+			seen[rc.ID] = true
+			merged = append(merged, LocalComment{ReviewComment: rc, Synced: true, RemoteUpdatedAt: rc.UpdatedAt})
+			result.Pulled++
+		}
+	}
+
+	return merged, nil
+}
+
+// hasUnknownReplies reports whether a remote thread has replies the local
+// store has never seen.
+func hasUnknownReplies(comments []LocalComment, replies []ReviewComment) bool {
+	for _, r := range replies {
+		if findLocal(comments, r.ID) == nil {
+			return true
+		}
+	}
+	return false
+}
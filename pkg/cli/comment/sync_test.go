@@ -0,0 +1,233 @@
+package comment
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/specledger/specledger/pkg/cli/auth"
+)
+
+// fakeReviewAPI is an in-memory review_comments table behind the PostgREST
+// filters used by Client.
+type fakeReviewAPI struct {
+	mu       sync.Mutex
+	rows     []ReviewComment
+	nextID   int
+	resolves int
+}
+
+func (f *fakeReviewAPI) add(c ReviewComment) ReviewComment {
+	f.nextID++
+	c.ID = fmt.Sprintf("r%d", f.nextID)
+	c.CreatedAt = fmt.Sprintf("2026-01-01T00:00:%02dZ", f.nextID)
+	c.UpdatedAt = c.CreatedAt
+	f.rows = append(f.rows, c)
+	return c
+}
+
+func (f *fakeReviewAPI) find(id string) *ReviewComment {
+	for i := range f.rows {
+		if f.rows[i].ID == id {
+			return &f.rows[i]
+		}
+	}
+	return nil
+}
+
+func (f *fakeReviewAPI) matches(c ReviewComment, q map[string][]string) bool {
+	for key, values := range q {
+		v := values[0]
+		switch key {
+		case "id":
+			if strings.HasPrefix(v, "in.(") {
+				ids := strings.Split(strings.TrimSuffix(strings.TrimPrefix(v, "in.("), ")"), ",")
+				found := false
+				for _, id := range ids {
+					found = found || id == c.ID
+				}
+				if !found {
+					return false
+				}
+			} else if v != "eq."+c.ID {
+				return false
+			}
+		case "change_id":
+			if v != "eq."+c.ChangeID {
+				return false
+			}
+		case "is_resolved":
+			if v != fmt.Sprintf("eq.%t", c.IsResolved) {
+				return false
+			}
+		case "parent_comment_id":
+			switch {
+			case v == "is.null" && c.ParentCommentID != "",
+				v == "not.is.null" && c.ParentCommentID == "",
+				strings.HasPrefix(v, "eq.") && v != "eq."+c.ParentCommentID:
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (f *fakeReviewAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	q := r.URL.Query()
+	switch r.Method {
+	case http.MethodGet:
+		result := []ReviewComment{}
+		for _, c := range f.rows {
+			if f.matches(c, q) {
+				result = append(result, c)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	case http.MethodPost:
+		var body ReviewComment
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		created := f.add(body)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode([]ReviewComment{created})
+	case http.MethodPatch:
+		for i := range f.rows {
+			if f.matches(f.rows[i], q) {
+				f.rows[i].IsResolved = true
+			}
+		}
+		f.resolves++
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newFakeRemote(t *testing.T) (*fakeReviewAPI, *RemoteStore) {
+	t.Helper()
+	api := &fakeReviewAPI{}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	client := newTestClient(srv.URL, "token", &mockAuthProvider{creds: &auth.Credentials{UserID: "u1"}})
+	return api, NewRemoteStore(client, "ch1")
+}
+
+func TestSync_PushesLocalCommentsAndPullsRemote(t *testing.T) {
+	api, remote := newFakeRemote(t)
+	api.add(ReviewComment{ChangeID: "ch1", FilePath: "plan.md", Content: "remote comment"})
+
+	local := NewLocalStore(t.TempDir(), "010-auth")
+	line := 4
+	c, _ := local.CreateComment(NewComment{FilePath: "spec.md", Content: "offline comment", Line: &line})
+	_, _ = local.CreateReply(c.ID, "offline reply")
+
+	result, err := Sync(local, remote, SyncOptions{})
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if result.Pushed != 2 || result.Pulled != 1 || len(result.Conflicts) != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	all, _ := local.All()
+	if len(all) != 3 {
+		t.Fatalf("expected 3 local records, got %d", len(all))
+	}
+	for _, lc := range all {
+		if !lc.Synced || IsLocalID(lc.ID) || IsLocalID(lc.ParentCommentID) {
+			t.Errorf("record not synced: %+v", lc)
+		}
+	}
+	if len(api.rows) != 3 {
+		t.Fatalf("expected 3 remote rows, got %d", len(api.rows))
+	}
+
+	// A second sync is a no-op
+	result, err = Sync(local, remote, SyncOptions{})
+	if err != nil || result.Pushed != 0 || result.Pulled != 0 || len(api.rows) != 3 {
+		t.Fatalf("second Sync() = %+v, %v (rows %d)", result, err, len(api.rows))
+	}
+}
+
+func TestSync_ResolveAndConflicts(t *testing.T) {
+	api, remote := newFakeRemote(t)
+	quiet := api.add(ReviewComment{ChangeID: "ch1", FilePath: "spec.md", Content: "quiet"})
+	busy := api.add(ReviewComment{ChangeID: "ch1", FilePath: "spec.md", Content: "busy"})
+	edited := api.add(ReviewComment{ChangeID: "ch1", FilePath: "plan.md", Content: "edited"})
+
+	local := NewLocalStore(t.TempDir(), "010-auth")
+	if _, err := Sync(local, remote, SyncOptions{}); err != nil {
+		t.Fatalf("initial Sync() error: %v", err)
+	}
+
+	for _, id := range []string{quiet.ID, busy.ID, edited.ID} {
+		if err := local.ResolveComment(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Meanwhile, remotely: a new reply on one, an edit on another
+	api.add(ReviewComment{ChangeID: "ch1", FilePath: "spec.md", Content: "wait", ParentCommentID: busy.ID})
+	api.find(edited.ID).UpdatedAt = "2026-02-01T00:00:00Z"
+
+	result, err := Sync(local, remote, SyncOptions{})
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if result.Resolved != 1 || len(result.Conflicts) != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	reasons := map[string]string{}
+	for _, c := range result.Conflicts {
+		reasons[c.CommentID] = c.Reason
+	}
+	if reasons[busy.ID] != ConflictNewReplies || reasons[edited.ID] != ConflictEditedRemotely {
+		t.Fatalf("unexpected conflicts: %+v", result.Conflicts)
+	}
+	if !api.find(quiet.ID).IsResolved || api.find(busy.ID).IsResolved || api.find(edited.ID).IsResolved {
+		t.Fatal("only the comment without remote activity should be resolved remotely")
+	}
+
+	// Conflicting comments are reopened locally, with the remote activity pulled
+	open, _ := local.FetchComments()
+	replies, _ := local.FetchReplies()
+	if len(open) != 2 || len(replies) != 1 {
+		t.Fatalf("expected 2 reopened comments and 1 pulled reply, got %d and %d", len(open), len(replies))
+	}
+
+	// Resolving again after review goes through
+	_ = local.ResolveComment(busy.ID)
+	if result, err = Sync(local, remote, SyncOptions{}); err != nil || result.Resolved != 1 {
+		t.Fatalf("Sync() after review = %+v, %v", result, err)
+	}
+	if !api.find(busy.ID).IsResolved {
+		t.Fatal("expected comment resolved remotely")
+	}
+}
+
+func TestSync_RemoteDeletion(t *testing.T) {
+	api, remote := newFakeRemote(t)
+	gone := api.add(ReviewComment{ChangeID: "ch1", FilePath: "spec.md", Content: "to delete"})
+
+	local := NewLocalStore(t.TempDir(), "010-auth")
+	if _, err := Sync(local, remote, SyncOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = local.CreateReply(gone.ID, "reply to a deleted comment")
+	api.rows = nil
+
+	result, err := Sync(local, remote, SyncOptions{})
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if result.Removed != 1 || len(result.Conflicts) != 1 || result.Pushed != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if all, _ := local.All(); len(all) != 0 {
+		t.Fatalf("expected empty local store, got %+v", all)
+	}
+}
@@ -15,6 +15,9 @@ type ReviewComment struct {
 	ParentCommentID string `json:"parent_comment_id"`
 	CreatedAt       string `json:"created_at"`
 	UpdatedAt       string `json:"updated_at"`
+
+	// AnchorStatus is set by ReanchorComments (not stored)
+	AnchorStatus string `json:"-"`
 }
 
 type ThreadReply struct {
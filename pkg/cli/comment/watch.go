@@ -0,0 +1,106 @@
+package comment
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ActivitySource is a store that can list the comments and replies created
+// since a point in time, for Watcher.
+type ActivitySource interface {
+	FetchActivity(since, etag string) (*ActivityPage, error)
+}
+
+func (s *RemoteStore) FetchActivity(since, etag string) (*ActivityPage, error) {
+	changeID, err := s.changeID()
+	if err != nil {
+		return nil, err
+	}
+	return s.Client.FetchActivity(changeID, since, etag)
+}
+
+// FetchActivity lists the local comments created at or after since. The
+// ETag is derived from the size and modification time of the store file.
+func (s *LocalStore) FetchActivity(since, etag string) (*ActivityPage, error) {
+	info, err := os.Stat(s.path)
+	if os.IsNotExist(err) {
+		return &ActivityPage{Comments: []ReviewComment{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", s.path, err)
+	}
+
+	tag := fmt.Sprintf(`W/"%d-%d"`, info.Size(), info.ModTime().UnixNano())
+	if etag == tag {
+		return &ActivityPage{ETag: tag, NotModified: true}, nil
+	}
+
+	all, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	page := &ActivityPage{ETag: tag, Comments: []ReviewComment{}}
+	for _, c := range all {
+		if since == "" || compareTimestamps(c.CreatedAt, since) >= 0 {
+			page.Comments = append(page.Comments, c.ReviewComment)
+		}
+	}
+	return page, nil
+}
+
+// Watcher reports new comments and replies across polls, using the ETag of
+// the previous poll and a cursor on the newest created_at seen.
+type Watcher struct {
+	source ActivitySource
+	cursor string          // created_at of the newest comment seen
+	seen   map[string]bool // IDs seen with created_at == cursor
+	etag   string
+}
+
+// NewWatcher returns a Watcher reporting activity created at or after since
+// (RFC 3339; "" for everything).
+func NewWatcher(source ActivitySource, since string) *Watcher {
+	return &Watcher{source: source, cursor: since, seen: make(map[string]bool)}
+}
+
+// Poll returns the comments and replies created since the previous poll,
+// oldest first.
+func (w *Watcher) Poll() ([]ReviewComment, error) {
+	page, err := w.source.FetchActivity(w.cursor, w.etag)
+	if err != nil {
+		return nil, err
+	}
+	if page.NotModified {
+		return nil, nil
+	}
+	w.etag = page.ETag
+
+	var fresh []ReviewComment
+	for _, c := range page.Comments {
+		cmp := compareTimestamps(c.CreatedAt, w.cursor)
+		if w.cursor != "" && (cmp < 0 || (cmp == 0 && w.seen[c.ID])) {
+			continue
+		}
+		fresh = append(fresh, c)
+
+		if w.cursor == "" || cmp > 0 {
+			w.cursor = c.CreatedAt
+			w.seen = make(map[string]bool)
+		}
+		w.seen[c.ID] = true
+	}
+	return fresh, nil
+}
+
+// compareTimestamps compares two RFC 3339 timestamps, as text when they
+// don't parse.
+func compareTimestamps(a, b string) int {
+	ta, errA := time.Parse(time.RFC3339Nano, a)
+	tb, errB := time.Parse(time.RFC3339Nano, b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	return ta.Compare(tb)
+}
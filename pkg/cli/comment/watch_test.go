@@ -0,0 +1,109 @@
+package comment
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeActivity is an ActivitySource over an in-memory list of comments.
+type fakeActivity struct {
+	comments []ReviewComment
+	version  int
+	sinces   []string
+}
+
+func (f *fakeActivity) FetchActivity(since, etag string) (*ActivityPage, error) {
+	f.sinces = append(f.sinces, since)
+	tag := fmt.Sprintf("v%d", f.version)
+	if etag == tag {
+		return &ActivityPage{ETag: tag, NotModified: true}, nil
+	}
+	page := &ActivityPage{ETag: tag}
+	for _, c := range f.comments {
+		if since == "" || compareTimestamps(c.CreatedAt, since) >= 0 {
+			page.Comments = append(page.Comments, c)
+		}
+	}
+	return page, nil
+}
+
+func (f *fakeActivity) add(c ReviewComment) {
+	f.comments = append(f.comments, c)
+	f.version++
+}
+
+func watchIDs(comments []ReviewComment) string {
+	ids := make([]string, 0, len(comments))
+	for _, c := range comments {
+		ids = append(ids, c.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	source := &fakeActivity{}
+	source.add(ReviewComment{ID: "c1", CreatedAt: "2026-01-15T10:00:00Z"})
+	source.add(ReviewComment{ID: "c2", CreatedAt: "2026-01-15T11:00:00Z"})
+
+	w := NewWatcher(source, "2026-01-15T10:30:00Z")
+
+	got, err := w.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if watchIDs(got) != "c2" {
+		t.Fatalf("first poll = %q, want c2", watchIDs(got))
+	}
+
+	// Unchanged: not modified
+	if got, _ = w.Poll(); len(got) != 0 {
+		t.Fatalf("unchanged poll = %q, want nothing", watchIDs(got))
+	}
+
+	// A reply with the same timestamp as the cursor is new; c2 is not
+	source.add(ReviewComment{ID: "r1", ParentCommentID: "c2", CreatedAt: "2026-01-15T11:00:00Z"})
+	source.add(ReviewComment{ID: "c3", CreatedAt: "2026-01-15T12:00:00Z"})
+	if got, _ = w.Poll(); watchIDs(got) != "r1,c3" {
+		t.Fatalf("third poll = %q, want r1,c3", watchIDs(got))
+	}
+
+	if last := source.sinces[len(source.sinces)-1]; last != "2026-01-15T11:00:00Z" {
+		t.Errorf("third poll since = %q, want the cursor of the second", last)
+	}
+}
+
+func TestFetchActivity_NotModified(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("created_at"); got != "gte.2026-01-15T10:00:00Z" {
+			t.Errorf("created_at filter = %q", got)
+		}
+		if r.Header.Get("If-None-Match") == `"abc"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"abc"`)
+		fmt.Fprint(w, `[{"id":"c1","file_path":"spec.md","created_at":"2026-01-15T10:00:00Z"}]`)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL, "token", &mockAuthProvider{})
+
+	page, err := client.FetchActivity("ch1", "2026-01-15T10:00:00Z", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.NotModified || page.ETag != `"abc"` || len(page.Comments) != 1 || page.Comments[0].ChangeID != "ch1" {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+
+	page, err = client.FetchActivity("ch1", "2026-01-15T10:00:00Z", page.ETag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !page.NotModified || len(page.Comments) != 0 {
+		t.Fatalf("expected not modified, got %+v", page)
+	}
+}
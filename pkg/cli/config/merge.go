@@ -272,6 +272,24 @@ func ResolveAgentConfig() *ResolvedConfig {
 	)
 }
 
+// ResolveSensitiveValues returns the values of sensitive keys in the resolved
+// agent configuration plus the per-agent API keys, so callers can scrub them
+// from output that leaves the machine.
+func ResolveSensitiveValues() []string {
+	var values []string
+	for _, v := range ResolveAgentConfig().Values {
+		if s, ok := v.Value.(string); ok && v.Sensitive && s != "" {
+			values = append(values, s)
+		}
+	}
+	for _, name := range []string{"claude", "opencode", "github-copilot", "codex"} {
+		if s := ResolveAgentSettings(name); s != nil && s.APIKey != "" {
+			values = append(values, s.APIKey)
+		}
+	}
+	return values
+}
+
 func loadProjectMetadata(projectPath string) (*struct {
 	Agent *AgentConfig `yaml:"agent,omitempty"`
 }, error) {
@@ -11,6 +11,8 @@ import (
 type PersonalConfig struct {
 	Agent         *AgentConfig `yaml:"agent,omitempty"`
 	ActiveProfile string       `yaml:"active-profile,omitempty"`
+	// Replace points dependency aliases at local checkouts, like go.mod's replace directive
+	Replace map[string]string `yaml:"replace,omitempty"`
 }
 
 func LoadPersonal(projectPath string) (*PersonalConfig, error) {
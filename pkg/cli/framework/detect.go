@@ -9,8 +9,16 @@ import (
 	"strings"
 
 	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/deps"
 )
 
+// markerPaths are the repository paths hasSpecKitMarkers and
+// hasOpenSpecMarkers look at
+var markerPaths = []string{
+	".specify/", "specify.yaml", "spec-kit-version", "*[Ss][Pp][Ee][Cc][Kk][Ii][Tt]*.md",
+	".openspec/", "openspec.yaml", "*[Oo][Pp][Ee][Nn][Ss][Pp][Ee][Cc]*.md",
+}
+
 // DetectFramework detects which SDD framework a remote repository uses
 func DetectFramework(repoURL string) (metadata.FrameworkChoice, error) {
 	// Clone the repo to a temporary directory
@@ -27,18 +35,47 @@ func DetectFramework(repoURL string) (metadata.FrameworkChoice, error) {
 		return metadata.FrameworkNone, fmt.Errorf("git clone failed: %w\nOutput: %s", err, string(output))
 	}
 
+	return DetectLocalFramework(repoPath), nil
+}
+
+// DetectFrameworkSparse detects which SDD framework a remote repository uses
+// from a blob-less sparse clone of the framework markers only, for
+// dependencies that are cached sparsely. Requires the git CLI.
+func DetectFrameworkSparse(repoURL, branch string) (metadata.FrameworkChoice, error) {
+	tempDir, err := os.MkdirTemp("", "specledger-detect-*")
+	if err != nil {
+		return metadata.FrameworkNone, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	repoPath := filepath.Join(tempDir, "repo")
+	if _, _, err := deps.Clone(deps.CloneOptions{
+		URL:         repoURL,
+		Branch:      branch,
+		TargetDir:   repoPath,
+		Shallow:     true,
+		SparsePaths: markerPaths,
+	}); err != nil {
+		return metadata.FrameworkNone, err
+	}
+
+	return DetectLocalFramework(repoPath), nil
+}
+
+// DetectLocalFramework detects which SDD framework a local directory uses, without cloning
+func DetectLocalFramework(repoPath string) metadata.FrameworkChoice {
 	// Check for Spec Kit indicators
 	if hasSpecKitMarkers(repoPath) {
-		return metadata.FrameworkSpecKit, nil
+		return metadata.FrameworkSpecKit
 	}
 
 	// Check for OpenSpec indicators
 	if hasOpenSpecMarkers(repoPath) {
-		return metadata.FrameworkOpenSpec, nil
+		return metadata.FrameworkOpenSpec
 	}
 
 	// Default to none if no framework detected
-	return metadata.FrameworkNone, nil
+	return metadata.FrameworkNone
 }
 
 // hasSpecKitMarkers checks if a repository uses Spec Kit
@@ -68,6 +68,54 @@ func GetRepoOwnerName(repoPath string) (owner, name string, err error) {
 	return m[1], m[2], nil
 }
 
+// GetOriginURL returns the first URL of the origin remote.
+func GetOriginURL(repoPath string) (string, error) {
+	repo, err := openRepo(repoPath)
+	if err != nil {
+		return "", err
+	}
+
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return "", fmt.Errorf("no 'origin' remote found: %w", err)
+	}
+
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return "", fmt.Errorf("origin remote has no URLs")
+	}
+	return urls[0], nil
+}
+
+// GetRepoRoot returns the absolute path of the working tree root containing repoPath.
+func GetRepoRoot(repoPath string) (string, error) {
+	repo, err := openRepo(repoPath)
+	if err != nil {
+		return "", err
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	return wt.Filesystem.Root(), nil
+}
+
+// GetUserIdentity returns the configured git user.name and user.email (empty when unset).
+func GetUserIdentity(repoPath string) (name, email string) {
+	get := func(key string) string {
+		cmd := exec.Command("git", "config", key)
+		cmd.Dir = repoPath
+		out, err := cmd.Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(out))
+	}
+	return get("user.name"), get("user.email")
+}
+
 // BranchExists reports whether a local branch with the given name exists.
 func BranchExists(repoPath, name string) (bool, error) {
 	repo, err := openRepo(repoPath)
@@ -0,0 +1,83 @@
+package hooks
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+)
+
+// SessionCaptureAgents lists the agents whose session capture hook can be
+// installed, matching the commands in internal/agent.Registry.
+var SessionCaptureAgents = []string{"claude", "opencode", "github-copilot", "codex"}
+
+// InstallSessionCaptureHookFor installs the session capture hook for an agent.
+// Copilot hooks live in the project (projectDir); the others are per user.
+// Returns whether a new hook was added and where it is configured.
+func InstallSessionCaptureHookFor(agent, projectDir string) (bool, string, error) {
+	switch agent {
+	case "", "claude":
+		installed, err := InstallSessionCaptureHook()
+		return installed, "~/.claude/settings.json", err
+	case "codex":
+		return installCodexHook()
+	case "github-copilot":
+		return installCopilotHook(projectDir)
+	case "opencode":
+		return installOpenCodeHook()
+	default:
+		return false, "", unsupportedAgentError(agent)
+	}
+}
+
+// UninstallSessionCaptureHookFor removes the session capture hook for an agent.
+// Returns whether a hook was found and removed.
+func UninstallSessionCaptureHookFor(agent, projectDir string) (bool, error) {
+	switch agent {
+	case "", "claude":
+		return UninstallSessionCaptureHook()
+	case "codex":
+		return uninstallCodexHook()
+	case "github-copilot":
+		return uninstallCopilotHook(projectDir)
+	case "opencode":
+		return uninstallOpenCodeHook()
+	default:
+		return false, unsupportedAgentError(agent)
+	}
+}
+
+// HasSessionCaptureHookFor checks if the session capture hook is installed for an agent
+func HasSessionCaptureHookFor(agent, projectDir string) (bool, error) {
+	switch agent {
+	case "", "claude":
+		settings, err := LoadClaudeSettings()
+		if err != nil {
+			return false, err
+		}
+		return HasSessionCaptureHook(settings), nil
+	case "codex":
+		return hasCodexHook()
+	case "github-copilot":
+		return hasCopilotHook(projectDir), nil
+	case "opencode":
+		return hasOpenCodeHook(), nil
+	default:
+		return false, unsupportedAgentError(agent)
+	}
+}
+
+func unsupportedAgentError(agent string) error {
+	return fmt.Errorf("unsupported agent: %s (valid: claude, opencode, github-copilot, codex)", agent)
+}
+
+// getSlBinary returns the full path of the sl binary if it can be found,
+// otherwise "sl"
+func getSlBinary() string {
+	if path, err := exec.LookPath("sl"); err == nil {
+		if abs, err := filepath.Abs(path); err == nil {
+			return abs
+		}
+		return path
+	}
+	return "sl"
+}
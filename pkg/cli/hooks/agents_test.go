@@ -0,0 +1,138 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddCodexNotify(t *testing.T) {
+	line := codexNotifyLine("/usr/local/bin/sl")
+	if line != `notify = ["/usr/local/bin/sl", "session", "capture", "--agent", "codex"]` {
+		t.Fatalf("unexpected notify line: %s", line)
+	}
+
+	tests := []struct {
+		name    string
+		config  string
+		want    string
+		changed bool
+		wantErr bool
+	}{
+		{
+			name:    "empty config",
+			config:  "",
+			want:    line + "\n",
+			changed: true,
+		},
+		{
+			name:    "inserted before first table",
+			config:  "model = \"o3\"\n\n[mcp_servers.docs]\ncommand = \"docs\"\n",
+			want:    "model = \"o3\"\n\n" + line + "\n\n[mcp_servers.docs]\ncommand = \"docs\"\n",
+			changed: true,
+		},
+		{
+			name:    "appended without tables",
+			config:  "model = \"o3\"\n",
+			want:    "model = \"o3\"\n" + line + "\n",
+			changed: true,
+		},
+		{
+			name:   "already installed",
+			config: line + "\n",
+			want:   line + "\n",
+		},
+		{
+			name:    "notify inside a table is not top-level",
+			config:  "[tui]\nnotify = true\n",
+			want:    line + "\n\n[tui]\nnotify = true\n",
+			changed: true,
+		},
+		{
+			name:    "other notify program",
+			config:  "notify = [\"notify-send\", \"codex\"]\n",
+			want:    "notify = [\"notify-send\", \"codex\"]\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, err := addCodexNotify(tt.config, line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("addCodexNotify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || changed != tt.changed {
+				t.Errorf("addCodexNotify() = %q, %v; want %q, %v", got, changed, tt.want, tt.changed)
+			}
+		})
+	}
+}
+
+func TestRemoveCodexNotify(t *testing.T) {
+	line := codexNotifyLine("sl")
+	original := "model = \"o3\"\n\n[mcp_servers.docs]\ncommand = \"docs\"\n"
+
+	installed, _, err := addCodexNotify(original, line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, removed := removeCodexNotify(installed)
+	if !removed || got != original {
+		t.Errorf("removeCodexNotify() = %q, %v; want %q", got, removed, original)
+	}
+
+	if _, removed := removeCodexNotify("notify = [\"notify-send\"]\n"); removed {
+		t.Error("expected other notify programs to be left alone")
+	}
+}
+
+func TestCopilotHookInstall(t *testing.T) {
+	projectDir := t.TempDir()
+
+	installed, location, err := InstallSessionCaptureHookFor("github-copilot", projectDir)
+	if err != nil || !installed || location != copilotHookFile {
+		t.Fatalf("install = %v, %q, %v", installed, location, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(projectDir, copilotHookFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"postToolUse"`) || !strings.Contains(string(data), "sl session capture --agent github-copilot") {
+		t.Errorf("unexpected hook file:\n%s", data)
+	}
+
+	if has, _ := HasSessionCaptureHookFor("github-copilot", projectDir); !has {
+		t.Error("expected hook to be detected")
+	}
+	if again, _, _ := InstallSessionCaptureHookFor("github-copilot", projectDir); again {
+		t.Error("expected second install to be a no-op")
+	}
+
+	removed, err := UninstallSessionCaptureHookFor("github-copilot", projectDir)
+	if err != nil || !removed {
+		t.Errorf("uninstall = %v, %v", removed, err)
+	}
+}
+
+func TestOpenCodeHookInstall(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	installed, location, err := InstallSessionCaptureHookFor("opencode", "")
+	if err != nil || !installed {
+		t.Fatalf("install = %v, %v", installed, err)
+	}
+	data, err := os.ReadFile(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "session capture --agent opencode") {
+		t.Errorf("unexpected plugin:\n%s", data)
+	}
+
+	if _, _, err := InstallSessionCaptureHookFor("cursor", ""); err == nil {
+		t.Error("expected error for unsupported agent")
+	}
+}
@@ -1,4 +1,4 @@
-// Package hooks provides Claude Code hook management functionality.
+// Package hooks manages the session capture hooks of the supported coding agents.
 package hooks
 
 import (
@@ -0,0 +1,164 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Codex has no tool hooks. Its top-level "notify" setting in
+// $CODEX_HOME/config.toml names a program that receives a JSON payload as its
+// last argument after every agent turn.
+
+// codexConfigPath returns $CODEX_HOME/config.toml (default ~/.codex/config.toml)
+func codexConfigPath() (string, error) {
+	if home := os.Getenv("CODEX_HOME"); home != "" {
+		return filepath.Join(home, "config.toml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".codex", "config.toml"), nil
+}
+
+// codexNotifyLine returns the notify setting that runs sl session capture
+func codexNotifyLine(slBinary string) string {
+	args := []string{slBinary, "session", "capture", "--agent", "codex"}
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = strconv.Quote(arg)
+	}
+	return "notify = [" + strings.Join(quoted, ", ") + "]"
+}
+
+// findCodexNotify returns the index of the top-level notify line, or -1.
+// Keys after the first [table] header belong to that table.
+func findCodexNotify(lines []string) int {
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			return -1
+		}
+		if key, _, ok := strings.Cut(trimmed, "="); ok && strings.TrimSpace(key) == "notify" {
+			return i
+		}
+	}
+	return -1
+}
+
+// isCodexCaptureNotify checks if a notify line runs sl session capture
+func isCodexCaptureNotify(line string) bool {
+	return strings.Contains(line, `"session", "capture"`) || strings.Contains(line, `"session","capture"`)
+}
+
+// addCodexNotify adds the notify line to a config.toml document.
+// Returns the new document and whether it changed. A notify program other
+// than sl is an error since Codex only accepts one.
+func addCodexNotify(config, notifyLine string) (string, bool, error) {
+	lines := strings.Split(config, "\n")
+	if i := findCodexNotify(lines); i >= 0 {
+		if isCodexCaptureNotify(lines[i]) {
+			return config, false, nil
+		}
+		return config, false, fmt.Errorf("codex already has a notify program configured (%s); remove it or chain sl session capture --agent codex from it", strings.TrimSpace(lines[i]))
+	}
+
+	// Top-level keys must precede the first table
+	insertAt := len(lines)
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "[") {
+			insertAt = i
+			break
+		}
+	}
+
+	var out []string
+	out = append(out, lines[:insertAt]...)
+	if insertAt == len(lines) {
+		// Appending: drop the trailing empty element of a newline-terminated file
+		for len(out) > 0 && out[len(out)-1] == "" {
+			out = out[:len(out)-1]
+		}
+		out = append(out, notifyLine, "")
+	} else {
+		out = append(out, notifyLine, "")
+		out = append(out, lines[insertAt:]...)
+	}
+	return strings.Join(out, "\n"), true, nil
+}
+
+// removeCodexNotify removes sl's notify line from a config.toml document
+func removeCodexNotify(config string) (string, bool) {
+	lines := strings.Split(config, "\n")
+	i := findCodexNotify(lines)
+	if i < 0 || !isCodexCaptureNotify(lines[i]) {
+		return config, false
+	}
+	lines = append(lines[:i], lines[i+1:]...)
+	// Drop the blank line added after it on install
+	if i < len(lines) && lines[i] == "" && i > 0 && lines[i-1] == "" {
+		lines = append(lines[:i], lines[i+1:]...)
+	}
+	return strings.Join(lines, "\n"), true
+}
+
+func readCodexConfig() (string, string, error) {
+	path, err := codexConfigPath()
+	if err != nil {
+		return "", "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", "", fmt.Errorf("failed to read codex config: %w", err)
+	}
+	return path, string(data), nil
+}
+
+func installCodexHook() (bool, string, error) {
+	path, config, err := readCodexConfig()
+	if err != nil {
+		return false, "", err
+	}
+
+	updated, changed, err := addCodexNotify(config, codexNotifyLine(getSlBinary()))
+	if err != nil || !changed {
+		return false, path, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, path, fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(updated), 0600); err != nil {
+		return false, path, fmt.Errorf("failed to write codex config: %w", err)
+	}
+	return true, path, nil
+}
+
+func uninstallCodexHook() (bool, error) {
+	path, config, err := readCodexConfig()
+	if err != nil {
+		return false, err
+	}
+
+	updated, removed := removeCodexNotify(config)
+	if !removed {
+		return false, nil
+	}
+	if err := os.WriteFile(path, []byte(updated), 0600); err != nil {
+		return false, fmt.Errorf("failed to write codex config: %w", err)
+	}
+	return true, nil
+}
+
+func hasCodexHook() (bool, error) {
+	_, config, err := readCodexConfig()
+	if err != nil {
+		return false, err
+	}
+	lines := strings.Split(config, "\n")
+	i := findCodexNotify(lines)
+	return i >= 0 && isCodexCaptureNotify(lines[i]), nil
+}
@@ -0,0 +1,94 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Copilot CLI loads hooks from .github/hooks/*.json in the repository. sl owns
+// a single file there so installing and removing never touches other hooks.
+
+// copilotHookFile is the hook configuration file sl manages, relative to the project
+const copilotHookFile = ".github/hooks/specledger.json"
+
+// CopilotHooks is the Copilot CLI hook configuration format
+type CopilotHooks struct {
+	Version int                             `json:"version"`
+	Hooks   map[string][]CopilotHookCommand `json:"hooks"`
+}
+
+// CopilotHookCommand is a single command hook
+type CopilotHookCommand struct {
+	Type       string `json:"type"`
+	Bash       string `json:"bash,omitempty"`
+	PowerShell string `json:"powershell,omitempty"`
+	TimeoutSec int    `json:"timeoutSec,omitempty"`
+}
+
+// copilotHooksConfig returns the hook file content for sl session capture.
+// The command uses "sl" from PATH since the file is committed and shared.
+func copilotHooksConfig() *CopilotHooks {
+	command := "sl session capture --agent github-copilot"
+	return &CopilotHooks{
+		Version: 1,
+		Hooks: map[string][]CopilotHookCommand{
+			"postToolUse": {{
+				Type:       "command",
+				Bash:       command,
+				PowerShell: command,
+				TimeoutSec: 30,
+			}},
+		},
+	}
+}
+
+func installCopilotHook(projectDir string) (bool, string, error) {
+	if hasCopilotHook(projectDir) {
+		return false, copilotHookFile, nil
+	}
+
+	path := filepath.Join(projectDir, copilotHookFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, copilotHookFile, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(copilotHooksConfig(), "", "  ")
+	if err != nil {
+		return false, copilotHookFile, fmt.Errorf("failed to marshal hooks: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return false, copilotHookFile, fmt.Errorf("failed to write hooks: %w", err)
+	}
+	return true, copilotHookFile, nil
+}
+
+func uninstallCopilotHook(projectDir string) (bool, error) {
+	path := filepath.Join(projectDir, copilotHookFile)
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to remove hooks: %w", err)
+	}
+	return true, nil
+}
+
+func hasCopilotHook(projectDir string) bool {
+	data, err := os.ReadFile(filepath.Join(projectDir, copilotHookFile))
+	if err != nil {
+		return false
+	}
+	var config CopilotHooks
+	if err := json.Unmarshal(data, &config); err != nil {
+		return false
+	}
+	for _, hook := range config.Hooks["postToolUse"] {
+		if strings.Contains(hook.Bash, "session capture") {
+			return true
+		}
+	}
+	return false
+}
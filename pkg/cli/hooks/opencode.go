@@ -0,0 +1,100 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// OpenCode is extended with JavaScript plugins loaded from
+// ~/.config/opencode/plugin. sl's plugin forwards completed bash tool calls to
+// sl session capture in the same JSON shape as Claude Code's hook.
+
+// openCodePluginName is the plugin file sl manages
+const openCodePluginName = "specledger-session.js"
+
+// openCodePluginTemplate is the plugin source; %s is the quoted sl binary
+const openCodePluginTemplate = `// Installed by 'sl auth hook --install --agent opencode'. Do not edit.
+// Sends completed bash tool calls to sl session capture.
+const pending = new Map()
+
+export const SpecLedgerSession = async ({ $, directory }) => ({
+  "tool.execute.before": async (input, output) => {
+    if (input.tool === "bash") pending.set(input.callID, output.args)
+  },
+  "tool.execute.after": async (input, output) => {
+    if (input.tool !== "bash") return
+    const args = pending.get(input.callID) || {}
+    pending.delete(input.callID)
+    const payload = JSON.stringify({
+      session_id: input.sessionID,
+      cwd: directory,
+      hook_event_name: "PostToolUse",
+      tool_name: "Bash",
+      tool_input: { command: args.command || "" },
+      tool_response: { interrupted: false },
+    })
+    try {
+      await $` + "`echo ${payload} | ${%s} session capture --agent opencode`" + `.quiet()
+    } catch {}
+  },
+})
+`
+
+// openCodePluginPath returns the plugin path under $XDG_CONFIG_HOME
+// (default ~/.config)
+func openCodePluginPath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "opencode", "plugin", openCodePluginName), nil
+}
+
+func installOpenCodeHook() (bool, string, error) {
+	path, err := openCodePluginPath()
+	if err != nil {
+		return false, "", err
+	}
+	if hasOpenCodeHook() {
+		return false, path, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, path, fmt.Errorf("failed to create directory: %w", err)
+	}
+	plugin := fmt.Sprintf(openCodePluginTemplate, strconv.Quote(getSlBinary()))
+	if err := os.WriteFile(path, []byte(plugin), 0644); err != nil {
+		return false, path, fmt.Errorf("failed to write plugin: %w", err)
+	}
+	return true, path, nil
+}
+
+func uninstallOpenCodeHook() (bool, error) {
+	path, err := openCodePluginPath()
+	if err != nil {
+		return false, err
+	}
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to remove plugin: %w", err)
+	}
+	return true, nil
+}
+
+func hasOpenCodeHook() bool {
+	path, err := openCodePluginPath()
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), "session capture")
+}
@@ -25,8 +25,129 @@ type ProjectMetadata struct {
 	ActiveProfile   string                         `yaml:"active-profile,omitempty"`
 	// BranchAliases maps non-standard branch names to spec feature names (FR-012)
 	BranchAliases map[string]string `yaml:"branch_aliases,omitempty"`
+	// Session configures where captured AI sessions are stored
+	Session *SessionConfig `yaml:"session,omitempty"`
 }
 
+// SessionConfig configures session capture
+type SessionConfig struct {
+	Storage   SessionStorageConfig   `yaml:"storage,omitempty"`
+	Redaction SessionRedactionConfig `yaml:"redaction,omitempty"`
+	Retention SessionRetentionConfig `yaml:"retention,omitempty"`
+}
+
+// SessionRetentionConfig limits the local session cache in ~/.specledger.
+// Unset fields use the defaults below.
+type SessionRetentionConfig struct {
+	MaxAge       string `yaml:"max_age,omitempty"`       // e.g. 30d, 2w, 720h
+	MaxSize      string `yaml:"max_size,omitempty"`      // e.g. 500MB, 2GB
+	UploadedOnly *bool  `yaml:"uploaded_only,omitempty"` // only prune sessions already uploaded (default true)
+}
+
+// Session retention defaults
+const (
+	DefaultSessionMaxAge  = 30 * 24 * time.Hour
+	DefaultSessionMaxSize = 500 << 20
+)
+
+// MaxAgeDuration returns the configured maximum age, or the default
+func (c SessionRetentionConfig) MaxAgeDuration() (time.Duration, error) {
+	if c.MaxAge == "" {
+		return DefaultSessionMaxAge, nil
+	}
+	return ParseAge(c.MaxAge)
+}
+
+// MaxSizeBytes returns the configured maximum cache size, or the default
+func (c SessionRetentionConfig) MaxSizeBytes() (int64, error) {
+	if c.MaxSize == "" {
+		return DefaultSessionMaxSize, nil
+	}
+	return ParseByteSize(c.MaxSize)
+}
+
+// PruneUploadedOnly reports whether sessions still waiting for upload are
+// protected from pruning
+func (c SessionRetentionConfig) PruneUploadedOnly() bool {
+	return c.UploadedOnly == nil || *c.UploadedOnly
+}
+
+// Validate checks that the age and size parse
+func (c SessionRetentionConfig) Validate() error {
+	if _, err := c.MaxAgeDuration(); err != nil {
+		return fmt.Errorf("max_age: %w", err)
+	}
+	if _, err := c.MaxSizeBytes(); err != nil {
+		return fmt.Errorf("max_size: %w", err)
+	}
+	return nil
+}
+
+var agePattern = regexp.MustCompile(`^(\d+)([dw])$`)
+
+// ParseAge parses a duration that may also be given in days or weeks,
+// e.g. "30d", "2w" or "12h"
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if m := agePattern.FindStringSubmatch(s); m != nil {
+		var n int64
+		if _, err := fmt.Sscan(m[1], &n); err != nil {
+			return 0, err
+		}
+		unit := 24 * time.Hour
+		if m[2] == "w" {
+			unit *= 7
+		}
+		return time.Duration(n) * unit, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q (e.g. 30d, 2w, 12h)", s)
+	}
+	return d, nil
+}
+
+var byteSizePattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([KMGT]?)(?:I?B)?$`)
+
+// ParseByteSize parses a size such as "500MB", "1.5GB", "200K" or "1024"
+// using binary multiples
+func ParseByteSize(s string) (int64, error) {
+	m := byteSizePattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return 0, fmt.Errorf("invalid size %q (e.g. 500MB, 2GB)", s)
+	}
+	var n float64
+	if _, err := fmt.Sscan(m[1], &n); err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+	shift := map[string]uint{"": 0, "K": 10, "M": 20, "G": 30, "T": 40}[m[2]]
+	return int64(n * float64(int64(1)<<shift)), nil
+}
+
+// SessionRedactionConfig adds project-specific secret patterns to the built-in
+// redaction rules applied before sessions are uploaded.
+type SessionRedactionConfig struct {
+	Patterns []string `yaml:"patterns,omitempty"` // Go regular expressions
+}
+
+// SessionStorageConfig selects the session storage backend.
+// Credentials are never stored here; the S3 backend reads them from the environment.
+type SessionStorageConfig struct {
+	Backend  string `yaml:"backend,omitempty"`  // supabase (default), local, s3
+	Path     string `yaml:"path,omitempty"`     // local: directory relative to the project root
+	Endpoint string `yaml:"endpoint,omitempty"` // s3: e.g. https://s3.eu-west-1.amazonaws.com
+	Bucket   string `yaml:"bucket,omitempty"`   // s3
+	Region   string `yaml:"region,omitempty"`   // s3: defaults to us-east-1
+	Prefix   string `yaml:"prefix,omitempty"`   // s3: key prefix inside the bucket
+}
+
+// Session storage backends
+const (
+	SessionBackendSupabase = "supabase"
+	SessionBackendLocal    = "local"
+	SessionBackendS3       = "s3"
+)
+
 // ProjectInfo contains project identification
 type ProjectInfo struct {
 	ID        string    `yaml:"id,omitempty"` // Supabase project UUID (for session capture)
@@ -85,6 +206,7 @@ type Dependency struct {
 	ResolvedCommit string          `yaml:"resolved_commit,omitempty"`
 	Framework      FrameworkChoice `yaml:"framework,omitempty"`   // speckit, openspec, both, none
 	ImportPath     string          `yaml:"import_path,omitempty"` // @alias/spec format for AI imports
+	Sparse         bool            `yaml:"sparse,omitempty"`      // Cache only artifact_path and specledger.yaml
 }
 
 // ToolStatus represents runtime tool detection (not persisted)
@@ -132,7 +254,7 @@ func ValidateShortCode(code string) error {
 func ValidateGitURL(url string) error {
 	sshPattern := `^git@[^:]+:[^/]+/.+\.git$|^git@[^:]+:[^/]+/[^/]+$`
 	httpsPattern := `^https://[^/]+/[^/]+/.+$`
-	localPathPattern := `^/|^./|^../`
+	localPathPattern := `^/|^./|^../|^file://`
 
 	if regexp.MustCompile(sshPattern).MatchString(url) {
 		return nil
@@ -144,7 +266,7 @@ func ValidateGitURL(url string) error {
 		return nil
 	}
 
-	return errors.New("url must be valid git SSH, HTTPS URL, or local file path")
+	return errors.New("url must be valid git SSH, HTTPS URL, file:// URL, or local file path")
 }
 
 // GetArtifactPath returns the artifact path, with default fallback
@@ -244,5 +366,34 @@ func (m *ProjectMetadata) Validate() error {
 		}
 	}
 
+	if m.Session != nil {
+		if err := m.Session.Storage.Validate(); err != nil {
+			return fmt.Errorf("session.storage: %w", err)
+		}
+		for _, expr := range m.Session.Redaction.Patterns {
+			if _, err := regexp.Compile(expr); err != nil {
+				return fmt.Errorf("session.redaction: invalid pattern %q: %w", expr, err)
+			}
+		}
+		if err := m.Session.Retention.Validate(); err != nil {
+			return fmt.Errorf("session.retention: %w", err)
+		}
+	}
+
 	return nil
 }
+
+// Validate checks the backend name and the settings it requires
+func (c SessionStorageConfig) Validate() error {
+	switch c.Backend {
+	case "", SessionBackendSupabase, SessionBackendLocal:
+		return nil
+	case SessionBackendS3:
+		if c.Endpoint == "" || c.Bucket == "" {
+			return errors.New("s3 backend requires endpoint and bucket")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown backend %q (valid: supabase, local, s3)", c.Backend)
+	}
+}
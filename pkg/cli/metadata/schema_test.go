@@ -257,3 +257,59 @@ func TestValidateArtifactPath(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionStorageConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SessionStorageConfig
+		wantErr bool
+	}{
+		{"empty defaults to supabase", SessionStorageConfig{}, false},
+		{"supabase", SessionStorageConfig{Backend: SessionBackendSupabase}, false},
+		{"local with default path", SessionStorageConfig{Backend: SessionBackendLocal}, false},
+		{"s3 with endpoint and bucket", SessionStorageConfig{Backend: SessionBackendS3, Endpoint: "https://s3.example.com", Bucket: "sessions"}, false},
+		{"s3 without bucket rejected", SessionStorageConfig{Backend: SessionBackendS3, Endpoint: "https://s3.example.com"}, true},
+		{"unknown backend rejected", SessionStorageConfig{Backend: "ftp"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSessionRetentionConfig(t *testing.T) {
+	var defaults SessionRetentionConfig
+	if age, _ := defaults.MaxAgeDuration(); age != DefaultSessionMaxAge {
+		t.Errorf("default MaxAgeDuration() = %s", age)
+	}
+	if size, _ := defaults.MaxSizeBytes(); size != DefaultSessionMaxSize {
+		t.Errorf("default MaxSizeBytes() = %d", size)
+	}
+	if !defaults.PruneUploadedOnly() {
+		t.Error("expected queued sessions to be protected by default")
+	}
+
+	ages := map[string]time.Duration{"30d": 30 * 24 * time.Hour, "2w": 14 * 24 * time.Hour, "12h": 12 * time.Hour}
+	for in, want := range ages {
+		if got, err := ParseAge(in); err != nil || got != want {
+			t.Errorf("ParseAge(%q) = %s, %v; want %s", in, got, err, want)
+		}
+	}
+	sizes := map[string]int64{"1024": 1024, "200K": 200 << 10, "500MB": 500 << 20, "1.5GB": 3 << 29, "2gib": 2 << 30}
+	for in, want := range sizes {
+		if got, err := ParseByteSize(in); err != nil || got != want {
+			t.Errorf("ParseByteSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+
+	for _, bad := range []SessionRetentionConfig{{MaxAge: "a month"}, {MaxAge: "-1h"}, {MaxSize: "lots"}} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", bad)
+		}
+	}
+}
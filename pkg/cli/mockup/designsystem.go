@@ -66,6 +66,12 @@ func WriteDesignSystem(path string, ds *DesignSystem) error {
 	sb.WriteString("| Field | Value |\n")
 	sb.WriteString("| ----- | ----- |\n")
 	sb.WriteString(fmt.Sprintf("| Framework | %s |\n", ds.Framework.String()))
+	if ds.App != "" {
+		sb.WriteString(fmt.Sprintf("| App | %s |\n", ds.App))
+	}
+	if len(ds.SharedPackages) > 0 {
+		sb.WriteString(fmt.Sprintf("| Shared Packages | %s |\n", strings.Join(ds.SharedPackages, ", ")))
+	}
 
 	if ds.Style != nil {
 		if ds.Style.CSSFramework != "" {
@@ -3,6 +3,7 @@ package mockup
 import (
 	_ "embed"
 	"fmt"
+	"path"
 
 	"github.com/specledger/specledger/pkg/cli/prompt"
 )
@@ -33,6 +34,33 @@ func BuildMockupPromptContext(
 	}
 }
 
+// MockupPromptOverrideFile is the name of the project override of the mockup
+// prompt template, in prompt.TemplatesDir.
+const MockupPromptOverrideFile = "mockup-prompt.tmpl"
+
+// AddProjectPaths sets the constitution and plan paths of ctx. specDir is
+// the spec's artifact directory relative to root.
+func (ctx *MockupPromptContext) AddProjectPaths(root, specDir string) {
+	ctx.ConstitutionPath = prompt.ExistingPath(root, prompt.ConstitutionFile)
+	ctx.PlanPath = prompt.ExistingPath(root, path.Join(specDir, "plan.md"))
+}
+
+// RenderProjectMockupPrompt renders the project's mockup-prompt.tmpl override
+// under root when there is one, otherwise the embedded template. An invalid
+// override is an error.
+func RenderProjectMockupPrompt(root string, ctx *MockupPromptContext) (string, error) {
+	sample := BuildMockupPromptContext("001-sample", "specledger/001-sample/spec.md", "Sample", FrameworkReact, MockupFormatHTML, "specledger/001-sample/mockup.html", "sample")
+	sample.ConstitutionPath = prompt.ConstitutionFile
+	sample.PlanPath = "specledger/001-sample/plan.md"
+	sample.AppPath = "apps/web"
+
+	content, _, err := prompt.LoadTemplate(root, MockupPromptOverrideFile, mockupPromptTemplate, sample)
+	if err != nil {
+		return "", err
+	}
+	return prompt.RenderTemplate("mockup-prompt", content, ctx)
+}
+
 // RenderMockupPrompt renders the mockup prompt template with the given context.
 func RenderMockupPrompt(ctx *MockupPromptContext) (string, error) {
 	if mockupPromptTemplate == "" {
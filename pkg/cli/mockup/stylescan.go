@@ -204,7 +204,7 @@ func ScanStyles(projectPath string) *StyleInfo {
 	}
 
 	// Also scan SCSS variable files
-	for _, candidate := range []string{"src/styles/variables.scss", "src/styles/_variables.scss", "styles/variables.scss"} {
+	for _, candidate := range scssVariableCandidates {
 		fullPath := filepath.Join(projectPath, candidate)
 		if _, err := os.Stat(fullPath); err != nil {
 			continue
@@ -0,0 +1,313 @@
+package mockup
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// TokenCategory is the kind of a design token. Its value is the token's group
+// path in the W3C Design Tokens format.
+type TokenCategory string
+
+const (
+	TokenColor      TokenCategory = "color"
+	TokenSpacing    TokenCategory = "spacing"
+	TokenRadius     TokenCategory = "radius"
+	TokenFontFamily TokenCategory = "typography.fontFamily"
+	TokenFontSize   TokenCategory = "typography.fontSize"
+	TokenFontWeight TokenCategory = "typography.fontWeight"
+	TokenShadow     TokenCategory = "shadow"
+)
+
+// TokenCategories lists the categories in export order.
+var TokenCategories = []TokenCategory{
+	TokenColor, TokenSpacing, TokenRadius, TokenFontFamily, TokenFontSize, TokenFontWeight, TokenShadow,
+}
+
+// w3cTypes maps categories to W3C Design Tokens $type values.
+var w3cTypes = map[TokenCategory]string{
+	TokenColor:      "color",
+	TokenSpacing:    "dimension",
+	TokenRadius:     "dimension",
+	TokenFontFamily: "fontFamily",
+	TokenFontSize:   "dimension",
+	TokenFontWeight: "fontWeight",
+	TokenShadow:     "shadow",
+}
+
+// W3CSourceExtension is the $extensions key recording where a token was found.
+const W3CSourceExtension = "com.specledger.source"
+
+// DesignToken is a design token extracted from a Tailwind config, CSS custom
+// property or SCSS variable.
+type DesignToken struct {
+	Category   TokenCategory `json:"category"`
+	Path       []string      `json:"path"`                  // name within the category, e.g. ["primary", "500"]
+	Value      string        `json:"value"`                 // CSS value as written
+	AliasOf    string        `json:"alias_of,omitempty"`    // key of the token Value refers to
+	Source     string        `json:"source"`                // file, relative to the project
+	SourceName string        `json:"source_name,omitempty"` // CSS or SCSS variable name
+}
+
+// Key identifies the token within a TokenSet: its category and path, a
+// trailing DEFAULT removed (Tailwind's primary.DEFAULT is primary).
+func (t DesignToken) Key() string {
+	path := t.Path
+	if len(path) > 1 && path[len(path)-1] == "DEFAULT" {
+		path = path[:len(path)-1]
+	}
+	return string(t.Category) + "." + strings.Join(path, ".")
+}
+
+// TokenSet is an ordered set of design tokens; the first token added for a
+// key wins.
+type TokenSet struct {
+	Tokens []DesignToken
+	index  map[string]int
+}
+
+// NewTokenSet returns an empty TokenSet.
+func NewTokenSet() *TokenSet {
+	return &TokenSet{index: make(map[string]int)}
+}
+
+// Add adds t unless a token with the same key exists, and reports whether it
+// was added.
+func (s *TokenSet) Add(t DesignToken) bool {
+	path := make([]string, len(t.Path))
+	for i, p := range t.Path {
+		path[i] = sanitizeTokenName(p)
+	}
+	t.Path = path
+	key := t.Key()
+	if _, ok := s.index[key]; ok {
+		return false
+	}
+	s.index[key] = len(s.Tokens)
+	s.Tokens = append(s.Tokens, t)
+	return true
+}
+
+// Lookup returns the token with the given key.
+func (s *TokenSet) Lookup(key string) (DesignToken, bool) {
+	i, ok := s.index[key]
+	if !ok {
+		return DesignToken{}, false
+	}
+	return s.Tokens[i], true
+}
+
+// Merge adds the tokens of other missing from s.
+func (s *TokenSet) Merge(other *TokenSet) {
+	if other == nil {
+		return
+	}
+	for _, t := range other.Tokens {
+		s.Add(t)
+	}
+}
+
+// Count returns the number of tokens in category c.
+func (s *TokenSet) Count(c TokenCategory) int {
+	n := 0
+	for _, t := range s.Tokens {
+		if t.Category == c {
+			n++
+		}
+	}
+	return n
+}
+
+// W3C returns the tokens as a W3C Design Tokens tree: groups keyed by
+// category and path, tokens with $type, $value and the source file in
+// $extensions. Aliases are written as {group.token} references. A token whose
+// path is also a group (primary and primary.foreground) moves to DEFAULT
+// inside the group.
+func (s *TokenSet) W3C() map[string]any {
+	// Final paths: category segments + token path, DEFAULT where a token
+	// would shadow a group
+	paths := make(map[string][]string, len(s.Tokens))
+	groups := make(map[string]bool)
+	for _, t := range s.Tokens {
+		full := t.fullPath()
+		for i := 1; i < len(full); i++ {
+			groups[strings.Join(full[:i], ".")] = true
+		}
+	}
+	for _, t := range s.Tokens {
+		full := t.fullPath()
+		if groups[strings.Join(full, ".")] {
+			full = append(full, "DEFAULT")
+		}
+		paths[t.Key()] = full
+	}
+
+	tree := make(map[string]any)
+	for _, t := range s.Tokens {
+		node := map[string]any{
+			"$type":  w3cTypes[t.Category],
+			"$value": w3cValue(t),
+		}
+		if target, ok := paths[t.AliasOf]; ok && t.AliasOf != "" {
+			node["$value"] = "{" + strings.Join(target, ".") + "}"
+		} else if t.Category == TokenShadow {
+			if shadow, ok := parseShadow(t.Value); ok {
+				node["$value"] = shadow
+			} else {
+				// Not a shadow the format can express: keep the CSS value
+				delete(node, "$type")
+			}
+		}
+		if t.Source != "" {
+			node["$extensions"] = map[string]any{W3CSourceExtension: t.Source}
+		}
+
+		full := paths[t.Key()]
+		group := tree
+		for _, name := range full[:len(full)-1] {
+			child, ok := group[name].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				group[name] = child
+			}
+			group = child
+		}
+		group[full[len(full)-1]] = node
+	}
+	return tree
+}
+
+// MarshalW3C encodes the tokens as indented W3C Design Tokens JSON.
+func (s *TokenSet) MarshalW3C() ([]byte, error) {
+	data, err := json.MarshalIndent(s.W3C(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// fullPath returns the token's path under the root of the W3C tree.
+func (t DesignToken) fullPath() []string {
+	full := strings.Split(string(t.Category), ".")
+	path := t.Path
+	if len(path) > 1 && path[len(path)-1] == "DEFAULT" {
+		path = path[:len(path)-1]
+	}
+	return append(full, path...)
+}
+
+// w3cValue converts a CSS value to the W3C value of its category: font
+// families become lists, font weights numbers when numeric.
+func w3cValue(t DesignToken) any {
+	switch t.Category {
+	case TokenFontFamily:
+		var families []string
+		for _, f := range splitTopLevel(t.Value, ',') {
+			f = strings.Trim(strings.TrimSpace(f), `"'`)
+			if f != "" {
+				families = append(families, f)
+			}
+		}
+		if len(families) == 1 {
+			return families[0]
+		}
+		return families
+	case TokenFontWeight:
+		n := json.Number(strings.TrimSpace(t.Value))
+		if _, err := n.Int64(); err == nil {
+			return n
+		}
+	}
+	return t.Value
+}
+
+// parseShadow parses a CSS box-shadow into W3C shadow values: an object, or
+// a list of objects for several shadows.
+func parseShadow(value string) (any, bool) {
+	var shadows []map[string]any
+	for _, part := range splitTopLevel(value, ',') {
+		var lengths []string
+		color := ""
+		inset := false
+		for _, field := range splitTopLevel(strings.TrimSpace(part), ' ') {
+			switch {
+			case field == "":
+			case field == "inset":
+				inset = true
+			case isDimension(field):
+				lengths = append(lengths, field)
+			case color == "" && isColorValue(field):
+				color = field
+			default:
+				return nil, false
+			}
+		}
+		if len(lengths) < 2 || len(lengths) > 4 {
+			return nil, false
+		}
+		for len(lengths) < 4 {
+			lengths = append(lengths, "0px")
+		}
+		if color == "" {
+			color = "#000000"
+		}
+		shadow := map[string]any{
+			"color":   color,
+			"offsetX": zeroPx(lengths[0]),
+			"offsetY": zeroPx(lengths[1]),
+			"blur":    zeroPx(lengths[2]),
+			"spread":  zeroPx(lengths[3]),
+		}
+		if inset {
+			shadow["inset"] = true
+		}
+		shadows = append(shadows, shadow)
+	}
+	switch len(shadows) {
+	case 0:
+		return nil, false
+	case 1:
+		return shadows[0], true
+	}
+	return shadows, true
+}
+
+// zeroPx gives unitless zeros a unit, as dimensions require one.
+func zeroPx(length string) string {
+	if length == "0" {
+		return "0px"
+	}
+	return length
+}
+
+// splitTopLevel splits s on sep outside parentheses and quotes.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth := 0
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// sanitizeTokenName replaces the characters W3C token names can't contain.
+func sanitizeTokenName(name string) string {
+	return strings.NewReplacer(".", "_", "{", "", "}", "", "$", "").Replace(name)
+}
@@ -0,0 +1,236 @@
+package mockup
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const tailwindV3Config = `import type { Config } from 'tailwindcss'
+import colors from 'tailwindcss/colors'
+
+export default {
+  content: ['./src/**/*.{ts,tsx}'],
+  theme: {
+    extend: {
+      colors: {
+        primary: {
+          DEFAULT: 'hsl(var(--primary))',
+          foreground: 'hsl(var(--primary-foreground))',
+        },
+        brand: {
+          500: '#6366f1', // main
+          600: "#4f46e5",
+        },
+        gray: colors.slate,
+      },
+      spacing: {
+        '18': '4.5rem',
+        0.5: '0.125rem',
+      },
+      borderRadius: {
+        lg: 'var(--radius)',
+        md: 'calc(var(--radius) - 2px)',
+      },
+      fontFamily: {
+        sans: ['Inter Variable', 'sans-serif'],
+      },
+      fontSize: {
+        xs: ['0.75rem', { lineHeight: '1rem' }],
+      },
+      fontWeight: {
+        heavy: '850',
+      },
+      boxShadow: {
+        card: '0 1px 3px 0 rgb(0 0 0 / 0.1), 0 1px 2px -1px rgb(0 0 0 / 0.1)',
+        glow: '0 0 0 3px theme("colors.brand.500")',
+      },
+    },
+  },
+} satisfies Config
+`
+
+const shadcnCSS = `@tailwind base;
+
+/* theme */
+:root {
+  --background: 0 0% 100%;
+  --primary: 222.2 47.4% 11.2%;
+  --primary-foreground: 210 40% 98%;
+  --radius: 0.5rem;
+  --font-heading: "Cal Sans", sans-serif;
+  --header-height: 64px;
+}
+
+.dark {
+  --background: 222.2 84% 4.9%;
+}
+`
+
+func tokenValues(set *TokenSet) map[string]string {
+	values := make(map[string]string, len(set.Tokens))
+	for _, t := range set.Tokens {
+		v := t.Value
+		if t.AliasOf != "" {
+			v = "{" + t.AliasOf + "}"
+		}
+		values[t.Key()] = v
+	}
+	return values
+}
+
+func TestExtractTokens_TailwindV3(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"tailwind.config.ts":     tailwindV3Config,
+		"src/styles/globals.css": shadcnCSS,
+	})
+
+	values := tokenValues(ExtractTokens(dir))
+	want := map[string]string{
+		"color.background":              "hsl(0 0% 100%)",
+		"color.primary":                 "hsl(222.2 47.4% 11.2%)",
+		"color.primary-foreground":      "hsl(210 40% 98%)",
+		"color.primary.foreground":      "{color.primary-foreground}",
+		"color.brand.500":               "#6366f1",
+		"color.brand.600":               "#4f46e5",
+		"radius.DEFAULT":                "0.5rem",
+		"radius.lg":                     "{radius.DEFAULT}",
+		"radius.md":                     "calc(var(--radius) - 2px)",
+		"spacing.18":                    "4.5rem",
+		"spacing.0_5":                   "0.125rem",
+		"typography.fontFamily.heading": `"Cal Sans", sans-serif`,
+		"typography.fontFamily.sans":    `"Inter Variable", sans-serif`,
+		"typography.fontSize.xs":        "0.75rem",
+		"typography.fontWeight.heavy":   "850",
+		"shadow.card":                   "0 1px 3px 0 rgb(0 0 0 / 0.1), 0 1px 2px -1px rgb(0 0 0 / 0.1)",
+		"shadow.glow":                   `0 0 0 3px theme("colors.brand.500")`,
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("tokens:\n got %v\nwant %v", values, want)
+	}
+}
+
+func TestExtractTokens_TailwindV4AndSCSS(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"src/index.css": `@import "tailwindcss";
+
+@theme {
+  --color-*: initial;
+  --color-mint-500: oklch(0.72 0.11 178);
+  --spacing: 0.25rem;
+  --radius-lg: 0.75rem;
+  --font-display: "Satoshi", sans-serif;
+  --font-weight-bold: 700;
+  --text-sm: 0.875rem;
+  --text-sm--line-height: 1.25rem;
+  --shadow-sm: 0 1px 2px 0 #0000000d;
+}
+
+@theme inline {
+  --color-accent: var(--accent);
+}
+
+:root {
+  --accent: oklch(0.6 0.2 30);
+}
+`,
+		"src/styles/_variables.scss": `// brand
+$brand: #ff5500 !default;
+$link-color: $brand;
+$gap-lg: 24px;
+$unused: 10;
+`,
+	})
+
+	values := tokenValues(ExtractTokens(dir))
+	want := map[string]string{
+		"color.mint.500":                "oklch(0.72 0.11 178)",
+		"color.accent":                  "oklch(0.6 0.2 30)",
+		"color.brand":                   "#ff5500",
+		"color.link-color":              "{color.brand}",
+		"spacing.base":                  "0.25rem",
+		"spacing.gap-lg":                "24px",
+		"radius.lg":                     "0.75rem",
+		"typography.fontFamily.display": `"Satoshi", sans-serif`,
+		"typography.fontWeight.bold":    "700",
+		"typography.fontSize.sm":        "0.875rem",
+		"shadow.sm":                     "0 1px 2px 0 #0000000d",
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("tokens:\n got %v\nwant %v", values, want)
+	}
+}
+
+func TestTokenSet_W3C(t *testing.T) {
+	set := NewTokenSet()
+	set.Add(DesignToken{Category: TokenColor, Path: []string{"primary"}, Value: "#111111", Source: "app/globals.css"})
+	set.Add(DesignToken{Category: TokenColor, Path: []string{"primary", "foreground"}, Value: "#ffffff"})
+	set.Add(DesignToken{Category: TokenColor, Path: []string{"link"}, Value: "var(--primary)", AliasOf: "color.primary"})
+	set.Add(DesignToken{Category: TokenFontFamily, Path: []string{"sans"}, Value: `"Inter", sans-serif`})
+	set.Add(DesignToken{Category: TokenFontWeight, Path: []string{"bold"}, Value: "700"})
+	set.Add(DesignToken{Category: TokenShadow, Path: []string{"sm"}, Value: "inset 0 1px 2px #0000000d"})
+	set.Add(DesignToken{Category: TokenShadow, Path: []string{"ring"}, Value: "0 0 0 var(--ring)"})
+
+	// Duplicate keys: the first wins
+	if set.Add(DesignToken{Category: TokenColor, Path: []string{"primary", "DEFAULT"}, Value: "#222222"}) {
+		t.Error("expected primary.DEFAULT to duplicate primary")
+	}
+
+	data, err := set.MarshalW3C()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  "color": {
+    "link": {"$type": "color", "$value": "{color.primary.DEFAULT}"},
+    "primary": {
+      "DEFAULT": {"$type": "color", "$value": "#111111", "$extensions": {"com.specledger.source": "app/globals.css"}},
+      "foreground": {"$type": "color", "$value": "#ffffff"}
+    }
+  },
+  "typography": {
+    "fontFamily": {"sans": {"$type": "fontFamily", "$value": ["Inter", "sans-serif"]}},
+    "fontWeight": {"bold": {"$type": "fontWeight", "$value": 700}}
+  },
+  "shadow": {
+    "sm": {"$type": "shadow", "$value": {"color": "#0000000d", "offsetX": "0px", "offsetY": "1px", "blur": "2px", "spread": "0px", "inset": true}},
+    "ring": {"$value": "0 0 0 var(--ring)"}
+  }
+}`
+	var wantTree map[string]any
+	if err := json.Unmarshal([]byte(want), &wantTree); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, wantTree) {
+		t.Errorf("W3C JSON:\n%s", data)
+	}
+}
+
+func TestExtractWorkspaceTokens(t *testing.T) {
+	ws, err := DetectWorkspace(turboWorkspace(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err := ws.FindApp("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set := ExtractWorkspaceTokens(ws, app)
+	background, ok := set.Lookup("color.background")
+	if !ok || background.Value != "#fff" || background.Source != "apps/web/app/globals.css" {
+		t.Errorf("background = %+v, want #fff from apps/web", background)
+	}
+	primary, ok := set.Lookup("color.primary-color")
+	if !ok || !strings.HasPrefix(primary.Source, "packages/tokens/") {
+		t.Errorf("primary = %+v, want a token from packages/tokens", primary)
+	}
+}
@@ -0,0 +1,587 @@
+package mockup
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// tailwindTokenKeys maps Tailwind v3 theme keys to token categories.
+var tailwindTokenKeys = map[string]TokenCategory{
+	"colors":       TokenColor,
+	"spacing":      TokenSpacing,
+	"borderRadius": TokenRadius,
+	"fontFamily":   TokenFontFamily,
+	"fontSize":     TokenFontSize,
+	"fontWeight":   TokenFontWeight,
+	"boxShadow":    TokenShadow,
+}
+
+// tailwindV4Namespaces maps Tailwind v4 theme variable namespaces to token
+// categories. Longer prefixes first: --font-weight- before --font-.
+var tailwindV4Namespaces = []struct {
+	prefix   string
+	category TokenCategory
+}{
+	{"--color-", TokenColor},
+	{"--spacing-", TokenSpacing},
+	{"--radius-", TokenRadius},
+	{"--font-weight-", TokenFontWeight},
+	{"--font-", TokenFontFamily},
+	{"--text-", TokenFontSize},
+	{"--shadow-", TokenShadow},
+}
+
+// scssVariableCandidates are SCSS files scanned for variables.
+var scssVariableCandidates = []string{
+	"src/styles/variables.scss",
+	"src/styles/_variables.scss",
+	"styles/variables.scss",
+	"src/styles/_tokens.scss",
+	"styles/_variables.scss",
+}
+
+var (
+	tailwindTokenKeyRe = regexp.MustCompile(`\b(colors|spacing|borderRadius|fontFamily|fontSize|fontWeight|boxShadow)\s*:\s*\{`)
+	scssVarDecl        = regexp.MustCompile(`^\s*\$([\w-]+)\s*:\s*([^;]+?)\s*(?:!default)?\s*;`)
+	cssBlockComment    = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	hexColorRe         = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	colorFuncRe        = regexp.MustCompile(`^(?:rgba?|hsla?|hwb|lab|lch|oklab|oklch|color)\(.*\)$`)
+	// hslTripletRe matches bare HSL channels, as in shadcn/ui themes: 222.2 84% 4.9%
+	hslTripletRe = regexp.MustCompile(`^\d+(?:\.\d+)?(?:deg)?\s+\d+(?:\.\d+)?%\s+\d+(?:\.\d+)?%$`)
+	dimensionRe  = regexp.MustCompile(`^-?(?:\d+|\d*\.\d+)(?:px|rem|em|%|vh|vw|pt)?$`)
+	// varRefRe matches a value that only references a variable, possibly
+	// wrapped in a color function: var(--x), $x, hsl(var(--x))
+	varRefRe = regexp.MustCompile(`^(?:(\w+)\()?(?:var\((--[\w-]+)\)|\$([\w-]+))\)?$`)
+	shadeRe  = regexp.MustCompile(`^(.+)-(\d+)$`)
+	// themeBlockRe matches Tailwind v4 @theme openers, with options: @theme inline {
+	themeBlockRe = regexp.MustCompile(`@theme(?:\s+[\w-]+)*\s*\{`)
+)
+
+// ExtractTokens extracts the design tokens of the project at projectPath
+// from its global CSS custom properties (including Tailwind v4 @theme
+// blocks), SCSS variables and Tailwind v3 config, in that order of
+// precedence. Variables referring to another token become aliases.
+func ExtractTokens(projectPath string) *TokenSet {
+	x := &tokenExtractor{set: NewTokenSet(), byVar: make(map[string]string)}
+
+	for _, candidate := range globalCSSCandidates {
+		if content, err := os.ReadFile(filepath.Join(projectPath, candidate)); err == nil {
+			x.scanCSS(string(content), candidate)
+		}
+	}
+	for _, candidate := range scssVariableCandidates {
+		if content, err := os.ReadFile(filepath.Join(projectPath, candidate)); err == nil {
+			x.scanSCSS(string(content), candidate)
+		}
+	}
+	for _, candidate := range []string{"tailwind.config.ts", "tailwind.config.js", "tailwind.config.mjs", "tailwind.config.cjs"} {
+		if content, err := os.ReadFile(filepath.Join(projectPath, candidate)); err == nil {
+			x.scanTailwindConfig(string(content), candidate)
+			break
+		}
+	}
+	x.resolvePending()
+	return x.set
+}
+
+// ExtractWorkspaceTokens extracts the tokens of app and merges in those of
+// the shared packages it depends on. Sources are relative to the workspace
+// root.
+func ExtractWorkspaceTokens(ws *Workspace, app *WorkspaceMember) *TokenSet {
+	set := NewTokenSet()
+	members := append([]WorkspaceMember{*app}, ws.SharedPackages(app)...)
+	for _, m := range members {
+		tokens := ExtractTokens(filepath.Join(ws.Root, filepath.FromSlash(m.Path)))
+		for i := range tokens.Tokens {
+			tokens.Tokens[i].Source = m.Path + "/" + tokens.Tokens[i].Source
+		}
+		set.Merge(tokens)
+	}
+	return set
+}
+
+// tokenExtractor accumulates tokens across files. Variables whose category
+// depends on the variable they refer to are resolved at the end.
+type tokenExtractor struct {
+	set     *TokenSet
+	byVar   map[string]string // CSS/SCSS variable name -> token key
+	pending []pendingToken
+}
+
+// pendingToken is a variable referring to another variable.
+type pendingToken struct {
+	name, value, source string
+}
+
+// addVariable classifies a CSS custom property or SCSS variable (name with
+// its -- or $ prefix) and adds it as a token.
+func (x *tokenExtractor) addVariable(name, value, source string, theme bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "initial" {
+		return
+	}
+	if varRefRe.MatchString(value) {
+		x.pending = append(x.pending, pendingToken{name: name, value: value, source: source})
+		return
+	}
+
+	category, path := classifyVariable(name, value, theme)
+	if category == "" {
+		return
+	}
+	if category == TokenColor && hslTripletRe.MatchString(value) {
+		value = "hsl(" + value + ")"
+	}
+	t := DesignToken{Category: category, Path: path, Value: value, Source: source, SourceName: name}
+	if x.set.Add(t) {
+		x.byVar[name] = x.set.Tokens[len(x.set.Tokens)-1].Key()
+	}
+}
+
+// resolvePending adds the variables referring to a known token as aliases of
+// it, in the token's category.
+func (x *tokenExtractor) resolvePending() {
+	// References may chain: resolve until nothing changes
+	for changed := true; changed; {
+		changed = false
+		remaining := x.pending[:0]
+		for _, p := range x.pending {
+			target, ok := x.aliasTarget(p.value)
+			if !ok {
+				remaining = append(remaining, p)
+				continue
+			}
+			path := tokenPath(target.Category, variableName(p.name))
+			t := DesignToken{Category: target.Category, Path: path, Value: p.value, AliasOf: target.Key(), Source: p.source, SourceName: p.name}
+			if x.set.Add(t) {
+				x.byVar[p.name] = x.set.Tokens[len(x.set.Tokens)-1].Key()
+			}
+			changed = true
+		}
+		x.pending = remaining
+	}
+}
+
+// aliasTarget returns the token a reference value points to. A reference
+// wrapped in a color function (hsl(var(--x))) only aliases a token written
+// with the same function.
+func (x *tokenExtractor) aliasTarget(value string) (DesignToken, bool) {
+	m := varRefRe.FindStringSubmatch(value)
+	if m == nil {
+		return DesignToken{}, false
+	}
+	name := m[2]
+	if name == "" {
+		name = "$" + m[3]
+	}
+	key, ok := x.byVar[name]
+	if !ok {
+		return DesignToken{}, false
+	}
+	target, ok := x.set.Lookup(key)
+	if !ok {
+		return DesignToken{}, false
+	}
+	if fn := m[1]; fn != "" && !strings.HasPrefix(target.Value, fn+"(") {
+		return DesignToken{}, false
+	}
+	return target, true
+}
+
+// scanCSS adds the custom properties of a CSS file; inside Tailwind v4 @theme
+// blocks, variable namespaces decide the category.
+func (x *tokenExtractor) scanCSS(content, source string) {
+	content = cssBlockComment.ReplaceAllString(content, "")
+	for _, block := range tailwindV4ThemeBlocks(content) {
+		for _, m := range cssVarDecl.FindAllStringSubmatch(block, -1) {
+			x.addVariable("--"+m[1], m[2], source, true)
+		}
+		content = strings.Replace(content, block, "", 1)
+	}
+	for _, m := range cssVarDecl.FindAllStringSubmatch(content, -1) {
+		x.addVariable("--"+m[1], m[2], source, false)
+	}
+}
+
+// scanSCSS adds the variables of an SCSS file.
+func (x *tokenExtractor) scanSCSS(content, source string) {
+	content = cssBlockComment.ReplaceAllString(content, "")
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "//"); i >= 0 && !strings.Contains(line[:i], ":/") {
+			line = line[:i]
+		}
+		if m := scssVarDecl.FindStringSubmatch(line); m != nil {
+			x.addVariable("$"+m[1], m[2], source, false)
+		}
+	}
+}
+
+// scanTailwindConfig adds the theme values of a Tailwind v3 config: colors,
+// spacing, borderRadius, fontFamily, fontSize, fontWeight and boxShadow under
+// theme or theme.extend. Values computed in JavaScript are skipped.
+func (x *tokenExtractor) scanTailwindConfig(content, source string) {
+	for _, loc := range tailwindTokenKeyRe.FindAllStringSubmatchIndex(content, -1) {
+		category := tailwindTokenKeys[content[loc[2]:loc[3]]]
+		p := &jsParser{src: content, pos: loc[1]}
+		var entries []jsEntry
+		p.parseObject(nil, &entries)
+
+		for _, e := range entries {
+			value := e.value
+			if len(e.list) > 0 {
+				if category == TokenFontFamily {
+					value = strings.Join(quoteFamilies(e.list), ", ")
+				} else {
+					// fontSize: ['0.875rem', { lineHeight: ... }]
+					value = e.list[0]
+				}
+			}
+			if value == "" {
+				continue
+			}
+			t := DesignToken{Category: category, Path: e.path, Value: value, Source: source}
+			if target, ok := x.aliasTarget(value); ok && target.Category == category {
+				t.AliasOf = target.Key()
+			}
+			x.set.Add(t)
+		}
+	}
+}
+
+// classifyVariable returns the category and token path of a variable, from
+// Tailwind v4 namespaces inside @theme (theme) or from its name and value
+// otherwise. The category is "" for variables that aren't design tokens.
+func classifyVariable(name, value string, theme bool) (TokenCategory, []string) {
+	if theme {
+		if name == "--spacing" {
+			return TokenSpacing, []string{"base"}
+		}
+		for _, ns := range tailwindV4Namespaces {
+			if !strings.HasPrefix(name, ns.prefix) {
+				continue
+			}
+			rest := strings.TrimPrefix(name, ns.prefix)
+			// --text-sm--line-height and similar modifiers aren't tokens
+			if rest == "" || strings.Contains(rest, "--") {
+				return "", nil
+			}
+			if ns.category == TokenColor {
+				return TokenColor, colorPath(rest)
+			}
+			return ns.category, []string{rest}
+		}
+		return "", nil
+	}
+
+	base := variableName(name)
+	lower := strings.ToLower(base)
+	var category TokenCategory
+	switch {
+	case isColorValue(value):
+		category = TokenColor
+	case strings.Contains(lower, "shadow"):
+		category = TokenShadow
+	case strings.Contains(lower, "radius"):
+		category = TokenRadius
+	case strings.Contains(lower, "font-weight"):
+		category = TokenFontWeight
+	case (strings.Contains(lower, "font-size") || strings.HasPrefix(lower, "text-")) && isDimension(value):
+		category = TokenFontSize
+	case strings.Contains(lower, "font") && !isDimension(value):
+		category = TokenFontFamily
+	case (strings.Contains(lower, "spacing") || strings.Contains(lower, "space") || strings.Contains(lower, "gap")) && isDimension(value):
+		category = TokenSpacing
+	default:
+		return "", nil
+	}
+	return category, tokenPath(category, base)
+}
+
+// tokenNamePrefixes are the name prefixes dropped from variables of each
+// category: --radius-lg is radius.lg.
+var tokenNamePrefixes = map[TokenCategory][]string{
+	TokenColor:      {"color-"},
+	TokenSpacing:    {"spacing-"},
+	TokenRadius:     {"radius-"},
+	TokenFontFamily: {"font-family-", "font-"},
+	TokenFontSize:   {"font-size-", "text-"},
+	TokenFontWeight: {"font-weight-"},
+	TokenShadow:     {"shadow-"},
+}
+
+// tokenPath returns the token path of a variable named name (without its
+// -- or $) in category. A variable named after its category (--radius) is
+// the category's DEFAULT.
+func tokenPath(category TokenCategory, name string) []string {
+	for _, prefix := range tokenNamePrefixes[category] {
+		if name == strings.TrimSuffix(prefix, "-") {
+			return []string{"DEFAULT"}
+		}
+		if stripped := strings.TrimPrefix(name, prefix); stripped != "" && stripped != name {
+			name = stripped
+			break
+		}
+	}
+	if category == TokenColor {
+		return colorPath(name)
+	}
+	return []string{name}
+}
+
+// variableName strips the -- or $ prefix of a variable.
+func variableName(name string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(name, "--"), "$")
+}
+
+// colorPath splits a trailing shade off a color name: red-500 -> red, 500.
+func colorPath(name string) []string {
+	if m := shadeRe.FindStringSubmatch(name); m != nil {
+		return []string{m[1], m[2]}
+	}
+	return []string{name}
+}
+
+// quoteFamilies quotes font family names containing spaces.
+func quoteFamilies(families []string) []string {
+	quoted := make([]string, len(families))
+	for i, f := range families {
+		if strings.Contains(f, " ") {
+			f = `"` + f + `"`
+		}
+		quoted[i] = f
+	}
+	return quoted
+}
+
+// isColorValue reports whether value is a CSS color: hex, a color function
+// or bare HSL channels.
+func isColorValue(value string) bool {
+	return hexColorRe.MatchString(value) || colorFuncRe.MatchString(value) || hslTripletRe.MatchString(value)
+}
+
+// isDimension reports whether value is a number with an optional CSS unit.
+func isDimension(value string) bool {
+	return dimensionRe.MatchString(value)
+}
+
+// tailwindV4ThemeBlocks returns the bodies of the @theme blocks of a CSS file.
+func tailwindV4ThemeBlocks(content string) []string {
+	var blocks []string
+	for _, loc := range themeBlockRe.FindAllStringIndex(content, -1) {
+		start := loc[1]
+		depth := 1
+		end := start
+		for end < len(content) && depth > 0 {
+			switch content[end] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+			end++
+		}
+		if depth == 0 {
+			blocks = append(blocks, content[start:end-1])
+		}
+	}
+	return blocks
+}
+
+// jsEntry is a leaf of a JavaScript object literal: a string or number
+// value, or a list of them.
+type jsEntry struct {
+	path  []string
+	value string
+	list  []string
+}
+
+// jsParser reads the literal parts of a JavaScript object, skipping
+// expressions it can't evaluate.
+type jsParser struct {
+	src string
+	pos int
+}
+
+// parseObject parses the object whose opening brace was consumed, adding
+// its leaves under prefix to out. Nested objects extend the path.
+func (p *jsParser) parseObject(prefix []string, out *[]jsEntry) {
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return
+		}
+		switch c := p.src[p.pos]; {
+		case c == '}':
+			p.pos++
+			return
+		case c == ',':
+			p.pos++
+			continue
+		case strings.HasPrefix(p.src[p.pos:], "..."):
+			p.skipValue()
+			continue
+		}
+
+		key, ok := p.parseKey()
+		p.skipSpace()
+		if !ok || p.pos >= len(p.src) || p.src[p.pos] != ':' {
+			p.skipValue()
+			continue
+		}
+		p.pos++
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return
+		}
+
+		path := append(append([]string{}, prefix...), key)
+		switch c := p.src[p.pos]; {
+		case c == '{':
+			p.pos++
+			p.parseObject(path, out)
+		case c == '[':
+			if list := p.parseList(); len(list) > 0 {
+				*out = append(*out, jsEntry{path: path, list: list})
+			}
+		case c == '"' || c == '\'' || c == '`':
+			if s, ok := p.parseString(); ok {
+				*out = append(*out, jsEntry{path: path, value: s})
+			}
+			p.skipValue()
+		case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+			start := p.pos
+			p.skipValue()
+			if n := strings.TrimSpace(p.src[start:p.pos]); dimensionRe.MatchString(n) {
+				*out = append(*out, jsEntry{path: path, value: n})
+			}
+		default:
+			p.skipValue()
+		}
+	}
+}
+
+// parseKey reads an identifier, number or quoted key.
+func (p *jsParser) parseKey() (string, bool) {
+	if p.pos < len(p.src) && strings.ContainsRune(`"'`+"`", rune(p.src[p.pos])) {
+		return p.parseString()
+	}
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '_' || c == '$' || c == '-' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.src[start:p.pos], p.pos > start
+}
+
+// parseString reads a quoted string without interpolation.
+func (p *jsParser) parseString() (string, bool) {
+	quote := p.src[p.pos]
+	p.pos++
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != quote {
+		if p.src[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	s := p.src[start:minInt(p.pos, len(p.src))]
+	p.pos++
+	if quote == '`' && strings.Contains(s, "${") {
+		return "", false
+	}
+	return s, true
+}
+
+// parseList reads an array, keeping its string and number elements.
+func (p *jsParser) parseList() []string {
+	p.pos++ // [
+	var list []string
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return list
+		}
+		switch c := p.src[p.pos]; {
+		case c == ']':
+			p.pos++
+			return list
+		case c == ',':
+			p.pos++
+		case c == '"' || c == '\'' || c == '`':
+			if s, ok := p.parseString(); ok {
+				list = append(list, s)
+			}
+		default:
+			start := p.pos
+			p.skipValue()
+			if n := strings.TrimSpace(p.src[start:p.pos]); dimensionRe.MatchString(n) {
+				list = append(list, n)
+			}
+		}
+	}
+}
+
+// skipValue skips to the next comma or closing bracket at depth 0, over
+// nested brackets, strings and comments.
+func (p *jsParser) skipValue() {
+	depth := 0
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			p.parseString()
+			continue
+		case c == '/' && p.pos+1 < len(p.src) && (p.src[p.pos+1] == '/' || p.src[p.pos+1] == '*'):
+			p.skipSpace()
+			continue
+		case c == '{' || c == '[' || c == '(':
+			depth++
+		case c == '}' || c == ']' || c == ')':
+			if depth == 0 {
+				return
+			}
+			depth--
+		case c == ',' && depth == 0:
+			return
+		}
+		p.pos++
+	}
+}
+
+// skipSpace skips whitespace and comments.
+func (p *jsParser) skipSpace() {
+	for p.pos < len(p.src) {
+		rest := p.src[p.pos:]
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\n' || rest[0] == '\r':
+			p.pos++
+		case strings.HasPrefix(rest, "//"):
+			if i := strings.IndexByte(rest, '\n'); i >= 0 {
+				p.pos += i + 1
+			} else {
+				p.pos = len(p.src)
+			}
+		case strings.HasPrefix(rest, "/*"):
+			if i := strings.Index(rest[2:], "*/"); i >= 0 {
+				p.pos += i + 4
+			} else {
+				p.pos = len(p.src)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
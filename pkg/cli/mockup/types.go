@@ -68,12 +68,16 @@ type DetectionResult struct {
 // DesignSystem contains the project's design tokens and styling conventions.
 // Does NOT index components - the AI agent discovers those via codebase search.
 type DesignSystem struct {
-	Version      int           `yaml:"version" json:"version"`
-	Framework    FrameworkType `yaml:"framework" json:"framework"`
-	LastScanned  time.Time     `yaml:"last_scanned" json:"last_scanned"`
-	ExternalLibs []string      `yaml:"external_libs,omitempty" json:"external_libs,omitempty"`
-	Style        *StyleInfo    `yaml:"style,omitempty" json:"style,omitempty"`
-	AppStructure *AppStructure `yaml:"app_structure,omitempty" json:"app_structure,omitempty"`
+	Version   int           `yaml:"version" json:"version"`
+	Framework FrameworkType `yaml:"framework" json:"framework"`
+	// Workspace app the design system describes, relative to the repository
+	// root, and the shared packages whose tokens were merged in (monorepos only)
+	App            string        `yaml:"app,omitempty" json:"app,omitempty"`
+	SharedPackages []string      `yaml:"shared_packages,omitempty" json:"shared_packages,omitempty"`
+	LastScanned    time.Time     `yaml:"last_scanned" json:"last_scanned"`
+	ExternalLibs   []string      `yaml:"external_libs,omitempty" json:"external_libs,omitempty"`
+	Style          *StyleInfo    `yaml:"style,omitempty" json:"style,omitempty"`
+	AppStructure   *AppStructure `yaml:"app_structure,omitempty" json:"app_structure,omitempty"`
 }
 
 // AppStructure describes the project's layout and routing structure.
@@ -113,6 +117,12 @@ type MockupPromptContext struct {
 	Format     MockupFormat  `json:"format"`
 	OutputPath string        `json:"output_path"`
 	UserPrompt string        `json:"user_prompt,omitempty"`
+	AppPath    string        `json:"app_path,omitempty"` // workspace app directory (monorepos only)
+
+	// Project files for custom templates, relative to the project root
+	// ("" when the file doesn't exist)
+	ConstitutionPath string `json:"constitution_path,omitempty"`
+	PlanPath         string `json:"plan_path,omitempty"`
 }
 
 // MockupResult is the JSON output for --json mode.
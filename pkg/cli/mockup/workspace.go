@@ -0,0 +1,383 @@
+package mockup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Workspace tools reported by DetectWorkspace.
+const (
+	WorkspaceTurborepo = "turborepo"
+	WorkspaceNx        = "nx"
+	WorkspacePnpm      = "pnpm"
+	WorkspaceYarn      = "yarn"
+	WorkspaceNpm       = "npm"
+)
+
+// Workspace describes a JavaScript monorepo: its tooling and member packages.
+type Workspace struct {
+	Root     string            `json:"-"`
+	Tool     string            `json:"tool"`
+	Patterns []string          `json:"patterns"`
+	Members  []WorkspaceMember `json:"members"`
+}
+
+// WorkspaceMember is a package of a workspace.
+type WorkspaceMember struct {
+	Name       string        `json:"name"`
+	Path       string        `json:"path"` // relative to the workspace root, slash-separated
+	Framework  FrameworkType `json:"framework"`
+	IsFrontend bool          `json:"is_frontend"`
+	IsApp      bool          `json:"is_app"` // an application, as opposed to a shared package
+
+	deps map[string]string
+}
+
+// defaultWorkspacePatterns are used for Nx and Turborepo repositories that
+// don't declare their packages elsewhere.
+var defaultWorkspacePatterns = []string{"apps/*", "packages/*", "libs/*"}
+
+// DetectWorkspace detects a pnpm, npm or yarn workspace, Nx or Turborepo
+// repository at root and scans its members. It returns nil when root is not a
+// workspace.
+func DetectWorkspace(root string) (*Workspace, error) {
+	ws := &Workspace{Root: root}
+
+	// pnpm-workspace.yaml
+	if data, err := os.ReadFile(filepath.Join(root, "pnpm-workspace.yaml")); err == nil {
+		var cfg struct {
+			Packages []string `yaml:"packages"`
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse pnpm-workspace.yaml: %w", err)
+		}
+		ws.Tool = WorkspacePnpm
+		ws.Patterns = cfg.Packages
+	}
+
+	// package.json "workspaces": an array, or {"packages": [...]} (yarn)
+	if len(ws.Patterns) == 0 {
+		if patterns := readPackageWorkspaces(root); len(patterns) > 0 {
+			ws.Tool = WorkspaceNpm
+			if fileExists(filepath.Join(root, "yarn.lock")) {
+				ws.Tool = WorkspaceYarn
+			}
+			ws.Patterns = patterns
+		}
+	}
+
+	// Nx and Turborepo sit on top of the package manager's workspace
+	if data, err := os.ReadFile(filepath.Join(root, "nx.json")); err == nil {
+		ws.Tool = WorkspaceNx
+		if len(ws.Patterns) == 0 {
+			ws.Patterns = nxPatterns(data)
+		}
+	}
+	if fileExists(filepath.Join(root, "turbo.json")) {
+		ws.Tool = WorkspaceTurborepo
+		if len(ws.Patterns) == 0 {
+			ws.Patterns = defaultWorkspacePatterns
+		}
+	}
+
+	if ws.Tool == "" {
+		return nil, nil
+	}
+
+	members, err := expandWorkspacePatterns(root, ws.Patterns)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range members {
+		ws.Members = append(ws.Members, scanWorkspaceMember(root, dir))
+	}
+	return ws, nil
+}
+
+// Apps returns the frontend applications of the workspace.
+func (ws *Workspace) Apps() []WorkspaceMember {
+	var apps []WorkspaceMember
+	for _, m := range ws.Members {
+		if m.IsApp && m.IsFrontend {
+			apps = append(apps, m)
+		}
+	}
+	return apps
+}
+
+// FindApp returns the member named name: its package name, its directory
+// name, or its path. The error lists the frontend apps.
+func (ws *Workspace) FindApp(name string) (*WorkspaceMember, error) {
+	name = strings.TrimSuffix(filepath.ToSlash(name), "/")
+	for i, m := range ws.Members {
+		if m.Name == name || m.Path == name || filepath.Base(m.Path) == name {
+			return &ws.Members[i], nil
+		}
+	}
+
+	var names []string
+	for _, app := range ws.Apps() {
+		names = append(names, fmt.Sprintf("%s (%s)", filepath.Base(app.Path), app.Path))
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("app %q not found: no frontend apps in this workspace", name)
+	}
+	return nil, fmt.Errorf("app %q not found, available apps: %s", name, strings.Join(names, ", "))
+}
+
+// SharedPackages returns the workspace packages app depends on, directly or
+// through other workspace packages, in path order. Their design tokens are
+// merged into the app's.
+func (ws *Workspace) SharedPackages(app *WorkspaceMember) []WorkspaceMember {
+	byName := make(map[string]int, len(ws.Members))
+	for i, m := range ws.Members {
+		byName[m.Name] = i
+	}
+
+	seen := map[string]bool{app.Path: true}
+	var shared []WorkspaceMember
+	queue := []*WorkspaceMember{app}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for dep := range current.deps {
+			i, ok := byName[dep]
+			if !ok || seen[ws.Members[i].Path] {
+				continue
+			}
+			seen[ws.Members[i].Path] = true
+			shared = append(shared, ws.Members[i])
+			queue = append(queue, &ws.Members[i])
+		}
+	}
+	sort.Slice(shared, func(i, j int) bool { return shared[i].Path < shared[j].Path })
+	return shared
+}
+
+// ScanWorkspaceStyles scans the styles of app and merges in the design
+// tokens of the shared packages it depends on. The app's own values win.
+func ScanWorkspaceStyles(ws *Workspace, app *WorkspaceMember) *StyleInfo {
+	info := ScanStyles(filepath.Join(ws.Root, filepath.FromSlash(app.Path)))
+	for _, pkg := range ws.SharedPackages(app) {
+		MergeStyles(info, ScanStyles(filepath.Join(ws.Root, filepath.FromSlash(pkg.Path))))
+	}
+	return info
+}
+
+// MergeStyles adds the tokens of src missing from dst: theme colors, CSS
+// variables, fonts and component libraries, and the CSS framework and
+// preprocessor when dst has none.
+func MergeStyles(dst, src *StyleInfo) {
+	if src == nil {
+		return
+	}
+	if dst.CSSFramework == "" && src.CSSFramework != "" {
+		dst.CSSFramework = src.CSSFramework
+		dst.StylingApproach = src.StylingApproach
+	}
+	if dst.Preprocessor == "" {
+		dst.Preprocessor = src.Preprocessor
+	}
+	if dst.ThemeColors == nil {
+		dst.ThemeColors = make(map[string]string)
+	}
+	for name, value := range src.ThemeColors {
+		if _, ok := dst.ThemeColors[name]; !ok {
+			dst.ThemeColors[name] = value
+		}
+	}
+	for _, v := range src.CSSVariables {
+		if !containsString(dst.CSSVariables, v) {
+			dst.CSSVariables = append(dst.CSSVariables, v)
+		}
+	}
+	for _, f := range src.FontFamilies {
+		if !containsString(dst.FontFamilies, f) {
+			dst.FontFamilies = append(dst.FontFamilies, f)
+		}
+	}
+	for _, lib := range src.ComponentLibs {
+		if !containsString(dst.ComponentLibs, lib) {
+			dst.ComponentLibs = append(dst.ComponentLibs, lib)
+		}
+	}
+}
+
+// PrefixPaths prefixes the paths of s with dir, making the structure of the
+// workspace member at dir relative to the workspace root.
+func (s *AppStructure) PrefixPaths(dir string) {
+	if s == nil || dir == "" || dir == "." {
+		return
+	}
+	prefix := func(paths []string) {
+		for i, p := range paths {
+			paths[i] = dir + "/" + p
+		}
+	}
+	prefix(s.Layouts)
+	prefix(s.Components)
+	prefix(s.GlobalStyles)
+}
+
+// readPackageWorkspaces returns the "workspaces" patterns of root's package.json.
+func readPackageWorkspaces(root string) []string {
+	data, err := os.ReadFile(filepath.Join(root, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || len(pkg.Workspaces) == 0 {
+		return nil
+	}
+
+	var patterns []string
+	if err := json.Unmarshal(pkg.Workspaces, &patterns); err == nil {
+		return patterns
+	}
+	var yarn struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(pkg.Workspaces, &yarn); err == nil {
+		return yarn.Packages
+	}
+	return nil
+}
+
+// nxPatterns returns the member patterns of an Nx repository from the
+// workspaceLayout of nx.json.
+func nxPatterns(nxJSON []byte) []string {
+	var cfg struct {
+		WorkspaceLayout struct {
+			AppsDir string `json:"appsDir"`
+			LibsDir string `json:"libsDir"`
+		} `json:"workspaceLayout"`
+	}
+	if err := json.Unmarshal(nxJSON, &cfg); err != nil {
+		return defaultWorkspacePatterns
+	}
+	if cfg.WorkspaceLayout.AppsDir == "" && cfg.WorkspaceLayout.LibsDir == "" {
+		return defaultWorkspacePatterns
+	}
+	var patterns []string
+	for _, dir := range []string{cfg.WorkspaceLayout.AppsDir, cfg.WorkspaceLayout.LibsDir} {
+		if dir != "" {
+			patterns = append(patterns, strings.TrimSuffix(dir, "/")+"/*")
+		}
+	}
+	return patterns
+}
+
+// expandWorkspacePatterns returns the member directories matched by the
+// workspace patterns, relative to root and sorted. Negated patterns ("!x")
+// exclude directories; "**" matches one or two levels.
+func expandWorkspacePatterns(root string, patterns []string) ([]string, error) {
+	matched := make(map[string]bool)
+	excluded := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		negate := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(strings.TrimPrefix(pattern, "!"), "./")
+		pattern = strings.TrimSuffix(pattern, "/")
+
+		globs := []string{pattern}
+		if strings.Contains(pattern, "**") {
+			globs = []string{
+				strings.ReplaceAll(pattern, "**", "*"),
+				strings.ReplaceAll(pattern, "**", "*/*"),
+			}
+		}
+		for _, g := range globs {
+			matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(g)))
+			if err != nil {
+				return nil, fmt.Errorf("invalid workspace pattern %q: %w", pattern, err)
+			}
+			for _, m := range matches {
+				if !isWorkspaceMember(m) {
+					continue
+				}
+				rel, err := filepath.Rel(root, m)
+				if err != nil || strings.Contains(filepath.ToSlash(rel), "node_modules") {
+					continue
+				}
+				if negate {
+					excluded[filepath.ToSlash(rel)] = true
+				} else {
+					matched[filepath.ToSlash(rel)] = true
+				}
+			}
+		}
+	}
+
+	var dirs []string
+	for dir := range matched {
+		if !excluded[dir] {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// isWorkspaceMember reports whether dir is a package: it has a package.json
+// or an Nx project.json.
+func isWorkspaceMember(dir string) bool {
+	return fileExists(filepath.Join(dir, "package.json")) || fileExists(filepath.Join(dir, "project.json"))
+}
+
+// scanWorkspaceMember reads the name and dependencies of the member at dir
+// (relative to root) and detects its framework.
+func scanWorkspaceMember(root, dir string) WorkspaceMember {
+	path := filepath.Join(root, filepath.FromSlash(dir))
+	m := WorkspaceMember{
+		Name:      filepath.Base(dir),
+		Path:      dir,
+		Framework: FrameworkUnknown,
+		deps:      readPackageDeps(path),
+	}
+
+	for _, file := range []string{"package.json", "project.json"} {
+		data, err := os.ReadFile(filepath.Join(path, file))
+		if err != nil {
+			continue
+		}
+		var meta struct {
+			Name        string `json:"name"`
+			ProjectType string `json:"projectType"`
+		}
+		if json.Unmarshal(data, &meta) != nil {
+			continue
+		}
+		if meta.Name != "" && file == "package.json" {
+			m.Name = meta.Name
+		}
+		if meta.ProjectType == "application" {
+			m.IsApp = true
+		}
+	}
+
+	if detection, err := DetectFramework(path); err == nil {
+		m.Framework = detection.Framework
+		m.IsFrontend = detection.IsFrontend
+		// A framework config file (next.config.js, vite.config.ts, ...) marks an app
+		if detection.ConfigFile != "" && detection.ConfigFile != "package.json" {
+			m.IsApp = true
+		}
+	}
+	if strings.HasPrefix(dir, "apps/") {
+		m.IsApp = true
+	}
+	return m
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
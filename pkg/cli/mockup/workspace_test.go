@@ -0,0 +1,185 @@
+package mockup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes files (slash-separated paths) under dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func turboWorkspace(t *testing.T) string {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pnpm-workspace.yaml": "packages:\n  - 'apps/*'\n  - 'packages/*'\n  - '!packages/legacy'\n",
+		"turbo.json":          "{}",
+		"package.json":        `{"name":"root","private":true}`,
+
+		"apps/web/package.json":     `{"name":"@acme/web","dependencies":{"next":"14.0.0","react":"18.0.0","@acme/ui":"workspace:*"}}`,
+		"apps/web/next.config.js":   "module.exports = {}",
+		"apps/web/app/globals.css":  ":root { --background: #fff; }",
+		"apps/docs/package.json":    `{"name":"@acme/docs","dependencies":{"astro":"4.0.0"}}`,
+		"apps/docs/astro.config.ts": "export default {}",
+		"apps/api/package.json":     `{"name":"@acme/api","dependencies":{"express":"4.0.0"}}`,
+
+		"packages/ui/package.json":           `{"name":"@acme/ui","dependencies":{"react":"18.0.0","@acme/tokens":"workspace:*","@radix-ui/react-dialog":"1.0.0"}}`,
+		"packages/tokens/package.json":       `{"name":"@acme/tokens"}`,
+		"packages/tokens/src/styles.css":     ":root { --primary-color: #3b82f6; --background: #000; }",
+		"packages/legacy/package.json":       `{"name":"@acme/legacy","dependencies":{"react":"16.0.0"}}`,
+		"packages/not-a-package/README.md":   "no package.json",
+		"packages/ui/node_modules/x/a.json":  "{}",
+		"packages/ui/src/components/Btn.tsx": "export const Btn = () => null",
+	})
+	return dir
+}
+
+func TestDetectWorkspace_None(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"package.json": `{"dependencies":{"react":"18.0.0"}}`})
+
+	ws, err := DetectWorkspace(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws != nil {
+		t.Fatalf("expected no workspace, got %+v", ws)
+	}
+}
+
+func TestDetectWorkspace_Turborepo(t *testing.T) {
+	ws, err := DetectWorkspace(turboWorkspace(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws == nil {
+		t.Fatal("expected a workspace")
+	}
+	if ws.Tool != WorkspaceTurborepo {
+		t.Errorf("Tool = %s, want turborepo", ws.Tool)
+	}
+
+	var paths []string
+	for _, m := range ws.Members {
+		paths = append(paths, m.Path)
+	}
+	if got := strings.Join(paths, ","); got != "apps/api,apps/docs,apps/web,packages/tokens,packages/ui" {
+		t.Errorf("members = %s", got)
+	}
+
+	var apps []string
+	for _, app := range ws.Apps() {
+		apps = append(apps, app.Path+"="+string(app.Framework))
+	}
+	if got := strings.Join(apps, ","); got != "apps/docs=astro,apps/web=nextjs" {
+		t.Errorf("apps = %s", got)
+	}
+}
+
+func TestDetectWorkspace_PackageJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		wantTool string
+	}{
+		{
+			name:     "npm",
+			files:    map[string]string{"package.json": `{"workspaces":["apps/*"]}`},
+			wantTool: WorkspaceNpm,
+		},
+		{
+			name:     "yarn",
+			files:    map[string]string{"package.json": `{"workspaces":{"packages":["apps/*"]}}`, "yarn.lock": ""},
+			wantTool: WorkspaceYarn,
+		},
+		{
+			name:     "nx",
+			files:    map[string]string{"nx.json": `{"workspaceLayout":{"appsDir":"apps"}}`},
+			wantTool: WorkspaceNx,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.files["apps/shop/package.json"] = `{"name":"shop","dependencies":{"vue":"3.0.0"}}`
+			writeFiles(t, dir, tt.files)
+
+			ws, err := DetectWorkspace(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ws == nil || ws.Tool != tt.wantTool {
+				t.Fatalf("workspace = %+v, want tool %s", ws, tt.wantTool)
+			}
+			apps := ws.Apps()
+			if len(apps) != 1 || apps[0].Name != "shop" || apps[0].Framework != FrameworkVue {
+				t.Errorf("apps = %+v, want shop (vue)", apps)
+			}
+		})
+	}
+}
+
+func TestWorkspace_FindApp(t *testing.T) {
+	ws, err := DetectWorkspace(turboWorkspace(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"web", "@acme/web", "apps/web", "apps/web/"} {
+		app, err := ws.FindApp(name)
+		if err != nil {
+			t.Errorf("FindApp(%q): %v", name, err)
+			continue
+		}
+		if app.Path != "apps/web" {
+			t.Errorf("FindApp(%q) = %s, want apps/web", name, app.Path)
+		}
+	}
+
+	_, err = ws.FindApp("mobile")
+	if err == nil || !strings.Contains(err.Error(), "docs (apps/docs)") {
+		t.Errorf("expected an error listing the apps, got %v", err)
+	}
+}
+
+func TestScanWorkspaceStyles(t *testing.T) {
+	ws, err := DetectWorkspace(turboWorkspace(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err := ws.FindApp("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var shared []string
+	for _, pkg := range ws.SharedPackages(app) {
+		shared = append(shared, pkg.Path)
+	}
+	if got := strings.Join(shared, ","); got != "packages/tokens,packages/ui" {
+		t.Errorf("shared packages = %s, want packages/tokens,packages/ui", got)
+	}
+
+	info := ScanWorkspaceStyles(ws, app)
+	if info.ThemeColors["--primary-color"] != "#3b82f6" {
+		t.Errorf("expected primary-color from packages/tokens, got %v", info.ThemeColors)
+	}
+	if info.ThemeColors["--background"] != "#fff" {
+		t.Errorf("app tokens should win: background = %q, want #fff", info.ThemeColors["--background"])
+	}
+	if !containsString(info.ComponentLibs, "Radix UI") {
+		t.Errorf("expected Radix UI from packages/ui, got %v", info.ComponentLibs)
+	}
+}
@@ -0,0 +1,57 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// TemplatesDir is where a project overrides the embedded prompt templates,
+// relative to the project root.
+const TemplatesDir = ".specledger/templates"
+
+// LoadTemplate returns the project override of a prompt template,
+// <root>/.specledger/templates/<name>, or fallback when there is none. The
+// override is validated by rendering it with sample data, so unknown fields
+// and syntax errors are reported before anything runs. overridePath is empty
+// when fallback is returned.
+func LoadTemplate(root, name, fallback string, sample any) (content, overridePath string, err error) {
+	path := filepath.Join(root, TemplatesDir, name)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fallback, "", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read prompt template %s: %w", path, err)
+	}
+
+	content = string(data)
+	if err := ValidateTemplate(name, content, sample); err != nil {
+		return "", "", fmt.Errorf("invalid prompt template %s: %w", filepath.Join(TemplatesDir, name), err)
+	}
+	return content, path, nil
+}
+
+// ValidateTemplate parses a template and executes it against sample data.
+func ValidateTemplate(name, content string, sample any) error {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	return tmpl.Execute(&buf, sample)
+}
+
+// ConstitutionFile is the project constitution, relative to the project root.
+const ConstitutionFile = ".specledger/memory/constitution.md"
+
+// ExistingPath returns rel when root/rel exists, otherwise "", so templates
+// can test for optional project files with {{if}}.
+func ExistingPath(root, rel string) string {
+	if _, err := os.Stat(filepath.Join(root, rel)); err != nil {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,82 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeOverride(t *testing.T, root, name, content string) {
+	t.Helper()
+	dir := filepath.Join(root, TemplatesDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	sample := struct {
+		Name  string
+		Items []string
+	}{Name: "x", Items: []string{"a"}}
+
+	t.Run("no override", func(t *testing.T) {
+		content, path, err := LoadTemplate(t.TempDir(), "p.tmpl", "embedded", sample)
+		if err != nil || content != "embedded" || path != "" {
+			t.Errorf("LoadTemplate() = %q, %q, %v; want embedded", content, path, err)
+		}
+	})
+
+	t.Run("valid override", func(t *testing.T) {
+		root := t.TempDir()
+		writeOverride(t, root, "p.tmpl", "Hi {{.Name}}{{range .Items}} {{.}}{{end}}")
+		content, path, err := LoadTemplate(root, "p.tmpl", "embedded", sample)
+		if err != nil {
+			t.Fatalf("LoadTemplate() error: %v", err)
+		}
+		if !strings.HasPrefix(content, "Hi") || path == "" {
+			t.Errorf("LoadTemplate() = %q, %q; want the override", content, path)
+		}
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		root := t.TempDir()
+		writeOverride(t, root, "p.tmpl", "Hi {{.Name")
+		if _, _, err := LoadTemplate(root, "p.tmpl", "embedded", sample); err == nil {
+			t.Error("expected a syntax error")
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		root := t.TempDir()
+		writeOverride(t, root, "p.tmpl", "{{range .Items}}{{.Missing}}{{end}}")
+		_, _, err := LoadTemplate(root, "p.tmpl", "embedded", sample)
+		if err == nil {
+			t.Fatal("expected an error for an unknown field")
+		}
+		if !strings.Contains(err.Error(), filepath.Join(TemplatesDir, "p.tmpl")) {
+			t.Errorf("error should name the override file: %v", err)
+		}
+	})
+}
+
+func TestExistingPath(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".specledger", "memory"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ConstitutionFile), []byte("# C"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ExistingPath(root, ConstitutionFile); got != ConstitutionFile {
+		t.Errorf("ExistingPath() = %q, want %q", got, ConstitutionFile)
+	}
+	if got := ExistingPath(root, "specledger/001-x/plan.md"); got != "" {
+		t.Errorf("ExistingPath() = %q for a missing file, want empty", got)
+	}
+}
@@ -0,0 +1,132 @@
+package revise
+
+import "sort"
+
+// DefaultTokenBudget is the default maximum estimated size of a revision
+// prompt. Larger sets of comments are split into batches, one agent run each.
+const DefaultTokenBudget = 8000
+
+// Batch is a set of comments addressed by one agent run.
+type Batch struct {
+	Number   int // 1-based
+	Comments []ProcessedComment
+	Prompt   string
+	Tokens   int
+}
+
+// CommentIDs returns the IDs of the comments the batch covers.
+func (b *Batch) CommentIDs() []string {
+	ids := make([]string, 0, len(b.Comments))
+	for _, p := range b.Comments {
+		ids = append(ids, p.Comment.ID)
+	}
+	return ids
+}
+
+// BatchComments splits processed comments into batches whose prompt, as
+// rendered by render, stays within budget tokens (0 for no limit). Comments
+// are grouped by file, in order of first appearance, and ordered by line
+// within a file so feedback on the same section stays together; a file's
+// comments are only split across batches when they don't fit in one. A
+// comment over the budget on its own gets its own batch.
+func BatchComments(processed []ProcessedComment, budget int, render func([]ProcessedComment) (string, error)) ([]Batch, error) {
+	var batches []Batch
+	var current []ProcessedComment
+	currentPrompt := ""
+
+	// fits renders current plus more and reports whether it is within budget
+	fits := func(more ...ProcessedComment) (string, bool, error) {
+		candidate := make([]ProcessedComment, 0, len(current)+len(more))
+		candidate = append(candidate, current...)
+		candidate = append(candidate, more...)
+		prompt, err := render(candidate)
+		if err != nil {
+			return "", false, err
+		}
+		return prompt, budget <= 0 || EstimateTokens(prompt) <= budget, nil
+	}
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		batches = append(batches, Batch{
+			Number:   len(batches) + 1,
+			Comments: current,
+			Prompt:   currentPrompt,
+			Tokens:   EstimateTokens(currentPrompt),
+		})
+		current = nil
+		currentPrompt = ""
+	}
+
+	for _, group := range groupByFile(processed) {
+		// The whole file in the current batch, or in a new one
+		prompt, ok, err := fits(group...)
+		if err != nil {
+			return nil, err
+		}
+		if !ok && len(current) > 0 {
+			saved := current
+			current = nil
+			prompt, ok, err = fits(group...)
+			current = saved
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				flush()
+			}
+		}
+		if ok {
+			current = append(current, group...)
+			currentPrompt = prompt
+			continue
+		}
+
+		// Too large for one batch: split the file comment by comment
+		for _, p := range group {
+			prompt, ok, err := fits(p)
+			if err != nil {
+				return nil, err
+			}
+			if !ok && len(current) > 0 {
+				flush()
+				if prompt, _, err = fits(p); err != nil {
+					return nil, err
+				}
+			}
+			current = append(current, p)
+			currentPrompt = prompt
+		}
+	}
+	flush()
+
+	return batches, nil
+}
+
+// groupByFile groups comments by file in order of first appearance, ordered
+// by line within each file (comments without a line last).
+func groupByFile(processed []ProcessedComment) [][]ProcessedComment {
+	var order []string
+	byFile := make(map[string][]ProcessedComment)
+	for _, p := range processed {
+		if _, seen := byFile[p.Comment.FilePath]; !seen {
+			order = append(order, p.Comment.FilePath)
+		}
+		byFile[p.Comment.FilePath] = append(byFile[p.Comment.FilePath], p)
+	}
+
+	groups := make([][]ProcessedComment, 0, len(order))
+	for _, path := range order {
+		group := byFile[path]
+		sort.SliceStable(group, func(i, j int) bool {
+			li, lj := group[i].Comment.Line, group[j].Comment.Line
+			if li == nil || lj == nil {
+				return li != nil && lj == nil
+			}
+			return *li < *lj
+		})
+		groups = append(groups, group)
+	}
+	return groups
+}
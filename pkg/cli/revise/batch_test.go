@@ -0,0 +1,96 @@
+package revise
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func batchTestComments() []ProcessedComment {
+	line := func(n int) *int { return &n }
+	var processed []ProcessedComment
+	add := func(file string, l *int) {
+		i := len(processed) + 1
+		processed = append(processed, ProcessedComment{
+			Comment: ReviewComment{ID: fmt.Sprintf("c%d", i), FilePath: file, Line: l, Content: strings.Repeat("word ", 50)},
+			Index:   i,
+		})
+	}
+	add("spec.md", line(30))
+	add("plan.md", line(5))
+	add("spec.md", line(10))
+	add("spec.md", nil)
+	add("plan.md", line(1))
+	return processed
+}
+
+func renderForTest(batch []ProcessedComment) (string, error) {
+	return RenderPrompt(BuildRevisionContext("010-x", batch, nil))
+}
+
+func TestBatchComments_NoLimit(t *testing.T) {
+	batches, err := BatchComments(batchTestComments(), 0, renderForTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 1 {
+		t.Fatalf("expected 1 batch, got %d", len(batches))
+	}
+
+	// Grouped by file in order of appearance, by line within a file
+	got := strings.Join(batches[0].CommentIDs(), ",")
+	if got != "c3,c1,c4,c5,c2" {
+		t.Errorf("order = %s, want c3,c1,c4,c5,c2", got)
+	}
+	if batches[0].Tokens != EstimateTokens(batches[0].Prompt) {
+		t.Errorf("Tokens = %d, want the estimate of the prompt", batches[0].Tokens)
+	}
+}
+
+func TestBatchComments_Budget(t *testing.T) {
+	processed := batchTestComments()
+
+	one, _ := renderForTest(processed[:1])
+	all, _ := renderForTest(processed)
+	// Room for a file's comments but not for everything
+	budget := EstimateTokens(one) + (EstimateTokens(all)-EstimateTokens(one))/2
+
+	batches, err := BatchComments(processed, budget, renderForTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) < 2 {
+		t.Fatalf("expected several batches under a budget of %d, got %d", budget, len(batches))
+	}
+
+	seen := make(map[string]int)
+	for i, b := range batches {
+		if b.Number != i+1 {
+			t.Errorf("batch %d numbered %d", i+1, b.Number)
+		}
+		if b.Tokens > budget {
+			t.Errorf("batch %d: %d tokens over the budget of %d", b.Number, b.Tokens, budget)
+		}
+		for _, id := range b.CommentIDs() {
+			seen[id]++
+		}
+	}
+	for _, p := range processed {
+		if seen[p.Comment.ID] != 1 {
+			t.Errorf("comment %s covered %d times, want once", p.Comment.ID, seen[p.Comment.ID])
+		}
+	}
+}
+
+func TestBatchComments_OversizedComment(t *testing.T) {
+	processed := batchTestComments()[:2]
+
+	// Every comment alone exceeds the budget: one batch each
+	batches, err := BatchComments(processed, 10, renderForTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 2 {
+		t.Fatalf("expected 2 batches, got %d", len(batches))
+	}
+}
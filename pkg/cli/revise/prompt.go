@@ -5,17 +5,67 @@ import (
 	_ "embed"
 	"fmt"
 	"math"
+	"path"
 	"text/template"
 
 	"github.com/specledger/specledger/pkg/cli/comment"
+	"github.com/specledger/specledger/pkg/cli/prompt"
 )
 
 //go:embed prompt.tmpl
 var promptTemplate string
 
+// PromptOverrideFile is the name of the project override of the revision
+// prompt template, in prompt.TemplatesDir.
+const PromptOverrideFile = "revise-prompt.tmpl"
+
 // RenderPrompt renders the revision prompt template with the given context.
 func RenderPrompt(ctx RevisionContext) (string, error) {
-	tmpl, err := template.New("revision").Parse(promptTemplate)
+	return renderPrompt(promptTemplate, ctx)
+}
+
+// RenderProjectPrompt renders the project's revise-prompt.tmpl override under
+// root when there is one, otherwise the embedded template. An invalid
+// override is an error.
+func RenderProjectPrompt(root string, ctx RevisionContext) (string, error) {
+	content, _, err := prompt.LoadTemplate(root, PromptOverrideFile, promptTemplate, samplePromptContext())
+	if err != nil {
+		return "", err
+	}
+	return renderPrompt(content, ctx)
+}
+
+// AddProjectPaths sets the constitution, spec and plan paths of ctx.
+// specDir is the spec's artifact directory relative to root.
+func (ctx *RevisionContext) AddProjectPaths(root, specDir string) {
+	ctx.ConstitutionPath = prompt.ExistingPath(root, prompt.ConstitutionFile)
+	ctx.SpecPath = prompt.ExistingPath(root, path.Join(specDir, "spec.md"))
+	ctx.PlanPath = prompt.ExistingPath(root, path.Join(specDir, "plan.md"))
+}
+
+// samplePromptContext exercises every field of RevisionContext, to validate
+// template overrides.
+func samplePromptContext() RevisionContext {
+	return RevisionContext{
+		SpecKey: "001-sample",
+		Comments: []PromptComment{{
+			Index:    1,
+			ID:       "sample",
+			FilePath: "specledger/001-sample/spec.md",
+			Target:   "sample text",
+			Lines:    "1",
+			Feedback: "sample feedback",
+			Guidance: "sample guidance",
+			Replies:  []ThreadReply{{ID: "reply", ParentID: "sample", AuthorName: "reviewer", Content: "sample reply"}},
+		}},
+		ConstitutionPath: prompt.ConstitutionFile,
+		SpecPath:         "specledger/001-sample/spec.md",
+		PlanPath:         "specledger/001-sample/plan.md",
+	}
+}
+
+func renderPrompt(content string, ctx RevisionContext) (string, error) {
+	tmpl, err := template.New("revision").Parse(content)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse prompt template: %w", err)
 	}
@@ -55,6 +105,13 @@ func BuildRevisionContext(specKey string, processed []ProcessedComment, replies
 			target = target[:197] + "..."
 		}
 
+		lines := ""
+		if p.Comment.StartLine != nil && p.Comment.Line != nil {
+			lines = fmt.Sprintf("%d-%d", *p.Comment.StartLine, *p.Comment.Line)
+		} else if p.Comment.Line != nil {
+			lines = fmt.Sprintf("%d", *p.Comment.Line)
+		}
+
 		var threadReplies []ThreadReply
 		if reps, ok := replyMap[p.Comment.ID]; ok {
 			threadReplies = make([]ThreadReply, 0, len(reps))
@@ -73,6 +130,8 @@ func BuildRevisionContext(specKey string, processed []ProcessedComment, replies
 			ID:       p.Comment.ID,
 			FilePath: p.Comment.FilePath,
 			Target:   target,
+			Lines:    lines,
+			Outdated: p.Comment.AnchorStatus == comment.AnchorOutdated,
 			Feedback: p.Comment.Content,
 			Guidance: p.Guidance,
 			Replies:  threadReplies,
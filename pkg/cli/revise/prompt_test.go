@@ -1,6 +1,8 @@
 package revise
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -191,6 +193,39 @@ func TestBuildRevisionContext_Truncation(t *testing.T) {
 	}
 }
 
+func TestRenderPrompt_Location(t *testing.T) {
+	start, end := 12, 14
+	processed := []ProcessedComment{
+		{
+			Comment: ReviewComment{ID: "id-1", FilePath: "spec.md", Content: "moved", SelectedText: "a", StartLine: &start, Line: &end, AnchorStatus: comment.AnchorMoved},
+			Index:   1,
+		},
+		{
+			Comment: ReviewComment{ID: "id-2", FilePath: "spec.md", Content: "gone", SelectedText: "b", Line: &end, AnchorStatus: comment.AnchorOutdated},
+			Index:   2,
+		},
+	}
+
+	ctx := BuildRevisionContext("spec", processed, nil)
+	if ctx.Comments[0].Lines != "12-14" || ctx.Comments[0].Outdated {
+		t.Errorf("comment 1: Lines = %q, Outdated = %v", ctx.Comments[0].Lines, ctx.Comments[0].Outdated)
+	}
+	if !ctx.Comments[1].Outdated {
+		t.Errorf("comment 2: expected Outdated")
+	}
+
+	prompt, err := RenderPrompt(ctx)
+	if err != nil {
+		t.Fatalf("RenderPrompt returned error: %v", err)
+	}
+	if !strings.Contains(prompt, "- **Location**: lines 12-14") {
+		t.Errorf("expected line location in prompt, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "- **Location**: outdated") {
+		t.Errorf("expected outdated location in prompt, got:\n%s", prompt)
+	}
+}
+
 func TestBuildRevisionContext_WithReplies(t *testing.T) {
 	processed := []ProcessedComment{
 		{
@@ -323,3 +358,46 @@ func TestBuildReplyMap_Nil(t *testing.T) {
 		t.Errorf("BuildReplyMap(nil) should return empty map, got %d entries", len(m))
 	}
 }
+
+func TestRenderProjectPrompt(t *testing.T) {
+	root := t.TempDir()
+	ctx := RevisionContext{
+		SpecKey:  "010-x",
+		Comments: []PromptComment{{Index: 1, FilePath: "spec.md", Feedback: "feedback"}},
+	}
+
+	// No override: the embedded template
+	got, err := RenderProjectPrompt(root, ctx)
+	if err != nil {
+		t.Fatalf("RenderProjectPrompt() error: %v", err)
+	}
+	want, _ := RenderPrompt(ctx)
+	if got != want {
+		t.Error("expected the embedded template without an override")
+	}
+
+	dir := filepath.Join(root, ".specledger", "templates")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	override := "Revise {{.SpecKey}} per {{.ConstitutionPath}}{{range .Comments}}\n- {{.FilePath}}: {{.Feedback}}{{end}}"
+	if err := os.WriteFile(filepath.Join(dir, PromptOverrideFile), []byte(override), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx.ConstitutionPath = ".specledger/memory/constitution.md"
+	got, err = RenderProjectPrompt(root, ctx)
+	if err != nil {
+		t.Fatalf("RenderProjectPrompt() error: %v", err)
+	}
+	if got != "Revise 010-x per .specledger/memory/constitution.md\n- spec.md: feedback" {
+		t.Errorf("unexpected override output:\n%s", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, PromptOverrideFile), []byte("{{.Nope}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RenderProjectPrompt(root, ctx); err == nil || !strings.Contains(err.Error(), PromptOverrideFile) {
+		t.Errorf("expected a validation error naming %s, got %v", PromptOverrideFile, err)
+	}
+}
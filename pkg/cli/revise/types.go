@@ -15,6 +15,12 @@ type ProcessedComment struct {
 type RevisionContext struct {
 	SpecKey  string
 	Comments []PromptComment
+
+	// Project files for custom templates, relative to the repo root
+	// ("" when the file doesn't exist). Set by AddProjectPaths.
+	ConstitutionPath string
+	SpecPath         string
+	PlanPath         string
 }
 
 // PromptComment is a single comment entry in the revision prompt template.
@@ -23,6 +29,8 @@ type PromptComment struct {
 	ID       string // Comment UUID (internal, for resolution)
 	FilePath string
 	Target   string // selected_text, "Line N", or "General"
+	Lines    string // Current line or range of the target ("12" or "12-15"), if known
+	Outdated bool   // Target no longer found in the current file version
 	Feedback string // Comment content
 	Guidance string // Optional user guidance
 	Replies  []ThreadReply
@@ -0,0 +1,100 @@
+package revise
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/specledger/specledger/pkg/cli/comment"
+)
+
+// NotAddressedReply is posted on comments whose target the revision didn't touch.
+const NotAddressedReply = "Not addressed in this revision: the commented text was not changed."
+
+// Verification is whether a revision touched a comment's target.
+type Verification struct {
+	Changed bool
+	Reason  string
+}
+
+// Snapshot holds the commented files as they were before the agent ran.
+type Snapshot struct {
+	root  string
+	files map[string]*string // nil when the file didn't exist
+}
+
+// TakeSnapshot reads the files of the processed comments under root.
+func TakeSnapshot(root string, processed []ProcessedComment) *Snapshot {
+	s := &Snapshot{root: root, files: make(map[string]*string)}
+	for _, p := range processed {
+		path := p.Comment.FilePath
+		if _, ok := s.files[path]; ok {
+			continue
+		}
+		s.files[path] = s.read(path)
+	}
+	return s
+}
+
+// Verify compares each comment's target region with the current version of
+// its file. Comments are anchored to the snapshot (see comment.ReanchorComments);
+// comments without a usable anchor are checked at the file level.
+func (s *Snapshot) Verify(processed []ProcessedComment) map[string]Verification {
+	results := make(map[string]Verification, len(processed))
+	lineMaps := make(map[string]*comment.LineMap)
+
+	for _, p := range processed {
+		c := p.Comment
+		before := s.files[c.FilePath]
+		after := s.read(c.FilePath)
+
+		switch {
+		case before == nil && after == nil:
+			results[c.ID] = Verification{Reason: "file not found"}
+			continue
+		case after == nil:
+			results[c.ID] = Verification{Changed: true, Reason: "file deleted"}
+			continue
+		case before == nil:
+			results[c.ID] = Verification{Changed: true, Reason: "file created"}
+			continue
+		case *before == *after:
+			results[c.ID] = Verification{Reason: "file unchanged"}
+			continue
+		}
+
+		if c.Line == nil || c.AnchorStatus == comment.AnchorOutdated {
+			results[c.ID] = Verification{Changed: true, Reason: "file changed (no line anchor)"}
+			continue
+		}
+
+		lineMap, ok := lineMaps[c.FilePath]
+		if !ok {
+			lineMap, _ = comment.ContentLineMap(*before, *after)
+			lineMaps[c.FilePath] = lineMap
+		}
+		if lineMap == nil {
+			results[c.ID] = Verification{Changed: true, Reason: "file changed"}
+			continue
+		}
+
+		start := *c.Line
+		if c.StartLine != nil {
+			start = *c.StartLine
+		}
+		if lineMap.RegionChanged(start, *c.Line) {
+			results[c.ID] = Verification{Changed: true, Reason: "commented lines changed"}
+		} else {
+			results[c.ID] = Verification{Reason: "commented lines unchanged"}
+		}
+	}
+	return results
+}
+
+func (s *Snapshot) read(path string) *string {
+	data, err := os.ReadFile(filepath.Join(s.root, filepath.FromSlash(path)))
+	if err != nil {
+		return nil
+	}
+	content := string(data)
+	return &content
+}
@@ -0,0 +1,46 @@
+package revise
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshotVerify(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("spec.md", "# Spec\nTokens expire after 1h.\nRefresh is automatic.\nOut of scope: SSO.\n")
+	write("plan.md", "# Plan\n")
+
+	line2, line3, line4 := 2, 3, 4
+	processed := []ProcessedComment{
+		{Comment: ReviewComment{ID: "edited", FilePath: "spec.md", SelectedText: "1h", Line: &line2}},
+		{Comment: ReviewComment{ID: "skipped", FilePath: "spec.md", SelectedText: "automatic", Line: &line3}},
+		{Comment: ReviewComment{ID: "range", FilePath: "spec.md", StartLine: &line3, Line: &line4}},
+		{Comment: ReviewComment{ID: "untouched-file", FilePath: "plan.md", Content: "general"}},
+	}
+
+	snapshot := TakeSnapshot(root, processed)
+
+	// The agent rewrites line 2 and inserts a line inside the 3-4 range
+	write("spec.md", "# Spec\nTokens expire after 15 minutes.\nRefresh is automatic.\nRefresh tokens rotate.\nOut of scope: SSO.\n")
+
+	results := snapshot.Verify(processed)
+
+	want := map[string]bool{"edited": true, "skipped": false, "range": true, "untouched-file": false}
+	for id, changed := range want {
+		if got := results[id]; got.Changed != changed {
+			t.Errorf("%s: Changed = %v (%s), want %v", id, got.Changed, got.Reason, changed)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/cli/auth"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// Backend stores compressed session content together with its queryable
+// metadata. Implementations exist for Supabase (the hosted default), a local
+// directory and S3-compatible object storage.
+type Backend interface {
+	// Name returns the backend name as used in specledger.yaml
+	Name() string
+	// Store uploads compressed content to input.StoragePath and records its metadata
+	Store(input *CreateSessionInput, data []byte) (*SessionMetadata, error)
+	// Query returns sessions matching opts
+	Query(opts *QueryOptions) ([]SessionMetadata, error)
+	// GetByID returns a session by ID, or nil if not found
+	GetByID(sessionID string) (*SessionMetadata, error)
+	// Download returns the compressed content stored at storagePath
+	Download(storagePath string) ([]byte, error)
+}
+
+// Identity identifies the author of captured sessions.
+type Identity struct {
+	UserID string // Stored as author_id
+	Email  string // Stored as the session content author
+}
+
+// LoadStorageConfig reads the session storage settings from the project's
+// specledger.yaml. Projects without a session section use Supabase.
+func LoadStorageConfig(workdir string) metadata.SessionStorageConfig {
+	meta, err := metadata.LoadFromProject(workdir)
+	if err != nil || meta.Session == nil {
+		return metadata.SessionStorageConfig{Backend: metadata.SessionBackendSupabase}
+	}
+	cfg := meta.Session.Storage
+	if cfg.Backend == "" {
+		cfg.Backend = metadata.SessionBackendSupabase
+	}
+	return cfg
+}
+
+// OpenBackend returns the storage backend configured for the project at workdir.
+// The Supabase backend requires a valid login.
+func OpenBackend(workdir string) (Backend, error) {
+	return OpenBackendWithConfig(workdir, LoadStorageConfig(workdir))
+}
+
+// OpenBackendWithConfig returns the storage backend described by cfg.
+func OpenBackendWithConfig(workdir string, cfg metadata.SessionStorageConfig) (Backend, error) {
+	switch cfg.Backend {
+	case "", metadata.SessionBackendSupabase:
+		accessToken, err := auth.GetValidAccessToken()
+		if err != nil {
+			return nil, fmt.Errorf("authentication required: run 'sl auth login' first\n\nDetails: %w", err)
+		}
+		return NewSupabaseBackend(accessToken), nil
+	case metadata.SessionBackendLocal:
+		return NewLocalBackend(LocalBackendDir(workdir, cfg.Path)), nil
+	case metadata.SessionBackendS3:
+		creds, err := S3CredentialsFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return NewS3Backend(cfg, creds), nil
+	default:
+		return nil, fmt.Errorf("unknown session storage backend %q (valid: supabase, local, s3)", cfg.Backend)
+	}
+}
+
+// ResolveIdentity returns the author recorded with captured sessions.
+// Supabase sessions are attributed to the logged-in user; other backends use
+// the git author email so no account is needed.
+func ResolveIdentity(workdir, backend string) (*Identity, error) {
+	if backend == "" || backend == metadata.SessionBackendSupabase {
+		creds, err := auth.LoadCredentials()
+		if err != nil || creds == nil {
+			return nil, fmt.Errorf("not authenticated")
+		}
+		return &Identity{UserID: creds.UserID, Email: creds.UserEmail}, nil
+	}
+
+	cmd := exec.Command("git", "config", "user.email")
+	cmd.Dir = workdir
+	output, err := cmd.Output()
+	email := strings.TrimSpace(string(output))
+	if err != nil || email == "" {
+		email = os.Getenv("USER")
+	}
+	if email == "" {
+		return nil, fmt.Errorf("no git user.email configured")
+	}
+	return &Identity{UserID: email, Email: email}, nil
+}
+
+// ResolveProjectID returns the project identifier used in storage paths.
+// Supabase needs the registered project UUID; other backends fall back to the
+// project name so local-only projects work without registration.
+func ResolveProjectID(workdir, backend string) (string, error) {
+	if backend == "" || backend == metadata.SessionBackendSupabase {
+		return GetProjectIDWithFallback(workdir)
+	}
+
+	if id, err := GetProjectID(workdir); err == nil {
+		return id, nil
+	}
+	if meta, err := metadata.LoadFromProject(workdir); err == nil && meta.Project.Name != "" {
+		return meta.Project.Name, nil
+	}
+	return filepath.Base(workdir), nil
+}
+
+// FindSession looks a session up by ID (UUID), commit hash (full or partial)
+// or task ID. Returns nil if nothing matches.
+func FindSession(b Backend, projectID, identifier string) (*SessionMetadata, error) {
+	// Check if identifier looks like a UUID (36 chars with dashes)
+	if len(identifier) == 36 && identifier[8] == '-' && identifier[13] == '-' {
+		if s, err := b.GetByID(identifier); err == nil && s != nil {
+			return s, nil
+		}
+	}
+
+	sessions, err := b.Query(&QueryOptions{ProjectID: projectID, CommitHash: identifier, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) > 0 {
+		return &sessions[0], nil
+	}
+
+	sessions, err = b.Query(&QueryOptions{ProjectID: projectID, TaskID: identifier, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) > 0 {
+		return &sessions[0], nil
+	}
+
+	return nil, nil
+}
+
+// metaPathFor returns where backends without a database keep the metadata
+// of the session stored at storagePath.
+func metaPathFor(storagePath string) string {
+	return strings.TrimSuffix(storagePath, ".json.gz") + ".meta.json"
+}
+
+// filterSessions applies QueryOptions in memory, for backends without a
+// query engine.
+func filterSessions(all []SessionMetadata, opts *QueryOptions) []SessionMetadata {
+	var result []SessionMetadata
+	for _, s := range all {
+		if opts.ProjectID != "" && s.ProjectID != opts.ProjectID {
+			continue
+		}
+		if opts.FeatureBranch != "" && s.FeatureBranch != opts.FeatureBranch {
+			continue
+		}
+		if opts.CommitHash != "" && (s.CommitHash == nil || !strings.HasPrefix(*s.CommitHash, opts.CommitHash)) {
+			continue
+		}
+		if opts.TaskID != "" && (s.TaskID == nil || *s.TaskID != opts.TaskID) {
+			continue
+		}
+		if opts.AuthorID != "" && s.AuthorID != opts.AuthorID {
+			continue
+		}
+		if opts.StartDate != nil && s.CreatedAt.Before(*opts.StartDate) {
+			continue
+		}
+		if opts.EndDate != nil && s.CreatedAt.After(*opts.EndDate) {
+			continue
+		}
+		result = append(result, s)
+	}
+
+	// Only created_at ordering is supported in memory
+	sort.SliceStable(result, func(i, j int) bool {
+		if opts.OrderDesc {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	if opts.Offset > 0 {
+		if opts.Offset >= len(result) {
+			return nil
+		}
+		result = result[opts.Offset:]
+	}
+	if opts.Limit > 0 && len(result) > opts.Limit {
+		result = result[:opts.Limit]
+	}
+	return result
+}
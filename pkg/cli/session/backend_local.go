@@ -0,0 +1,165 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// DefaultLocalSessionsDir is where the local backend stores sessions,
+// relative to the project root.
+const DefaultLocalSessionsDir = ".specledger/sessions"
+
+// LocalBackend stores sessions in a directory, using the same layout as the
+// hosted storage bucket: {project_id}/{branch}/{identifier}.json.gz with a
+// {identifier}.meta.json file next to each session.
+type LocalBackend struct {
+	dir string
+}
+
+// NewLocalBackend creates a local backend rooted at dir
+func NewLocalBackend(dir string) *LocalBackend {
+	return &LocalBackend{dir: dir}
+}
+
+// LocalBackendDir resolves the configured path against the project root
+func LocalBackendDir(projectRoot, path string) string {
+	if path == "" {
+		path = DefaultLocalSessionsDir
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(projectRoot, path)
+}
+
+// Name returns the backend name
+func (b *LocalBackend) Name() string {
+	return metadata.SessionBackendLocal
+}
+
+// Dir returns the directory sessions are stored in
+func (b *LocalBackend) Dir() string {
+	return b.dir
+}
+
+// Store writes content and metadata under the backend directory
+func (b *LocalBackend) Store(input *CreateSessionInput, data []byte) (*SessionMetadata, error) {
+	dataPath := filepath.Join(b.dir, filepath.FromSlash(input.StoragePath))
+	if err := ensureDir(filepath.Dir(dataPath)); err != nil {
+		return nil, fmt.Errorf("failed to create session directory: %w", err)
+	}
+	if err := os.WriteFile(dataPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write session data: %w", err)
+	}
+
+	meta := newSessionMetadata(input)
+	metaData, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	metaPath := filepath.Join(b.dir, filepath.FromSlash(metaPathFor(input.StoragePath)))
+	if err := os.WriteFile(metaPath, metaData, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write metadata: %w", err)
+	}
+
+	return meta, nil
+}
+
+// Query reads every metadata file below the project (and branch, if given)
+// and filters them in memory
+func (b *LocalBackend) Query(opts *QueryOptions) ([]SessionMetadata, error) {
+	root := b.dir
+	if opts.ProjectID != "" {
+		root = filepath.Join(root, opts.ProjectID)
+		if opts.FeatureBranch != "" {
+			root = filepath.Join(root, filepath.FromSlash(opts.FeatureBranch))
+		}
+	}
+
+	all, err := b.readAll(root)
+	if err != nil {
+		return nil, err
+	}
+	return filterSessions(all, opts), nil
+}
+
+// GetByID scans all metadata files for the given ID
+func (b *LocalBackend) GetByID(sessionID string) (*SessionMetadata, error) {
+	all, err := b.readAll(b.dir)
+	if err != nil {
+		return nil, err
+	}
+	for i := range all {
+		if all[i].ID == sessionID {
+			return &all[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Download reads the content stored at storagePath
+func (b *LocalBackend) Download(storagePath string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(b.dir, filepath.FromSlash(storagePath)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	return data, nil
+}
+
+// readAll loads every .meta.json file below root
+func (b *LocalBackend) readAll(root string) ([]SessionMetadata, error) {
+	var sessions []SessionMetadata
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".meta.json") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		var meta SessionMetadata
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return nil
+		}
+		sessions = append(sessions, meta)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// newSessionMetadata builds the record that backends without a database
+// store next to the session content.
+func newSessionMetadata(input *CreateSessionInput) *SessionMetadata {
+	return &SessionMetadata{
+		ID:            uuid.New().String(),
+		ProjectID:     input.ProjectID,
+		FeatureBranch: input.FeatureBranch,
+		CommitHash:    input.CommitHash,
+		TaskID:        input.TaskID,
+		AuthorID:      input.AuthorID,
+		StoragePath:   input.StoragePath,
+		Status:        input.Status,
+		SizeBytes:     input.SizeBytes,
+		RawSizeBytes:  input.RawSizeBytes,
+		MessageCount:  input.MessageCount,
+		Redactions:    input.Redactions,
+		CreatedAt:     time.Now().UTC(),
+	}
+}
@@ -0,0 +1,373 @@
+package session
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// S3Credentials are the access keys used to sign S3 requests
+type S3Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// S3CredentialsFromEnv reads credentials from SPECLEDGER_S3_ACCESS_KEY_ID /
+// SPECLEDGER_S3_SECRET_ACCESS_KEY, falling back to the standard AWS variables.
+func S3CredentialsFromEnv() (*S3Credentials, error) {
+	creds := &S3Credentials{
+		AccessKeyID:     firstEnv("SPECLEDGER_S3_ACCESS_KEY_ID", "AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: firstEnv("SPECLEDGER_S3_SECRET_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    firstEnv("SPECLEDGER_S3_SESSION_TOKEN", "AWS_SESSION_TOKEN"),
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3 session storage requires SPECLEDGER_S3_ACCESS_KEY_ID and SPECLEDGER_S3_SECRET_ACCESS_KEY (or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+	}
+	return creds, nil
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// S3Backend stores sessions in an S3-compatible bucket (AWS S3, MinIO, R2,
+// ...) using path-style requests. Metadata is kept as a .meta.json object
+// next to each session.
+type S3Backend struct {
+	endpoint string
+	bucket   string
+	region   string
+	prefix   string
+	creds    *S3Credentials
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3Backend creates an S3 backend from the project configuration
+func NewS3Backend(cfg metadata.SessionStorageConfig, creds *S3Credentials) *S3Backend {
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3Backend{
+		endpoint: strings.TrimSuffix(cfg.Endpoint, "/"),
+		bucket:   cfg.Bucket,
+		region:   region,
+		prefix:   strings.Trim(cfg.Prefix, "/"),
+		creds:    creds,
+		client:   &http.Client{Timeout: StorageTimeout},
+		now:      time.Now,
+	}
+}
+
+// Name returns the backend name
+func (b *S3Backend) Name() string {
+	return metadata.SessionBackendS3
+}
+
+// Store uploads content, then its metadata object
+func (b *S3Backend) Store(input *CreateSessionInput, data []byte) (*SessionMetadata, error) {
+	if err := b.putObject(input.StoragePath, data, "application/gzip"); err != nil {
+		return nil, fmt.Errorf("storage upload failed: %w", err)
+	}
+
+	meta := newSessionMetadata(input)
+	metaData, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	if err := b.putObject(metaPathFor(input.StoragePath), metaData, "application/json"); err != nil {
+		return nil, fmt.Errorf("metadata creation failed: %w", err)
+	}
+	return meta, nil
+}
+
+// Query lists metadata objects below the project (and branch, if given) and
+// filters them in memory
+func (b *S3Backend) Query(opts *QueryOptions) ([]SessionMetadata, error) {
+	listPrefix := ""
+	if opts.ProjectID != "" {
+		listPrefix = opts.ProjectID + "/"
+		if opts.FeatureBranch != "" {
+			listPrefix += opts.FeatureBranch + "/"
+		}
+	}
+
+	all, err := b.readAll(listPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return filterSessions(all, opts), nil
+}
+
+// GetByID scans all metadata objects for the given ID
+func (b *S3Backend) GetByID(sessionID string) (*SessionMetadata, error) {
+	all, err := b.readAll("")
+	if err != nil {
+		return nil, err
+	}
+	for i := range all {
+		if all[i].ID == sessionID {
+			return &all[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Download fetches the content stored at storagePath
+func (b *S3Backend) Download(storagePath string) ([]byte, error) {
+	return b.getObject(storagePath)
+}
+
+// readAll fetches every .meta.json object below listPrefix
+func (b *S3Backend) readAll(listPrefix string) ([]SessionMetadata, error) {
+	keys, err := b.listKeys(listPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []SessionMetadata
+	for _, key := range keys {
+		if !strings.HasSuffix(key, ".meta.json") {
+			continue
+		}
+		data, err := b.getObject(key)
+		if err != nil {
+			continue // Skip unreadable entries
+		}
+		var meta SessionMetadata
+		if err := json.Unmarshal(data, &meta); err != nil {
+			continue
+		}
+		sessions = append(sessions, meta)
+	}
+	return sessions, nil
+}
+
+// objectKey prepends the configured prefix to a storage path
+func (b *S3Backend) objectKey(storagePath string) string {
+	if b.prefix == "" {
+		return storagePath
+	}
+	return path.Join(b.prefix, storagePath)
+}
+
+// objectURL returns the path-style URL of a key
+func (b *S3Backend) objectURL(key string) string {
+	return fmt.Sprintf("%s/%s/%s", b.endpoint, b.bucket, awsEscape(key, false))
+}
+
+func (b *S3Backend) putObject(storagePath string, data []byte, contentType string) error {
+	req, err := http.NewRequest(http.MethodPut, b.objectURL(b.objectKey(storagePath)), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	_, err = b.do(req, data)
+	return err
+}
+
+func (b *S3Backend) getObject(storagePath string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, b.objectURL(b.objectKey(storagePath)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return b.do(req, nil)
+}
+
+// listBucketResult is the subset of the ListObjectsV2 response we use
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// listKeys returns all storage paths (without the configured prefix) below listPrefix
+func (b *S3Backend) listKeys(listPrefix string) ([]string, error) {
+	fullPrefix := b.objectKey(listPrefix)
+	if listPrefix == "" && b.prefix != "" {
+		fullPrefix = b.prefix + "/"
+	}
+
+	var keys []string
+	token := ""
+	for {
+		params := url.Values{}
+		params.Set("list-type", "2")
+		params.Set("prefix", fullPrefix)
+		if token != "" {
+			params.Set("continuation-token", token)
+		}
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s?%s", b.endpoint, b.bucket, params.Encode()), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		body, err := b.do(req, nil)
+		if err != nil {
+			return nil, fmt.Errorf("list failed: %w", err)
+		}
+
+		var result listBucketResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse list response: %w", err)
+		}
+		for _, c := range result.Contents {
+			key := c.Key
+			if b.prefix != "" {
+				key = strings.TrimPrefix(key, b.prefix+"/")
+			}
+			keys = append(keys, key)
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do signs and sends a request, returning the response body
+func (b *S3Backend) do(req *http.Request, payload []byte) ([]byte, error) {
+	signV4(req, payload, b.creds, b.region, b.now().UTC())
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%s %s failed with status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// signV4 adds AWS Signature Version 4 headers for the s3 service. Every
+// header already set on the request is signed, together with host,
+// x-amz-date and x-amz-content-sha256.
+func signV4(req *http.Request, payload []byte, creds *S3Credentials, region string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	sum := sha256.Sum256(payload)
+	payloadHash := hex.EncodeToString(sum[:])
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	// Canonical headers
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// Canonical query string
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var queryParts []string
+	for _, k := range keys {
+		values := query[k]
+		sort.Strings(values)
+		for _, v := range values {
+			queryParts = append(queryParts, awsEscape(k, true)+"="+awsEscape(v, true))
+		}
+	}
+
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		strings.Join(queryParts, "&"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// awsEscape percent-encodes everything except unreserved characters, as
+// required by SigV4. Slashes are kept when encodeSlash is false.
+func awsEscape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// SupabaseBackend stores sessions in Supabase Storage with metadata in the
+// sessions table.
+type SupabaseBackend struct {
+	accessToken string
+	storage     *StorageClient
+	metadata    *MetadataClient
+}
+
+// NewSupabaseBackend creates a Supabase backend authenticated with accessToken
+func NewSupabaseBackend(accessToken string) *SupabaseBackend {
+	return &SupabaseBackend{
+		accessToken: accessToken,
+		storage:     NewStorageClient(),
+		metadata:    NewMetadataClient(),
+	}
+}
+
+// Name returns the backend name
+func (b *SupabaseBackend) Name() string {
+	return metadata.SessionBackendSupabase
+}
+
+// Store uploads content to Storage, then creates the metadata row
+func (b *SupabaseBackend) Store(input *CreateSessionInput, data []byte) (*SessionMetadata, error) {
+	if _, err := b.storage.Upload(b.accessToken, input.StoragePath, data); err != nil {
+		return nil, fmt.Errorf("storage upload failed: %w", err)
+	}
+	meta, err := b.metadata.Create(b.accessToken, input)
+	if err != nil {
+		return nil, fmt.Errorf("metadata creation failed: %w", err)
+	}
+	return meta, nil
+}
+
+// Query queries the sessions table
+func (b *SupabaseBackend) Query(opts *QueryOptions) ([]SessionMetadata, error) {
+	return b.metadata.Query(b.accessToken, opts)
+}
+
+// GetByID retrieves a session by its ID
+func (b *SupabaseBackend) GetByID(sessionID string) (*SessionMetadata, error) {
+	return b.metadata.GetByID(b.accessToken, sessionID)
+}
+
+// Download downloads session content from Storage
+func (b *SupabaseBackend) Download(storagePath string) ([]byte, error) {
+	return b.storage.Download(b.accessToken, storagePath)
+}
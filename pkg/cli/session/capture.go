@@ -216,8 +216,9 @@ func Capture(input *HookInput) *CaptureResult {
 	result := &CaptureResult{Captured: false}
 
 	// Debug: log capture flow
-	debugLog := filepath.Join(os.TempDir(), "sl-capture-debug.log")
-	debugF, _ := os.OpenFile(debugLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	debugLog := CaptureDebugLogPath()
+	RotateLog(debugLog, MaxLogSize)
+	debugF, _ := openPrivateLog(debugLog)
 	debugWrite := func(msg string) {
 		if debugF != nil {
 			fmt.Fprintf(debugF, "[%s] %s\n", time.Now().Format(time.RFC3339), msg)
@@ -229,30 +230,54 @@ func Capture(input *HookInput) *CaptureResult {
 		}
 	}()
 
-	debugWrite(fmt.Sprintf("Capture called, tool_input=%q, cwd=%q", input.ToolInput.Command(), input.Cwd))
+	// Commands and tool output may hold secrets: only their shape is logged
+	debugWrite(fmt.Sprintf("Capture called, agent=%q, tool=%q, cwd=%q", input.Agent, input.ToolName, input.Cwd))
 
-	// Check if this is a git commit
-	if !IsGitCommit(input.ToolInput.Command()) {
-		debugWrite("skip: not a git commit")
-		return result // Not a commit, nothing to capture
+	adapter, err := GetTranscriptAdapter(input.Agent)
+	if err != nil {
+		result.Error = err
+		return result
 	}
 
-	// Verify the tool succeeded
-	if !input.ToolSuccess() {
-		debugWrite("skip: tool not successful")
-		return result // Commit failed, nothing to capture
+	// State is keyed by agent session ID, or by transcript path for hooks
+	// that don't report one
+	stateKey := input.SessionID
+	if stateKey == "" {
+		stateKey = input.TranscriptPath
+	}
+
+	if input.TurnComplete {
+		// Agents without tool hooks only report finished turns; capture when
+		// the turn moved HEAD
+		if !headMovedSinceLastTurn(input.Cwd, stateKey) {
+			debugWrite("skip: HEAD unchanged since last turn")
+			return result
+		}
+	} else {
+		// Check if this is a git commit
+		if !IsGitCommit(input.ToolInput.Command()) {
+			debugWrite("skip: not a git commit")
+			return result // Not a commit, nothing to capture
+		}
+
+		// Verify the tool succeeded
+		if !input.ToolSuccess() {
+			debugWrite("skip: tool not successful")
+			return result // Commit failed, nothing to capture
+		}
 	}
 
-	debugWrite("git commit detected, checking auth...")
+	// === Backend and identity first: skip silently if not configured ===
+	storageCfg := LoadStorageConfig(input.Cwd)
+	debugWrite(fmt.Sprintf("git commit detected, backend=%s, checking identity...", storageCfg.Backend))
 
-	// === Auth check first: skip silently if not authenticated ===
-	creds, err := auth.LoadCredentials()
-	if err != nil || creds == nil {
-		debugWrite(fmt.Sprintf("skip: no creds (err=%v, creds=%v)", err, creds))
+	identity, err := ResolveIdentity(input.Cwd, storageCfg.Backend)
+	if err != nil {
+		debugWrite(fmt.Sprintf("skip: no identity (err=%v)", err))
 		return result // No credentials, silently skip
 	}
 
-	debugWrite(fmt.Sprintf("auth OK, user=%s", creds.UserEmail))
+	debugWrite(fmt.Sprintf("identity OK, user=%s", identity.Email))
 
 	// === Core metadata (always available from git) ===
 
@@ -274,8 +299,8 @@ func Capture(input *HookInput) *CaptureResult {
 	}
 	debugWrite(fmt.Sprintf("branch=%s", branch))
 
-	// Get project ID (with fallback to git remote lookup and auto-persist)
-	projectID, err := GetProjectIDWithFallback(input.Cwd)
+	// Get project ID (Supabase falls back to git remote lookup and auto-persists)
+	projectID, err := ResolveProjectID(input.Cwd, storageCfg.Backend)
 	if err != nil {
 		debugWrite(fmt.Sprintf("skip: no project ID (err=%v)", err))
 		return result // No project ID, silently skip
@@ -284,15 +309,18 @@ func Capture(input *HookInput) *CaptureResult {
 	// === Transcript (nice-to-have, graceful degradation) ===
 
 	var messages []Message
-	var newOffset int64
+	var lastOffset, newOffset int64
 
-	// Try to get transcript path - provided or search by session_id
+	// Try to get transcript path - provided or located by the agent adapter
 	transcriptPath := input.TranscriptPath
-	if transcriptPath == "" && input.SessionID != "" {
-		if found, err := findTranscriptBySessionID(input.SessionID); err == nil {
+	if transcriptPath == "" {
+		if found, err := adapter.FindTranscript(input.SessionID, input.Cwd); err == nil {
 			transcriptPath = found
 		}
 	}
+	if stateKey == "" {
+		stateKey = transcriptPath
+	}
 
 	// If we have a transcript, compute delta
 	if transcriptPath != "" {
@@ -300,13 +328,12 @@ func Capture(input *HookInput) *CaptureResult {
 			input.TranscriptPath = transcriptPath
 
 			// Get last offset for this session (0 if first capture)
-			var lastOffset int64
-			if offsetInfo, err := GetSessionOffset(input.SessionID); err == nil {
+			if offsetInfo, err := GetSessionOffset(stateKey); err == nil {
 				lastOffset = offsetInfo.LastOffset
 			}
 
 			// Compute delta from last offset (or full transcript if first capture)
-			msgs, offset, err := ComputeDelta(transcriptPath, lastOffset)
+			msgs, offset, err := adapter.ReadDelta(transcriptPath, lastOffset)
 			if err == nil {
 				messages = msgs
 				newOffset = offset
@@ -317,16 +344,42 @@ func Capture(input *HookInput) *CaptureResult {
 	// Even without transcript messages, we still capture metadata
 	// A session record with commit linkage is valuable
 
+	// Redact secrets before anything is compressed, queued or uploaded. A
+	// redactor that can't be built means nothing leaves the machine.
+	redactor, err := LoadRedactor(input.Cwd)
+	if err != nil {
+		debugWrite(fmt.Sprintf("error: redaction unavailable: %v", err))
+		result.Error = err
+		return result
+	}
+	redactions := redactor.RedactMessages(messages)
+	if total := redactions.Total(); total > 0 {
+		debugWrite(fmt.Sprintf("redacted %d secrets (%s)", total, redactions))
+	}
+	// Truncate only after redaction, so no secret is cut short of its pattern
+	truncateToolOutputs(messages)
+
+	// Link the session to an issue via the commit message or the spec's
+	// in-progress work
+	taskID := ResolveTaskID(input.Cwd, commitHash, branch)
+	var taskIDPtr *string
+	if taskID != "" {
+		taskIDPtr = &taskID
+		debugWrite(fmt.Sprintf("linked to issue %s", taskID))
+	}
+
 	// Build session content
 	sessionID := uuid.New().String()
 	content := &SessionContent{
-		Version:       "1.0",
+		Version:       SessionContentVersion,
 		SessionID:     sessionID,
 		FeatureBranch: branch,
 		CommitHash:    commitHash,
-		TaskID:        "",
-		Author:        creds.UserEmail,
+		TaskID:        taskID,
+		Author:        identity.Email,
+		Agent:         adapter.Name(),
 		CapturedAt:    time.Now(),
+		Redactions:    redactionsOrNil(redactions),
 		Messages:      messages,
 	}
 
@@ -353,69 +406,62 @@ func Capture(input *HookInput) *CaptureResult {
 	result.MessageCount = len(messages)
 	result.SizeBytes = int64(len(compressed))
 	result.RawSizeBytes = rawSize
+	result.Redactions = redactions
+	result.TaskID = taskID
 	result.StoragePath = BuildStoragePath(projectID, branch, commitHash)
 
-	// Try to get valid access token
-	accessToken, err := auth.GetValidAccessToken()
+	// Open the configured backend
+	backend, err := OpenBackendWithConfig(input.Cwd, storageCfg)
 	if err != nil {
 		// Queue for later upload + log error
 		LogCaptureError(CaptureErrorEntry{
-			UserID:        creds.UserID,
+			UserID:        identity.UserID,
 			ProjectID:     projectID,
 			SessionID:     sessionID,
-			ErrorMessage:  fmt.Sprintf("token refresh failed: %v", err),
+			ErrorMessage:  fmt.Sprintf("%s backend unavailable: %v", storageCfg.Backend, err),
 			FeatureBranch: branch,
 			CommitHash:    commitHash,
 		})
-		return queueSession(result, compressed, projectID, branch, &commitHash, nil, creds.UserID)
+		return queueSession(result, compressed, input.Cwd, storageCfg.Backend, projectID, branch, &commitHash, taskIDPtr, identity.UserID)
 	}
 
-	// Upload to storage
-	storage := NewStorageClient()
-	_, err = storage.Upload(accessToken, result.StoragePath, compressed)
-	if err != nil {
-		// Queue for later upload + log error
-		LogCaptureError(CaptureErrorEntry{
-			UserID:        creds.UserID,
-			ProjectID:     projectID,
-			SessionID:     sessionID,
-			ErrorMessage:  fmt.Sprintf("storage upload failed: %v", err),
-			FeatureBranch: branch,
-			CommitHash:    commitHash,
-		})
-		return queueSession(result, compressed, projectID, branch, &commitHash, nil, creds.UserID)
-	}
-
-	// Create metadata
-	metadata := NewMetadataClient()
-	_, err = metadata.Create(accessToken, &CreateSessionInput{
+	// Upload content and create metadata
+	stored, err := backend.Store(&CreateSessionInput{
 		ProjectID:     projectID,
 		FeatureBranch: branch,
 		CommitHash:    &commitHash,
-		TaskID:        nil,
-		AuthorID:      creds.UserID,
+		TaskID:        taskIDPtr,
+		AuthorID:      identity.UserID,
 		StoragePath:   result.StoragePath,
 		Status:        StatusComplete,
 		SizeBytes:     result.SizeBytes,
 		RawSizeBytes:  result.RawSizeBytes,
 		MessageCount:  result.MessageCount,
-	})
+		Redactions:    redactionsOrNil(redactions),
+	}, compressed)
 	if err != nil {
-		// Storage upload succeeded but metadata failed - still queue + log error
+		// Queue for later upload + log error
 		LogCaptureError(CaptureErrorEntry{
-			UserID:        creds.UserID,
+			UserID:        identity.UserID,
 			ProjectID:     projectID,
 			SessionID:     sessionID,
-			ErrorMessage:  fmt.Sprintf("metadata creation failed: %v", err),
+			ErrorMessage:  err.Error(),
 			FeatureBranch: branch,
 			CommitHash:    commitHash,
 		})
-		return queueSession(result, compressed, projectID, branch, &commitHash, nil, creds.UserID)
+		return queueSession(result, compressed, input.Cwd, storageCfg.Backend, projectID, branch, &commitHash, taskIDPtr, identity.UserID)
 	}
 
-	// Update offset tracking (only if we have transcript data)
-	if input.TranscriptPath != "" && newOffset > 0 {
-		if err := UpdateSessionOffset(input.SessionID, newOffset, commitHash, input.TranscriptPath); err != nil {
+	// Keep a copy for offline search
+	CacheSession(stored, compressed)
+
+	// Update offset tracking (only if we have transcript data). Turn-based
+	// agents always record the commit so the next turn compares against it.
+	if newOffset == 0 && input.TurnComplete {
+		newOffset = lastOffset
+	}
+	if (input.TranscriptPath != "" && newOffset > 0) || input.TurnComplete {
+		if err := UpdateSessionOffset(stateKey, newOffset, commitHash, input.TranscriptPath); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to update session offset: %v\n", err)
 		}
 	}
@@ -424,12 +470,37 @@ func Capture(input *HookInput) *CaptureResult {
 	return result
 }
 
+// headMovedSinceLastTurn reports whether HEAD differs from the commit recorded
+// for the session. The first turn of a session only records a baseline: a
+// commit made in that turn is missed, which beats capturing every session
+// that happens to start on an existing commit.
+func headMovedSinceLastTurn(workdir, stateKey string) bool {
+	head, err := GetCurrentCommitHash(workdir)
+	if err != nil || stateKey == "" {
+		return false
+	}
+
+	state, err := LoadSessionState()
+	if err != nil {
+		return false
+	}
+	info, exists := state.Sessions[stateKey]
+	if !exists {
+		state.Sessions[stateKey] = &SessionOffsetInfo{LastCommit: head}
+		_ = SaveSessionState(state)
+		return false
+	}
+	return info.LastCommit != head
+}
+
 // queueSession queues a session for later upload
-func queueSession(result *CaptureResult, compressed []byte, projectID, branch string, commitHash, taskID *string, authorID string) *CaptureResult {
+func queueSession(result *CaptureResult, compressed []byte, workdir, backend, projectID, branch string, commitHash, taskID *string, authorID string) *CaptureResult {
 	queue := NewQueue()
 	entry := &QueueEntry{
 		SessionID:     result.SessionID,
 		ProjectID:     projectID,
+		Backend:       backend,
+		Workdir:       workdir,
 		FeatureBranch: branch,
 		CommitHash:    commitHash,
 		TaskID:        taskID,
@@ -437,6 +508,9 @@ func queueSession(result *CaptureResult, compressed []byte, projectID, branch st
 		Status:        StatusComplete,
 		CreatedAt:     time.Now(),
 		RetryCount:    0,
+		MessageCount:  result.MessageCount,
+		RawSizeBytes:  result.RawSizeBytes,
+		Redactions:    redactionsOrNil(result.Redactions),
 	}
 
 	if err := queue.Enqueue(entry, compressed); err != nil {
@@ -448,11 +522,39 @@ func queueSession(result *CaptureResult, compressed []byte, projectID, branch st
 	return result
 }
 
-// CaptureFromStdin reads hook input from stdin and captures the session
-func CaptureFromStdin() *CaptureResult {
+// redactionsOrNil drops empty counts so metadata only records sessions that
+// had something redacted
+func redactionsOrNil(counts RedactionCounts) map[string]int {
+	if counts.Total() == 0 {
+		return nil
+	}
+	return counts
+}
+
+// CaptureFromPayload parses an agent's hook payload and captures the session
+func CaptureFromPayload(agent string, data []byte) *CaptureResult {
+	adapter, err := GetTranscriptAdapter(agent)
+	if err != nil {
+		return &CaptureResult{Error: err}
+	}
+
+	input, err := adapter.ParseHookInput(data)
+	if err != nil {
+		return &CaptureResult{Error: err}
+	}
+	input.Agent = adapter.Name()
+	if input.Cwd == "" {
+		input.Cwd, _ = os.Getwd()
+	}
+
+	return Capture(input)
+}
+
+// CaptureFromStdin reads an agent's hook input from stdin and captures the session
+func CaptureFromStdin(agent string) *CaptureResult {
 	// Debug: log that capture was invoked
-	debugLog := filepath.Join(os.TempDir(), "sl-capture-debug.log")
-	if f, err := os.OpenFile(debugLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
+	debugLog := CaptureDebugLogPath()
+	if f, err := openPrivateLog(debugLog); err == nil {
 		fmt.Fprintf(f, "[%s] CaptureFromStdin invoked\n", time.Now().Format(time.RFC3339))
 		f.Close()
 	}
@@ -493,20 +595,7 @@ func CaptureFromStdin() *CaptureResult {
 			return &CaptureResult{Error: fmt.Errorf("no data received from stdin")}
 		}
 
-		// Debug: log raw input
-		rawLog := filepath.Join(os.TempDir(), "sl-capture-raw.log")
-		if f, err := os.OpenFile(rawLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
-			fmt.Fprintf(f, "[%s] raw_input=%s\n", time.Now().Format(time.RFC3339), string(result.data))
-			f.Close()
-		}
-
-		// Parse hook input
-		input, err := ParseHookInput(result.data)
-		if err != nil {
-			return &CaptureResult{Error: err}
-		}
-
-		return Capture(input)
+		return CaptureFromPayload(agent, result.data)
 
 	case <-time.After(5 * time.Second):
 		return &CaptureResult{Error: fmt.Errorf("timeout waiting for stdin input (waited 5 seconds)")}
@@ -526,21 +615,32 @@ func CaptureTestMode() *CaptureResult {
 		return &CaptureResult{Error: fmt.Errorf("not in a git repository: %w", err)}
 	}
 
-	// Check if project has a project ID
-	projectID, err := GetProjectID(cwd)
-	if err != nil {
-		return &CaptureResult{Error: fmt.Errorf("project setup incomplete: %w\n\nTo use session capture, ensure specledger.yaml has a project.id field", err)}
+	storageCfg := LoadStorageConfig(cwd)
+
+	// Check if project has a project ID (only Supabase requires a registered one)
+	var projectID string
+	if storageCfg.Backend == metadata.SessionBackendSupabase {
+		projectID, err = GetProjectID(cwd)
+		if err != nil {
+			return &CaptureResult{Error: fmt.Errorf("project setup incomplete: %w\n\nTo use session capture, ensure specledger.yaml has a project.id field", err)}
+		}
+	} else {
+		projectID, _ = ResolveProjectID(cwd, storageCfg.Backend)
 	}
 
 	fmt.Printf("✓ Git repository detected\n")
 	fmt.Printf("✓ Project ID found: %s\n", projectID)
+	fmt.Printf("✓ Storage backend: %s\n", storageCfg.Backend)
 
 	// Check for authentication
-	creds, err := auth.LoadCredentials()
-	if err != nil || creds == nil {
-		return &CaptureResult{Error: fmt.Errorf("not authenticated. Run: sl auth login")}
+	identity, err := ResolveIdentity(cwd, storageCfg.Backend)
+	if err != nil {
+		if storageCfg.Backend == metadata.SessionBackendSupabase {
+			return &CaptureResult{Error: fmt.Errorf("not authenticated. Run: sl auth login")}
+		}
+		return &CaptureResult{Error: fmt.Errorf("no author identity: %w (set git config user.email)", err)}
 	}
-	fmt.Printf("✓ Authenticated as: %s\n", creds.UserEmail)
+	fmt.Printf("✓ Capturing as: %s\n", identity.Email)
 
 	// Check for Claude Code transcript in projects directory
 	homeDir, err := os.UserHomeDir()
@@ -316,3 +316,24 @@ func TestLogCaptureErrorAppends(t *testing.T) {
 		t.Errorf("Expected 2 JSONL lines, got %d", len(lines))
 	}
 }
+
+func TestOpenPrivateLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := openPrivateLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("log mode = %o, want 600", perm)
+	}
+}
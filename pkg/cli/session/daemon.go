@@ -0,0 +1,279 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+const (
+	// DaemonStatusFile records the upload daemon's state in ~/.specledger
+	DaemonStatusFile = "daemon.json"
+	// DefaultDaemonInterval is how often the daemon checks the queue
+	DefaultDaemonInterval = time.Minute
+	// maxOfflineDelay caps the delay between connectivity checks while offline
+	maxOfflineDelay = 15 * time.Minute
+)
+
+// DaemonStatus is the state of the upload daemon, shown by
+// 'sl session sync --status'
+type DaemonStatus struct {
+	PID       int        `json:"pid"`
+	Workdir   string     `json:"workdir"`
+	StartedAt time.Time  `json:"started_at"`
+	LastRun   *time.Time `json:"last_run,omitempty"`
+	NextRun   *time.Time `json:"next_run,omitempty"`
+	Offline   bool       `json:"offline"`
+	Uploaded  int        `json:"uploaded"` // sessions uploaded since the daemon started
+	LastError string     `json:"last_error,omitempty"`
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+}
+
+// Running reports whether the daemon process is still alive
+func (s *DaemonStatus) Running() bool {
+	if s == nil || s.PID <= 0 || s.StoppedAt != nil {
+		return false
+	}
+	process, err := os.FindProcess(s.PID)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// DaemonOptions configures RunDaemon
+type DaemonOptions struct {
+	Workdir  string
+	Interval time.Duration
+	// Once drains the queue a single time and returns
+	Once bool
+	// Logf receives progress messages
+	Logf func(format string, args ...interface{})
+}
+
+func daemonStatusPath() string {
+	return filepath.Join(GetBaseDir(), DaemonStatusFile)
+}
+
+// LoadDaemonStatus returns the last recorded daemon state, or nil if the
+// daemon never ran
+func LoadDaemonStatus() (*DaemonStatus, error) {
+	data, err := os.ReadFile(daemonStatusPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read daemon status: %w", err)
+	}
+	var status DaemonStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse daemon status: %w", err)
+	}
+	return &status, nil
+}
+
+func saveDaemonStatus(status *DaemonStatus) error {
+	if err := ensureDir(GetBaseDir()); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(daemonStatusPath(), data, 0600)
+}
+
+// RunDaemon drains the upload queue until ctx is cancelled. Each pass uploads
+// the sessions whose retry backoff has elapsed, for every project with queued
+// sessions; while the backend is unreachable it checks again with exponential
+// backoff instead of burning retries. Only one daemon runs at a time.
+func RunDaemon(ctx context.Context, opts DaemonOptions) error {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultDaemonInterval
+	}
+	logf := opts.Logf
+	if logf == nil {
+		logf = func(string, ...interface{}) {}
+	}
+
+	if !opts.Once {
+		if existing, _ := LoadDaemonStatus(); existing.Running() && existing.PID != os.Getpid() {
+			return fmt.Errorf("session daemon already running (pid %d, started %s)", existing.PID, existing.StartedAt.Format("2006-01-02 15:04"))
+		}
+	}
+
+	// A single pass leaves the status of a running daemon alone
+	status := &DaemonStatus{PID: os.Getpid(), Workdir: opts.Workdir, StartedAt: time.Now()}
+	if !opts.Once {
+		defer func() {
+			stopped := time.Now()
+			status.StoppedAt = &stopped
+			status.NextRun = nil
+			_ = saveDaemonStatus(status)
+		}()
+	}
+
+	queue := NewQueue()
+	offlineDelay := opts.Interval
+	for {
+		result, uploadedIn, err := drainOnce(queue, opts.Workdir)
+		now := time.Now()
+		status.LastRun = &now
+		status.LastError = ""
+		if result != nil {
+			status.Uploaded += result.Uploaded
+		}
+		for _, dir := range uploadedIn {
+			pruneAfterUpload(dir, logf)
+		}
+
+		wait := opts.Interval
+		switch {
+		case err != nil && IsNetworkError(err), result != nil && result.Offline:
+			if err == nil && len(result.Errors) > 0 {
+				err = result.Errors[len(result.Errors)-1]
+			}
+			status.Offline = true
+			if err != nil {
+				status.LastError = err.Error()
+			}
+			wait = withJitter(offlineDelay)
+			logf("offline (%s); checking again in %s", status.LastError, wait.Round(time.Second))
+			offlineDelay = min(offlineDelay*2, maxOfflineDelay)
+		case err != nil:
+			status.Offline = false
+			status.LastError = err.Error()
+			logf("sync failed: %v", err)
+		default:
+			if status.Offline {
+				logf("back online")
+			}
+			status.Offline = false
+			offlineDelay = opts.Interval
+			if result.Uploaded > 0 || result.Failed > 0 {
+				logf("uploaded %d, failed %d, waiting %d, gave up %d", result.Uploaded, result.Failed, result.Deferred, result.Skipped)
+			}
+			for _, e := range result.Errors {
+				logf("sync failed: %v", e)
+			}
+			if len(result.Errors) > 0 {
+				status.LastError = result.Errors[len(result.Errors)-1].Error()
+			}
+			if result.NextRetry != nil {
+				if untilRetry := time.Until(*result.NextRetry); untilRetry < wait {
+					wait = max(untilRetry, time.Second)
+				}
+			}
+		}
+
+		if opts.Once {
+			return err
+		}
+
+		next := time.Now().Add(wait)
+		status.NextRun = &next
+		_ = saveDaemonStatus(status)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// drainOnce uploads what the queue allows for every project with queued
+// sessions, each to the backend configured in the directory it was captured
+// in. Sessions queued before directories were recorded use workdir. It
+// returns the directories whose sessions were uploaded. Opening a backend can
+// itself fail for lack of network (e.g. refreshing the auth token), which
+// counts as offline.
+func drainOnce(queue *Queue, workdir string) (*SyncResult, []string, error) {
+	entries, err := queue.ListEntries()
+	if err != nil || len(entries) == 0 {
+		return &SyncResult{}, nil, err
+	}
+
+	total := &SyncResult{}
+	var uploadedIn []string
+	drained := make(map[string]bool) // project and backend pairs already processed
+	for _, dir := range queueWorkdirs(entries, workdir) {
+		cfg := LoadStorageConfig(dir)
+		projectID, err := ResolveProjectID(dir, cfg.Backend)
+		if err == nil {
+			key := projectID + "/" + cfg.Backend
+			if drained[key] {
+				continue
+			}
+			drained[key] = true
+			var backend Backend
+			if backend, err = OpenBackendWithConfig(dir, cfg); err == nil {
+				result := queue.ProcessQueue(backend, projectID)
+				total.merge(result)
+				if result.Uploaded > 0 {
+					uploadedIn = append(uploadedIn, dir)
+				}
+				continue
+			}
+		}
+		if IsNetworkError(err) {
+			total.Offline = true
+		}
+		total.Errors = append(total.Errors, fmt.Errorf("%s: %w", dir, err))
+	}
+	return total, uploadedIn, nil
+}
+
+// queueWorkdirs returns the distinct directories the queued sessions were
+// captured in, in queue order
+func queueWorkdirs(entries []*QueueEntry, fallback string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, e := range entries {
+		dir := e.Workdir
+		if dir == "" {
+			dir = fallback
+		}
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// pruneAfterUpload applies the project's retention policy to the local cache
+func pruneAfterUpload(workdir string, logf func(string, ...interface{})) {
+	policy, err := LoadRetentionPolicy(workdir)
+	if err != nil {
+		logf("retention not applied: %v", err)
+		return
+	}
+	projectID, err := ResolveProjectID(workdir, LoadStorageConfig(workdir).Backend)
+	if err != nil {
+		logf("retention not applied: %v", err)
+		return
+	}
+	report, err := Prune(projectID, policy, false)
+	if err != nil {
+		logf("prune failed: %v", err)
+		return
+	}
+	if len(report.Pruned) > 0 {
+		logf("pruned %d cached session(s), freed %d bytes", len(report.Pruned), report.FreedBytes)
+	}
+}
+
+// withJitter spreads a delay by up to ±20%
+func withJitter(d time.Duration) time.Duration {
+	spread := int64(d) / 5
+	if spread <= 0 {
+		return d
+	}
+	return d - time.Duration(spread) + time.Duration(rand.Int63n(2*spread+1))
+}
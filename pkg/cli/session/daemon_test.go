@@ -0,0 +1,74 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// localProject writes a project at a new directory whose sessions are stored
+// with the local backend
+func localProject(t *testing.T, name string) string {
+	t.Helper()
+	dir := t.TempDir()
+	meta := metadata.NewProjectMetadata(name, name[:3], "specledger", "1.0.0", nil, "")
+	meta.Session = &metadata.SessionConfig{Storage: metadata.SessionStorageConfig{Backend: metadata.SessionBackendLocal}}
+	if err := metadata.SaveToProject(meta, dir); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestDrainOnceAllProjects(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	alpha := localProject(t, "alpha")
+	beta := localProject(t, "beta")
+
+	q := NewQueue()
+	data, err := Compress([]byte(`{"version":"2.0"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, e := range []*QueueEntry{
+		{ProjectID: "alpha", Workdir: alpha, CommitHash: strPtr("aaaaaaa")},
+		{ProjectID: "beta", Workdir: beta, CommitHash: strPtr("bbbbbbb")},
+		{ProjectID: "alpha", Workdir: alpha, CommitHash: strPtr("ccccccc")},
+		{ProjectID: "alpha", CommitHash: strPtr("ddddddd")}, // queued before workdirs were recorded
+	} {
+		e.SessionID = "s-" + (*e.CommitHash)
+		e.Backend = metadata.SessionBackendLocal
+		e.FeatureBranch = "main"
+		e.Status = StatusComplete
+		e.CreatedAt = time.Now().Add(time.Duration(i) * time.Second)
+		if err := q.Enqueue(e, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Run from beta: every project is drained to its own backend
+	result, uploadedIn, err := drainOnce(q, beta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Uploaded != 4 || len(result.Errors) != 0 {
+		t.Fatalf("expected all sessions uploaded, got %+v", result)
+	}
+	if len(uploadedIn) != 2 || uploadedIn[0] != alpha || uploadedIn[1] != beta {
+		t.Errorf("uploadedIn = %v", uploadedIn)
+	}
+	if n, _ := q.Count(); n != 0 {
+		t.Errorf("expected empty queue, got %d", n)
+	}
+
+	for dir, want := range map[string]int{alpha: 3, beta: 1} {
+		sessions, err := NewLocalBackend(LocalBackendDir(dir, "")).Query(&QueryOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sessions) != want {
+			t.Errorf("%s: expected %d sessions, got %d", filepath.Base(dir), want, len(sessions))
+		}
+	}
+}
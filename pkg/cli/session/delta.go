@@ -68,6 +68,21 @@ func SaveSessionState(state *SessionState) error {
 
 // ComputeDelta reads new lines from a transcript file since the last offset
 func ComputeDelta(transcriptPath string, lastOffset int64) ([]Message, int64, error) {
+	return readJSONLDelta(transcriptPath, lastOffset, func(line []byte) *Message {
+		var tl TranscriptLine
+		if err := json.Unmarshal(line, &tl); err != nil {
+			// Skip malformed lines
+			return nil
+		}
+		return transcriptLineToMessage(tl)
+	})
+}
+
+// readJSONLDelta reads a JSONL transcript from lastOffset, converting each
+// line with parse (which returns nil for lines to skip), and returns the
+// byte offset after the last line read. Tool results are attached to the
+// calls they answer.
+func readJSONLDelta(transcriptPath string, lastOffset int64, parse func(line []byte) *Message) ([]Message, int64, error) {
 	file, err := os.Open(transcriptPath)
 	if err != nil {
 		return nil, lastOffset, fmt.Errorf("failed to open transcript: %w", err)
@@ -84,7 +99,7 @@ func ComputeDelta(transcriptPath string, lastOffset int64) ([]Message, int64, er
 
 	var messages []Message
 	scanner := bufio.NewScanner(file)
-	// Increase buffer size for long lines (Claude transcripts can have very long lines)
+	// Increase buffer size for long lines (transcripts can have very long lines)
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, 10*1024*1024) // max 10MB per line
 
@@ -94,15 +109,8 @@ func ComputeDelta(transcriptPath string, lastOffset int64) ([]Message, int64, er
 			continue
 		}
 
-		var tl TranscriptLine
-		if err := json.Unmarshal(line, &tl); err != nil {
-			// Skip malformed lines
-			continue
-		}
-
 		// Convert transcript line to message
-		msg := transcriptLineToMessage(tl)
-		if msg != nil {
+		if msg := parse(line); msg != nil {
 			messages = append(messages, *msg)
 		}
 	}
@@ -117,7 +125,7 @@ func ComputeDelta(transcriptPath string, lastOffset int64) ([]Message, int64, er
 		return nil, lastOffset, fmt.Errorf("failed to get current offset: %w", err)
 	}
 
-	return messages, newOffset, nil
+	return attachToolResults(messages), newOffset, nil
 }
 
 // transcriptLineToMessage converts a transcript line to a message
@@ -142,14 +150,17 @@ func transcriptLineToMessage(tl TranscriptLine) *Message {
 
 	// Extract content - check nested message first, then direct content
 	var content string
+	var calls []ToolCall
+	var results []toolResult
 	if tl.Message != nil && tl.Message.Content != nil {
 		content = extractContent(tl.Message.Content)
+		calls, results = extractToolBlocks(tl.Message.Content)
 	} else if tl.Content != "" {
 		content = tl.Content
 	}
 
-	// Skip empty content
-	if content == "" {
+	// Skip lines with nothing to keep
+	if content == "" && len(calls) == 0 && len(results) == 0 {
 		return nil
 	}
 
@@ -159,9 +170,11 @@ func transcriptLineToMessage(tl TranscriptLine) *Message {
 	}
 
 	return &Message{
-		Role:      role,
-		Content:   content,
-		Timestamp: timestamp,
+		Role:        role,
+		Content:     content,
+		Timestamp:   timestamp,
+		ToolCalls:   calls,
+		toolResults: results,
 	}
 }
 
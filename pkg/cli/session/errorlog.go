@@ -62,7 +62,8 @@ func logToLocalFile(entry CaptureErrorEntry) {
 	}
 
 	// Append with newline (JSONL format)
-	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	RotateLog(logPath, MaxLogSize)
+	f, err := openPrivateLog(logPath)
 	if err != nil {
 		return
 	}
@@ -71,6 +72,17 @@ func logToLocalFile(entry CaptureErrorEntry) {
 	_, _ = f.Write(append(data, '\n'))
 }
 
+// openPrivateLog opens a capture log for appending, readable only by the
+// user. Logs created world-readable by older versions are restricted too.
+func openPrivateLog(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	_ = f.Chmod(0600)
+	return f, nil
+}
+
 // logToSentry sends the error to Sentry with structured context tags.
 // Best-effort: if it fails, we already have the local log.
 func logToSentry(entry CaptureErrorEntry) {
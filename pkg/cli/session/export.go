@@ -0,0 +1,288 @@
+package session
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// Export formats
+const (
+	ExportMarkdown = "md"
+	ExportHTML     = "html"
+)
+
+// ExportFormats lists the formats supported by Export
+var ExportFormats = []string{ExportMarkdown, ExportHTML}
+
+// ExportOptions controls how a session is exported
+type ExportOptions struct {
+	// RepoURL is the web URL of the repository (e.g.
+	// https://github.com/owner/repo); when set, the commit and branch in the
+	// header link to it
+	RepoURL string
+	// NoTools omits tool calls
+	NoTools bool
+}
+
+// Export renders a session as a readable transcript in the given format
+func Export(content *SessionContent, format string, opts ExportOptions) (string, error) {
+	switch format {
+	case ExportMarkdown:
+		return exportMarkdown(content, opts), nil
+	case ExportHTML:
+		return exportHTML(content, opts)
+	default:
+		return "", fmt.Errorf("unsupported export format %q (supported: %s)", format, strings.Join(ExportFormats, ", "))
+	}
+}
+
+// exportHeader is a header row of an exported session
+type exportHeader struct {
+	Label string
+	Text  string
+	URL   string
+}
+
+func exportHeaders(content *SessionContent, opts ExportOptions) []exportHeader {
+	var headers []exportHeader
+	if content.FeatureBranch != "" {
+		h := exportHeader{Label: "Branch", Text: content.FeatureBranch}
+		if opts.RepoURL != "" {
+			h.URL = opts.RepoURL + "/tree/" + content.FeatureBranch
+		}
+		headers = append(headers, h)
+	}
+	if content.CommitHash != "" {
+		h := exportHeader{Label: "Commit", Text: shortHash(content.CommitHash)}
+		if opts.RepoURL != "" {
+			h.URL = opts.RepoURL + "/commit/" + content.CommitHash
+		}
+		headers = append(headers, h)
+	}
+	if content.TaskID != "" {
+		headers = append(headers, exportHeader{Label: "Task", Text: content.TaskID})
+	}
+	if content.Author != "" {
+		headers = append(headers, exportHeader{Label: "Author", Text: content.Author})
+	}
+	if content.Agent != "" {
+		headers = append(headers, exportHeader{Label: "Agent", Text: content.Agent})
+	}
+	headers = append(headers,
+		exportHeader{Label: "Captured", Text: content.CapturedAt.Format("2006-01-02 15:04:05 MST")},
+		exportHeader{Label: "Session", Text: content.SessionID},
+	)
+	return headers
+}
+
+// exportTitle is the title of an exported session
+func exportTitle(content *SessionContent) string {
+	switch {
+	case content.CommitHash != "":
+		return "Session for commit " + shortHash(content.CommitHash)
+	case content.TaskID != "":
+		return "Session for " + content.TaskID
+	default:
+		return "Session " + content.SessionID
+	}
+}
+
+func exportMarkdown(content *SessionContent, opts ExportOptions) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", exportTitle(content))
+	b.WriteString("| | |\n|---|---|\n")
+	for _, h := range exportHeaders(content, opts) {
+		text := strings.ReplaceAll(h.Text, "|", `\|`)
+		if h.URL != "" {
+			text = fmt.Sprintf("[%s](%s)", text, h.URL)
+		}
+		fmt.Fprintf(&b, "| **%s** | %s |\n", h.Label, text)
+	}
+
+	for _, msg := range content.Messages {
+		fmt.Fprintf(&b, "\n## %s · %s\n\n", roleTitle(msg.Role), msg.Timestamp.Format("15:04:05"))
+		if msg.Content != "" {
+			b.WriteString(strings.TrimRight(msg.Content, "\n"))
+			b.WriteString("\n")
+		}
+		if opts.NoTools {
+			continue
+		}
+		for _, call := range msg.ToolCalls {
+			summary := toolStatusMarker(call) + " " + FormatToolCall(call)
+			if call.Output == "" {
+				fmt.Fprintf(&b, "\n- `%s`\n", strings.ReplaceAll(summary, "`", "'"))
+				continue
+			}
+			fence := codeFence(call.Output)
+			fmt.Fprintf(&b, "\n<details>\n<summary>%s</summary>\n\n%s\n%s\n%s\n\n</details>\n",
+				template.HTMLEscapeString(summary), fence, strings.TrimRight(call.Output, "\n"), fence)
+		}
+	}
+	return b.String()
+}
+
+// backtickRun matches runs of backticks, to pick a fence that doesn't clash
+var backtickRun = regexp.MustCompile("`+")
+
+// codeFence returns a Markdown code fence longer than any backtick run in text
+func codeFence(text string) string {
+	longest := 2
+	for _, run := range backtickRun.FindAllString(text, -1) {
+		if len(run) > longest {
+			longest = len(run)
+		}
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+// toolStatusMarker returns a plain-text marker for a tool call's status
+func toolStatusMarker(call ToolCall) string {
+	switch call.Status {
+	case ToolStatusSuccess:
+		return "✓"
+	case ToolStatusError:
+		return "✗"
+	default:
+		return "•"
+	}
+}
+
+func roleTitle(role string) string {
+	if role == "" {
+		return "Unknown"
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+var exportHTMLTemplate = template.Must(template.New("session").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 900px; margin: 2em auto; padding: 0 1em; color: #1f2328; }
+table.meta td { padding: 2px 12px 2px 0; vertical-align: top; }
+.msg { border: 1px solid #d0d7de; border-radius: 6px; margin: 1em 0; padding: 0.5em 1em; }
+.msg.user { background: #f6f8fa; }
+.msg header { font-size: 0.85em; color: #59636e; margin-bottom: 0.5em; }
+.msg header .role { font-weight: 600; color: #1f2328; }
+.content { white-space: pre-wrap; }
+details.tool { margin: 0.4em 0; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: 0.85em; }
+details.tool pre { background: #f6f8fa; padding: 0.5em; overflow-x: auto; }
+.success { color: #1a7f37; } .error { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table class="meta">
+{{- range .Headers}}
+<tr><td><strong>{{.Label}}</strong></td><td>{{if .URL}}<a href="{{.URL}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}</td></tr>
+{{- end}}
+</table>
+{{- range .Messages}}
+<section class="msg {{.Role}}">
+<header><span class="role">{{.RoleTitle}}</span> · <time datetime="{{.Timestamp}}">{{.Time}}</time></header>
+{{- if .Content}}
+<div class="content">{{.Content}}</div>
+{{- end}}
+{{- range .ToolCalls}}
+<details class="tool"><summary><span class="{{.Status}}">{{.Marker}}</span> {{.Summary}}</summary>{{if .Output}}<pre>{{.Output}}</pre>{{end}}</details>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+type htmlMessage struct {
+	Role      string
+	RoleTitle string
+	Timestamp string
+	Time      string
+	Content   string
+	ToolCalls []htmlToolCall
+}
+
+type htmlToolCall struct {
+	Status  string
+	Marker  string
+	Summary string
+	Output  string
+}
+
+func exportHTML(content *SessionContent, opts ExportOptions) (string, error) {
+	data := struct {
+		Title    string
+		Headers  []exportHeader
+		Messages []htmlMessage
+	}{
+		Title:   exportTitle(content),
+		Headers: exportHeaders(content, opts),
+	}
+	for _, msg := range content.Messages {
+		m := htmlMessage{
+			Role:      msg.Role,
+			RoleTitle: roleTitle(msg.Role),
+			Timestamp: msg.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+			Time:      msg.Timestamp.Format("15:04:05"),
+			Content:   strings.TrimRight(msg.Content, "\n"),
+		}
+		if !opts.NoTools {
+			for _, call := range msg.ToolCalls {
+				m.ToolCalls = append(m.ToolCalls, htmlToolCall{
+					Status:  call.Status,
+					Marker:  toolStatusMarker(call),
+					Summary: FormatToolCall(call),
+					Output:  strings.TrimRight(call.Output, "\n"),
+				})
+			}
+		}
+		data.Messages = append(data.Messages, m)
+	}
+
+	var buf bytes.Buffer
+	if err := exportHTMLTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render HTML: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// remoteWebPattern matches git remotes on a web host: scp-style SSH
+// (git@host:owner/repo.git), ssh:// and http(s):// URLs
+var remoteWebPattern = regexp.MustCompile(`^(?:(?:ssh|https?)://)?(?:[^@/]+@)?([^:/]+)(?::\d+)?[:/](.+?)(?:\.git)?/?$`)
+
+// RepoWebURL returns the web URL of the repository's origin remote, e.g.
+// https://github.com/owner/repo, or "" if there is no usable remote
+func RepoWebURL(workdir string) string {
+	cmd := exec.Command("git", "remote", "get-url", "origin")
+	cmd.Dir = workdir
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return remoteToWebURL(strings.TrimSpace(string(output)))
+}
+
+// remoteToWebURL converts a git remote URL to the repository's web URL
+func remoteToWebURL(remote string) string {
+	if strings.HasPrefix(remote, "file://") || strings.HasPrefix(remote, "/") {
+		return ""
+	}
+	matches := remoteWebPattern.FindStringSubmatch(remote)
+	if matches == nil {
+		return ""
+	}
+	return "https://" + matches[1] + "/" + matches[2]
+}
@@ -0,0 +1,89 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func exportTestContent() *SessionContent {
+	exit := 1
+	at := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	return &SessionContent{
+		SessionID:     "550e8400",
+		FeatureBranch: "010-export",
+		CommitHash:    "0123456789abcdef0123456789abcdef01234567",
+		TaskID:        "SL-abc123",
+		Author:        "dev@example.com",
+		CapturedAt:    at,
+		Messages: []Message{
+			{Role: "user", Content: "run <the> tests", Timestamp: at},
+			{Role: "assistant", Content: "Running.", Timestamp: at.Add(time.Second), ToolCalls: []ToolCall{
+				{Name: "Bash", Command: "go test ./...", Status: ToolStatusError, ExitCode: &exit, Output: "--- FAIL\n```go\nx\n```"},
+				{Name: "Edit", FilePath: "x.go", DiffSummary: "+1 -1", Status: ToolStatusSuccess},
+			}},
+		},
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	out, err := Export(exportTestContent(), ExportMarkdown, ExportOptions{RepoURL: "https://github.com/o/r"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# Session for commit 0123456",
+		"| **Commit** | [0123456](https://github.com/o/r/commit/0123456789abcdef0123456789abcdef01234567) |",
+		"| **Branch** | [010-export](https://github.com/o/r/tree/010-export) |",
+		"| **Task** | SL-abc123 |",
+		"## User · 10:00:00",
+		"<summary>✗ $ go test ./... (exit 1)</summary>",
+		"````\n--- FAIL\n```go",
+		"- `✓ Edit x.go (+1 -1)`",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+
+	noTools, _ := Export(exportTestContent(), ExportMarkdown, ExportOptions{NoTools: true})
+	if strings.Contains(noTools, "go test") || strings.Contains(noTools, "](") {
+		t.Errorf("expected no tools and no links:\n%s", noTools)
+	}
+}
+
+func TestExportHTML(t *testing.T) {
+	out, err := Export(exportTestContent(), ExportHTML, ExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<title>Session for commit 0123456</title>",
+		"run &lt;the&gt; tests",
+		`<details class="tool"><summary><span class="error">✗</span> $ go test ./... (exit 1)</summary><pre>--- FAIL`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("html missing %q:\n%s", want, out)
+		}
+	}
+
+	if _, err := Export(exportTestContent(), "pdf", ExportOptions{}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestRemoteToWebURL(t *testing.T) {
+	tests := map[string]string{
+		"git@github.com:owner/repo.git":             "https://github.com/owner/repo",
+		"https://github.com/owner/repo.git":         "https://github.com/owner/repo",
+		"https://user:pw@gitlab.com/group/sub/repo": "https://gitlab.com/group/sub/repo",
+		"ssh://git@github.com:22/owner/repo.git":    "https://github.com/owner/repo",
+		"/srv/git/repo.git":                         "",
+		"file:///srv/git/repo.git":                  "",
+	}
+	for remote, want := range tests {
+		if got := remoteToWebURL(remote); got != want {
+			t.Errorf("remoteToWebURL(%q) = %q, want %q", remote, got, want)
+		}
+	}
+}
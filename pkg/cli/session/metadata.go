@@ -47,6 +47,10 @@ type CreateSessionInput struct {
 	SizeBytes     int64         `json:"size_bytes"`
 	RawSizeBytes  int64         `json:"raw_size_bytes"`
 	MessageCount  int           `json:"message_count"`
+	// Redactions is kept by backends that store metadata next to the content.
+	// The sessions table has no column for it: the counts travel in the
+	// session content.
+	Redactions map[string]int `json:"-"`
 }
 
 // Create creates a new session metadata record
@@ -0,0 +1,257 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+const (
+	// MaxLogSize is the size at which capture logs are rotated; one rotated
+	// copy (<log>.1) is kept
+	MaxLogSize = 1 << 20
+	// CaptureDebugLogFile is the capture debug log, kept in the temp directory
+	CaptureDebugLogFile = "sl-capture-debug.log"
+	// legacyRawLogFile held unredacted hook payloads in older versions; it is
+	// no longer written and is removed by Prune
+	legacyRawLogFile = "sl-capture-raw.log"
+)
+
+// Prune reasons
+const (
+	PruneReasonAge  = "age"
+	PruneReasonSize = "size"
+)
+
+// reservedBaseDirs are directories in ~/.specledger that hold other data than
+// cached sessions and are never pruned
+var reservedBaseDirs = map[string]bool{
+	"cache":          true, // spec dependencies
+	"playbook-cache": true,
+}
+
+// RetentionPolicy limits the local session cache
+type RetentionPolicy struct {
+	MaxAge       time.Duration // 0 disables the age limit
+	MaxSize      int64         // bytes; 0 disables the size limit
+	UploadedOnly bool          // never prune sessions still waiting for upload
+}
+
+// LoadRetentionPolicy reads session.retention from the project's
+// specledger.yaml, falling back to the defaults
+func LoadRetentionPolicy(workdir string) (RetentionPolicy, error) {
+	var cfg metadata.SessionRetentionConfig
+	if meta, err := metadata.LoadFromProject(workdir); err == nil && meta.Session != nil {
+		cfg = meta.Session.Retention
+	}
+	maxAge, err := cfg.MaxAgeDuration()
+	if err != nil {
+		return RetentionPolicy{}, fmt.Errorf("session.retention.max_age: %w", err)
+	}
+	maxSize, err := cfg.MaxSizeBytes()
+	if err != nil {
+		return RetentionPolicy{}, fmt.Errorf("session.retention.max_size: %w", err)
+	}
+	return RetentionPolicy{MaxAge: maxAge, MaxSize: maxSize, UploadedOnly: cfg.PruneUploadedOnly()}, nil
+}
+
+// PrunedSession is a cached session removed (or, in a dry run, to be
+// removed) by Prune
+type PrunedSession struct {
+	ProjectID  string    `json:"project_id"`
+	SpecKey    string    `json:"spec_key"`
+	Identifier string    `json:"identifier"`
+	SizeBytes  int64     `json:"size_bytes"`
+	ModTime    time.Time `json:"mod_time"`
+	Queued     bool      `json:"queued"`
+	Reason     string    `json:"reason"`
+}
+
+// PruneReport is the outcome of Prune
+type PruneReport struct {
+	DryRun      bool            `json:"dry_run"`
+	Pruned      []PrunedSession `json:"pruned"`
+	FreedBytes  int64           `json:"freed_bytes"`
+	KeptCount   int             `json:"kept_count"`
+	KeptBytes   int64           `json:"kept_bytes"`
+	RotatedLogs []string        `json:"rotated_logs,omitempty"`
+	RemovedLogs []string        `json:"removed_logs,omitempty"`
+}
+
+// Prune applies a project's retention policy to its sessions in the local
+// cache (~/.specledger/<projectID>); other projects' sessions follow their
+// own policy. Sessions older than MaxAge are removed, then the oldest until
+// the project's cache fits in MaxSize. Queued sessions are skipped when
+// UploadedOnly is set; otherwise they are removed from the queue too.
+// Oversized capture logs are rotated. With dryRun nothing is changed.
+func Prune(projectID string, policy RetentionPolicy, dryRun bool) (*PruneReport, error) {
+	if projectID == "" || reservedBaseDirs[projectID] || strings.ContainsAny(projectID, `/\`) || strings.HasPrefix(projectID, ".") {
+		return nil, fmt.Errorf("invalid project ID %q", projectID)
+	}
+	report := &PruneReport{DryRun: dryRun, Pruned: []PrunedSession{}}
+
+	cached, err := listCachedSessions(projectID)
+	if err != nil {
+		return nil, err
+	}
+	// Oldest first, so size pruning removes the oldest sessions
+	sort.Slice(cached, func(i, j int) bool { return cached[i].ModTime.Before(cached[j].ModTime) })
+
+	var total int64
+	for _, s := range cached {
+		total += s.SizeBytes
+	}
+
+	prune := func(s *PrunedSession, reason string) {
+		s.Reason = reason
+		report.Pruned = append(report.Pruned, *s)
+		report.FreedBytes += s.SizeBytes
+		total -= s.SizeBytes
+	}
+	removed := make([]bool, len(cached))
+
+	now := time.Now()
+	for i := range cached {
+		s := &cached[i]
+		if s.Queued && policy.UploadedOnly {
+			continue
+		}
+		if policy.MaxAge > 0 && now.Sub(s.ModTime) > policy.MaxAge {
+			prune(s, PruneReasonAge)
+			removed[i] = true
+		}
+	}
+	for i := range cached {
+		if policy.MaxSize <= 0 || total <= policy.MaxSize {
+			break
+		}
+		s := &cached[i]
+		if removed[i] || (s.Queued && policy.UploadedOnly) {
+			continue
+		}
+		prune(s, PruneReasonSize)
+		removed[i] = true
+	}
+
+	for i, s := range cached {
+		if !removed[i] {
+			report.KeptCount++
+			report.KeptBytes += s.SizeBytes
+		}
+	}
+
+	logs := []string{GetCaptureErrorsLogPath(), CaptureDebugLogPath()}
+	for _, path := range logs {
+		if info, err := os.Stat(path); err == nil && info.Size() > MaxLogSize {
+			report.RotatedLogs = append(report.RotatedLogs, path)
+		}
+	}
+	rawLog := filepath.Join(os.TempDir(), legacyRawLogFile)
+	for _, path := range []string{rawLog, rawLog + ".1"} {
+		if _, err := os.Stat(path); err == nil {
+			report.RemovedLogs = append(report.RemovedLogs, path)
+		}
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	queue := NewQueue()
+	for _, s := range report.Pruned {
+		if s.Queued {
+			if err := queue.Dequeue(s.ProjectID, s.SpecKey, s.Identifier); err != nil {
+				return report, fmt.Errorf("failed to remove queued session %s: %w", s.Identifier, err)
+			}
+			continue
+		}
+		if err := os.Remove(GetSessionPath(s.ProjectID, s.SpecKey, s.Identifier)); err != nil && !os.IsNotExist(err) {
+			return report, fmt.Errorf("failed to remove session %s: %w", s.Identifier, err)
+		}
+		removeEmptyDirs(filepath.Dir(GetSessionPath(s.ProjectID, s.SpecKey, s.Identifier)), GetBaseDir())
+	}
+	for _, path := range report.RotatedLogs {
+		RotateLog(path, MaxLogSize)
+	}
+	for _, path := range report.RemovedLogs {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return report, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	return report, nil
+}
+
+// listCachedSessions returns every session file in ~/.specledger/<projectID>,
+// with whether it is still waiting for upload
+func listCachedSessions(projectID string) ([]PrunedSession, error) {
+	queued := make(map[string]bool)
+	if refs, err := NewQueue().List(); err == nil {
+		for _, ref := range refs {
+			queued[GetSessionPath(ref.ProjectID, ref.SpecKey, ref.Identifier)] = true
+		}
+	}
+
+	projectDir := filepath.Join(GetBaseDir(), projectID)
+	if _, err := os.Stat(projectDir); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", projectDir, err)
+	}
+
+	var sessions []PrunedSession
+	_ = filepath.Walk(projectDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(info.Name(), ".json.gz") {
+			return nil
+		}
+		specDir, err := filepath.Rel(projectDir, filepath.Dir(path))
+		if err != nil {
+			return nil
+		}
+		s := PrunedSession{
+			ProjectID:  projectID,
+			SpecKey:    filepath.ToSlash(specDir),
+			Identifier: strings.TrimSuffix(info.Name(), ".json.gz"),
+			SizeBytes:  info.Size(),
+			ModTime:    info.ModTime(),
+			Queued:     queued[path],
+		}
+		if meta, err := os.Stat(GetSessionMetaPath(s.ProjectID, s.SpecKey, s.Identifier)); err == nil {
+			s.SizeBytes += meta.Size()
+		}
+		sessions = append(sessions, s)
+		return nil
+	})
+	return sessions, nil
+}
+
+// removeEmptyDirs removes dir and its parents while they are empty, stopping
+// at root
+func removeEmptyDirs(dir, root string) {
+	for dir != root && strings.HasPrefix(dir, root) {
+		if err := os.Remove(dir); err != nil {
+			return // Not empty
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// CaptureDebugLogPath returns the path of the capture debug log
+func CaptureDebugLogPath() string {
+	return filepath.Join(os.TempDir(), CaptureDebugLogFile)
+}
+
+// RotateLog moves a log larger than maxSize to <path>.1, replacing the
+// previous rotation. Errors are ignored: logs are best-effort.
+func RotateLog(path string, maxSize int64) {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() <= maxSize {
+		return
+	}
+	_ = os.Rename(path, path+".1")
+}
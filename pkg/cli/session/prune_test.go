@@ -0,0 +1,113 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrune(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("TMPDIR", t.TempDir())
+
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	cache := func(commit string, modTime time.Time, size int) {
+		t.Helper()
+		if err := SaveLocalSession("proj", "main", commit, []byte(strings.Repeat("x", size))); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(GetSessionPath("proj", "main", commit), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cache("old", old, 100)
+	cache("big1", time.Now().Add(-2*time.Hour), 400)
+	cache("big2", time.Now().Add(-time.Hour), 400)
+
+	// An old queued session, and data that isn't a session cache
+	q := NewQueue()
+	enqueueTestSession(t, q, "queued")
+	queuedPath := GetSessionPath("proj", "main", "queued")
+	if err := os.Chtimes(queuedPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveLocalSession("other", "main", "elsewhere", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	otherPath := GetSessionPath("other", "main", "elsewhere")
+	if err := os.Chtimes(otherPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	depCache := filepath.Join(GetBaseDir(), "cache", "dep", "x.json.gz")
+	if err := os.MkdirAll(filepath.Dir(depCache), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(depCache, []byte("dep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// An oversized debug log is rotated, and the old raw payload log removed
+	if err := os.WriteFile(CaptureDebugLogPath(), make([]byte, MaxLogSize+1), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rawLog := filepath.Join(os.TempDir(), legacyRawLogFile)
+	if err := os.WriteFile(rawLog, []byte("raw_input={}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := RetentionPolicy{MaxAge: 30 * 24 * time.Hour, MaxSize: 1000, UploadedOnly: true}
+	dry, err := Prune("proj", policy, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dry.Pruned) != 2 || dry.Pruned[0].Identifier != "old" || dry.Pruned[0].Reason != PruneReasonAge ||
+		dry.Pruned[1].Identifier != "big1" || dry.Pruned[1].Reason != PruneReasonSize || len(dry.RotatedLogs) != 1 ||
+		len(dry.RemovedLogs) != 1 {
+		t.Fatalf("unexpected dry run: %+v", dry)
+	}
+	if _, err := os.Stat(GetSessionPath("proj", "main", "old")); err != nil {
+		t.Fatal("dry run removed a session")
+	}
+
+	report, err := Prune("proj", policy, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Pruned) != 2 || report.KeptCount != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	for path, want := range map[string]bool{
+		GetSessionPath("proj", "main", "old"):  false,
+		GetSessionPath("proj", "main", "big1"): false,
+		GetSessionPath("proj", "main", "big2"): true,
+		queuedPath:                             true,
+		otherPath:                              true, // another project's policy applies
+		depCache:                               true,
+		CaptureDebugLogPath():                  false,
+		CaptureDebugLogPath() + ".1":           true,
+		rawLog:                                 false,
+	} {
+		if _, err := os.Stat(path); (err == nil) != want {
+			t.Errorf("%s exists = %v, want %v", path, err == nil, want)
+		}
+	}
+
+	// Without uploaded_only, the old queued session goes too, queue included
+	report, err = Prune("proj", RetentionPolicy{MaxAge: 30 * 24 * time.Hour}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Pruned) != 1 || !report.Pruned[0].Queued {
+		t.Fatalf("expected the queued session to be pruned: %+v", report)
+	}
+	if n, _ := q.Count(); n != 0 {
+		t.Errorf("expected empty queue, got %d", n)
+	}
+
+	if _, err := Prune("cache", RetentionPolicy{}, true); err == nil {
+		t.Error("expected an error for a reserved directory")
+	}
+}
@@ -2,18 +2,27 @@ package session
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
 )
 
 const (
-	// MaxRetries is the maximum number of upload retries
-	MaxRetries = 3
-	// RetryDelay is the initial delay between retries
-	RetryDelay = 5 * time.Second
+	// MaxRetries is the maximum number of upload retries. Attempts made while
+	// offline don't count.
+	MaxRetries = 10
+	// RetryDelay is the initial delay between retries; it doubles with each
+	// retry up to MaxRetryDelay
+	RetryDelay = 30 * time.Second
+	// MaxRetryDelay caps the delay between retries
+	MaxRetryDelay = time.Hour
 	// QueueIndexFile is the name of the queue index file
 	QueueIndexFile = ".queue.json"
 )
@@ -117,14 +126,7 @@ func (q *Queue) saveIndex(index *QueueIndex) error {
 // Stores at: ~/.specledger/{project_id}/{spec_key}/{identifier}.json.gz
 func (q *Queue) Enqueue(entry *QueueEntry, compressedData []byte) error {
 	// Determine identifier (commit hash or task ID)
-	var identifier string
-	if entry.CommitHash != nil {
-		identifier = *entry.CommitHash
-	} else if entry.TaskID != nil {
-		identifier = *entry.TaskID
-	} else {
-		identifier = entry.SessionID
-	}
+	identifier := LocalIdentifier(entry.CommitHash, entry.TaskID, entry.SessionID)
 
 	// Create directory structure
 	sessionDir := GetSessionDir(entry.ProjectID, entry.FeatureBranch)
@@ -274,8 +276,10 @@ func (q *Queue) ListEntries() ([]*QueueEntry, error) {
 	return entries, nil
 }
 
-// UpdateRetryCount updates the retry count for a queued session
-func (q *Queue) UpdateRetryCount(projectID, specKey, identifier string) error {
+// RecordFailure records a failed upload attempt on a queued session. Attempts
+// that failed because the machine is offline keep the error but don't count
+// as a retry or push back the next one.
+func (q *Queue) RecordFailure(projectID, specKey, identifier string, uploadErr error, offline bool) error {
 	metaPath := GetSessionMetaPath(projectID, specKey, identifier)
 
 	metaData, err := os.ReadFile(metaPath)
@@ -288,9 +292,14 @@ func (q *Queue) UpdateRetryCount(projectID, specKey, identifier string) error {
 		return fmt.Errorf("failed to parse metadata: %w", err)
 	}
 
-	entry.RetryCount++
 	now := time.Now()
+	entry.LastError = uploadErr.Error()
 	entry.LastRetry = &now
+	if !offline {
+		entry.RetryCount++
+		next := now.Add(RetryBackoff(entry.RetryCount))
+		entry.NextRetry = &next
+	}
 
 	updatedData, err := json.MarshalIndent(entry, "", "  ")
 	if err != nil {
@@ -304,75 +313,133 @@ func (q *Queue) UpdateRetryCount(projectID, specKey, identifier string) error {
 	return nil
 }
 
-// ShouldRetry checks if a session should be retried
+// ResetRetries clears the retry count and backoff of the project's queued
+// sessions, so the next pass retries them all
+func (q *Queue) ResetRetries(projectID string) error {
+	refs, err := q.List()
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if ref.ProjectID != projectID {
+			continue
+		}
+		metaPath := GetSessionMetaPath(ref.ProjectID, ref.SpecKey, ref.Identifier)
+		_, entry, err := q.GetQueuedSession(ref.ProjectID, ref.SpecKey, ref.Identifier)
+		if err != nil {
+			continue
+		}
+		entry.RetryCount = 0
+		entry.NextRetry = nil
+		data, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		if err := os.WriteFile(metaPath, data, 0600); err != nil {
+			return fmt.Errorf("failed to write metadata: %w", err)
+		}
+	}
+	return nil
+}
+
+// RetryBackoff returns the delay before the given retry: exponential from
+// RetryDelay, capped at MaxRetryDelay, with jitter so that queued sessions
+// (and machines) don't retry in lockstep. The result lies between half and
+// all of the exponential delay.
+func RetryBackoff(retryCount int) time.Duration {
+	delay := RetryDelay
+	for i := 1; i < retryCount && delay < MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, MaxRetryDelay)
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// ShouldRetry checks if a session should be retried now
 func (q *Queue) ShouldRetry(entry *QueueEntry) bool {
 	if entry.RetryCount >= MaxRetries {
 		return false
 	}
+	return entry.NextRetry == nil || !time.Now().Before(*entry.NextRetry)
+}
 
-	if entry.LastRetry != nil {
-		// Exponential backoff
-		delay := RetryDelay * time.Duration(1<<entry.RetryCount)
-		if time.Since(*entry.LastRetry) < delay {
-			return false
-		}
-	}
+// SyncResult is the outcome of a pass over the upload queue
+type SyncResult struct {
+	Uploaded int
+	Failed   int
+	Skipped  int  // retries exhausted
+	Deferred int  // waiting for their next retry
+	Offline  bool // the backend was unreachable; remaining sessions were left queued
+	Errors   []error
+	// NextRetry is the earliest time a deferred session can be retried
+	NextRetry *time.Time
+}
 
-	return true
+// merge adds the outcome of another pass over the queue
+func (r *SyncResult) merge(other *SyncResult) {
+	r.Uploaded += other.Uploaded
+	r.Failed += other.Failed
+	r.Skipped += other.Skipped
+	r.Deferred += other.Deferred
+	r.Offline = r.Offline || other.Offline
+	r.Errors = append(r.Errors, other.Errors...)
+	if other.NextRetry != nil && (r.NextRetry == nil || other.NextRetry.Before(*r.NextRetry)) {
+		r.NextRetry = other.NextRetry
+	}
 }
 
-// ProcessQueue attempts to upload all queued sessions
-func (q *Queue) ProcessQueue(accessToken string) (uploaded int, failed int, skipped int, errors []error) {
+// ProcessQueue attempts to upload projectID's queued sessions to backend.
+// The queue is shared by all projects, which may use different backends:
+// sessions of other projects, or captured for another backend, are left
+// queued for a sync from their own project.
+//
+// A network error stops the pass: the remaining sessions would fail the same
+// way, and retries made while offline are not counted.
+func (q *Queue) ProcessQueue(backend Backend, projectID string) *SyncResult {
+	result := &SyncResult{}
 	refs, err := q.List()
 	if err != nil {
-		return 0, 0, 0, []error{err}
+		result.Errors = []error{err}
+		return result
 	}
 
-	storage := NewStorageClient()
-	metadata := NewMetadataClient()
-
 	for _, ref := range refs {
+		if ref.ProjectID != projectID {
+			continue
+		}
+
 		data, entry, err := q.GetQueuedSession(ref.ProjectID, ref.SpecKey, ref.Identifier)
 		if err != nil {
-			errors = append(errors, fmt.Errorf("failed to get session %s: %w", ref.Identifier, err))
-			failed++
+			result.Errors = append(result.Errors, fmt.Errorf("failed to get session %s: %w", ref.Identifier, err))
+			result.Failed++
+			continue
+		}
+		if entry.backendName() != backend.Name() {
 			continue
 		}
 
+		if result.Offline {
+			result.Deferred++
+			continue
+		}
+		if entry.RetryCount >= MaxRetries {
+			result.Skipped++
+			continue
+		}
 		if !q.ShouldRetry(entry) {
-			skipped++
+			result.Deferred++
+			if result.NextRetry == nil || entry.NextRetry.Before(*result.NextRetry) {
+				result.NextRetry = entry.NextRetry
+			}
 			continue
 		}
 
 		// Build storage path (same structure as local)
 		storagePath := BuildStoragePath(ref.ProjectID, ref.SpecKey, ref.Identifier)
 
-		// Try to upload
-		_, err = storage.Upload(accessToken, storagePath, data)
-		if err != nil {
-			_ = q.UpdateRetryCount(ref.ProjectID, ref.SpecKey, ref.Identifier)
-			uploadErr := fmt.Errorf("failed to upload session %s: %w", ref.Identifier, err)
-			errors = append(errors, uploadErr)
-			// Log retry failure to both local and Supabase
-			commitHash := ""
-			if entry.CommitHash != nil {
-				commitHash = *entry.CommitHash
-			}
-			LogCaptureError(CaptureErrorEntry{
-				UserID:        entry.AuthorID,
-				ProjectID:     entry.ProjectID,
-				SessionID:     entry.SessionID,
-				ErrorMessage:  fmt.Sprintf("queue retry upload failed: %v", err),
-				FeatureBranch: entry.FeatureBranch,
-				CommitHash:    commitHash,
-				RetryCount:    entry.RetryCount + 1,
-			})
-			failed++
-			continue
-		}
-
-		// Create metadata
-		_, err = metadata.Create(accessToken, &CreateSessionInput{
+		// Try to upload content and create metadata
+		_, err = backend.Store(&CreateSessionInput{
 			ProjectID:     entry.ProjectID,
 			FeatureBranch: entry.FeatureBranch,
 			CommitHash:    entry.CommitHash,
@@ -381,14 +448,21 @@ func (q *Queue) ProcessQueue(accessToken string) (uploaded int, failed int, skip
 			StoragePath:   storagePath,
 			Status:        entry.Status,
 			SizeBytes:     int64(len(data)),
-			RawSizeBytes:  0, // We don't track raw size in queue
-			MessageCount:  0, // We don't track message count in queue
-		})
+			RawSizeBytes:  entry.RawSizeBytes,
+			MessageCount:  entry.MessageCount,
+			Redactions:    entry.Redactions,
+		}, data)
 		if err != nil {
-			_ = q.UpdateRetryCount(ref.ProjectID, ref.SpecKey, ref.Identifier)
-			metaErr := fmt.Errorf("failed to create metadata for session %s: %w", ref.Identifier, err)
-			errors = append(errors, metaErr)
-			// Log retry failure to both local and Supabase
+			offline := IsNetworkError(err)
+			_ = q.RecordFailure(ref.ProjectID, ref.SpecKey, ref.Identifier, err, offline)
+			if offline {
+				result.Offline = true
+				result.Deferred++
+				result.Errors = append(result.Errors, fmt.Errorf("backend unreachable: %w", err))
+				continue
+			}
+			result.Errors = append(result.Errors, fmt.Errorf("failed to upload session %s: %w", ref.Identifier, err))
+			// Log retry failure to both local and Sentry
 			commitHash := ""
 			if entry.CommitHash != nil {
 				commitHash = *entry.CommitHash
@@ -397,21 +471,48 @@ func (q *Queue) ProcessQueue(accessToken string) (uploaded int, failed int, skip
 				UserID:        entry.AuthorID,
 				ProjectID:     entry.ProjectID,
 				SessionID:     entry.SessionID,
-				ErrorMessage:  fmt.Sprintf("queue retry metadata failed: %v", err),
+				ErrorMessage:  fmt.Sprintf("queue retry failed: %v", err),
 				FeatureBranch: entry.FeatureBranch,
 				CommitHash:    commitHash,
 				RetryCount:    entry.RetryCount + 1,
 			})
-			failed++
+			result.Failed++
 			continue
 		}
 
-		// Success - remove from queue
+		// Success - remove from queue, keeping the content as a search cache
 		_ = q.Dequeue(ref.ProjectID, ref.SpecKey, ref.Identifier)
-		uploaded++
+		_ = SaveLocalSession(ref.ProjectID, ref.SpecKey, ref.Identifier, data)
+		result.Uploaded++
 	}
 
-	return uploaded, failed, skipped, errors
+	return result
+}
+
+// backendName returns the backend the session was captured for. Entries
+// queued before backends were recorded were all captured for Supabase.
+func (e *QueueEntry) backendName() string {
+	if e.Backend == "" {
+		return metadata.SessionBackendSupabase
+	}
+	return e.Backend
+}
+
+// IsNetworkError reports whether err means the backend couldn't be reached
+// (DNS failure, refused connection, timeout), as opposed to the backend
+// rejecting the request. Other transport errors, such as TLS certificate or
+// malformed URL errors, won't go away by waiting and count as failures.
+func IsNetworkError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var timeout interface{ Timeout() bool }
+	return errors.As(err, &timeout) && timeout.Timeout()
 }
 
 // GetLocalSession retrieves a session from local storage (not necessarily queued)
@@ -0,0 +1,156 @@
+package session
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// failingBackend is a Backend whose Store always fails with err
+type failingBackend struct {
+	LocalBackend
+	err   error
+	calls int
+}
+
+func (b *failingBackend) Store(*CreateSessionInput, []byte) (*SessionMetadata, error) {
+	b.calls++
+	return nil, b.err
+}
+
+func enqueueTestSession(t *testing.T, q *Queue, commit string) {
+	t.Helper()
+	enqueueProjectSession(t, q, "proj", metadata.SessionBackendLocal, commit)
+}
+
+func enqueueProjectSession(t *testing.T, q *Queue, projectID, backend, commit string) {
+	t.Helper()
+	data, err := Compress([]byte(`{"version":"2.0"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := &QueueEntry{SessionID: "s-" + commit, ProjectID: projectID, Backend: backend, FeatureBranch: "main", CommitHash: strPtr(commit), Status: StatusComplete, CreatedAt: time.Now()}
+	if err := q.Enqueue(entry, data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	for retry, base := range map[int]time.Duration{1: RetryDelay, 2: 2 * RetryDelay, 4: 8 * RetryDelay, 30: MaxRetryDelay, 100: MaxRetryDelay} {
+		for i := 0; i < 20; i++ {
+			if d := RetryBackoff(retry); d < base/2 || d > base {
+				t.Fatalf("RetryBackoff(%d) = %s, want within [%s, %s]", retry, d, base/2, base)
+			}
+		}
+	}
+}
+
+func TestIsNetworkError(t *testing.T) {
+	offline := &url.Error{Op: "Post", URL: "https://example.com", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+	if !IsNetworkError(errors.Join(errors.New("upload failed"), offline)) {
+		t.Error("expected wrapped dial error to be a network error")
+	}
+	if IsNetworkError(errors.New("upload failed (403): forbidden")) {
+		t.Error("expected HTTP rejection not to be a network error")
+	}
+	badCert := &url.Error{Op: "Post", URL: "https://example.com", Err: x509.UnknownAuthorityError{}}
+	if IsNetworkError(badCert) {
+		t.Error("expected a certificate error not to be a network error")
+	}
+	timeout := &url.Error{Op: "Post", URL: "https://example.com", Err: context.DeadlineExceeded}
+	if !IsNetworkError(timeout) {
+		t.Error("expected a timeout to be a network error")
+	}
+}
+
+func TestProcessQueueBackoff(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	q := NewQueue()
+	enqueueTestSession(t, q, "aaaaaaa")
+	enqueueTestSession(t, q, "bbbbbbb")
+
+	// Offline: the pass stops at the first network error, and nothing counts
+	// as a retry
+	offline := &failingBackend{err: &url.Error{Op: "Post", URL: "https://x", Err: &net.DNSError{Err: "no such host", Name: "x"}}}
+	result := q.ProcessQueue(offline, "proj")
+	if !result.Offline || result.Deferred != 2 || offline.calls != 1 {
+		t.Fatalf("unexpected offline result: %+v (calls %d)", result, offline.calls)
+	}
+	entries, _ := q.ListEntries()
+	for _, e := range entries {
+		if e.RetryCount != 0 || e.NextRetry != nil {
+			t.Errorf("offline attempt counted as retry: %+v", e)
+		}
+	}
+	if entries[0].LastError == "" {
+		t.Error("expected last error to be recorded")
+	}
+
+	// Rejected: counted, and deferred until the backoff elapses
+	rejected := &failingBackend{err: errors.New("upload failed (500): boom")}
+	result = q.ProcessQueue(rejected, "proj")
+	if result.Failed != 2 || result.Offline {
+		t.Fatalf("unexpected rejected result: %+v", result)
+	}
+	result = q.ProcessQueue(rejected, "proj")
+	if result.Deferred != 2 || result.NextRetry == nil || rejected.calls != 2 {
+		t.Fatalf("expected both sessions deferred, got %+v (calls %d)", result, rejected.calls)
+	}
+	entries, _ = q.ListEntries()
+	if entries[0].RetryCount != 1 || entries[0].LastError != "upload failed (500): boom" {
+		t.Errorf("unexpected entry after failure: %+v", entries[0])
+	}
+
+	// Forcing clears the backoff of the project's sessions only, and a
+	// working backend drains the queue
+	enqueueProjectSession(t, q, "other", metadata.SessionBackendLocal, "ccccccc")
+	if err := q.RecordFailure("other", "main", "ccccccc", errors.New("boom"), false); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.ResetRetries("proj"); err != nil {
+		t.Fatal(err)
+	}
+	if _, other, err := q.GetQueuedSession("other", "main", "ccccccc"); err != nil || other.RetryCount != 1 {
+		t.Errorf("expected other project's retries kept, got %+v (%v)", other, err)
+	}
+	result = q.ProcessQueue(NewLocalBackend(t.TempDir()), "proj")
+	if result.Uploaded != 2 {
+		t.Fatalf("expected both sessions uploaded, got %+v", result)
+	}
+	if n, _ := q.Count(); n != 1 {
+		t.Errorf("expected only the other project's session queued, got %d", n)
+	}
+}
+
+func TestProcessQueueScopedToProjectAndBackend(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	q := NewQueue()
+	enqueueProjectSession(t, q, "proj", metadata.SessionBackendLocal, "aaaaaaa")
+	enqueueProjectSession(t, q, "other", metadata.SessionBackendLocal, "bbbbbbb")
+	enqueueProjectSession(t, q, "proj", metadata.SessionBackendS3, "ccccccc")
+	enqueueProjectSession(t, q, "proj", "", "ddddddd") // queued before backends were recorded
+
+	result := q.ProcessQueue(NewLocalBackend(t.TempDir()), "proj")
+	if result.Uploaded != 1 {
+		t.Fatalf("expected only the local session of proj uploaded, got %+v", result)
+	}
+
+	refs, err := q.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left []string
+	for _, ref := range refs {
+		left = append(left, ref.ProjectID+"/"+ref.Identifier)
+	}
+	if got := strings.Join(left, ","); got != "other/bbbbbbb,proj/ccccccc,proj/ddddddd" {
+		t.Errorf("left queued = %s", got)
+	}
+}
@@ -0,0 +1,197 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/specledger/specledger/pkg/cli/config"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// redactionPattern is a named secret format. If the pattern has a group
+// named "secret", only that group is replaced so the surrounding key name
+// stays readable.
+type redactionPattern struct {
+	Name string
+	Re   *regexp.Regexp
+}
+
+// builtinRedactionPatterns cover common credential formats. Order matters:
+// specific token formats run before the generic assignment rule so counts
+// name the provider.
+var builtinRedactionPatterns = []redactionPattern{
+	{"private-key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{"aws-access-key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"github-token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"anthropic-key", regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}`)},
+	{"openai-key", regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_-]{20,}`)},
+	{"slack-token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{"stripe-key", regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{16,}`)},
+	{"google-api-key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"bearer-token", regexp.MustCompile(`(?i)\bbearer\s+(?P<secret>[A-Za-z0-9._~+/-]{16,}=*)`)},
+	{"url-credentials", regexp.MustCompile(`\b[a-z][a-z0-9+.-]*://[^\s:/@]+:(?P<secret>[^\s@/]{3,})@`)},
+	// KEY=value lines as found in .env files and shell exports
+	{"secret-assignment", regexp.MustCompile(`(?i)\b[A-Z0-9_.-]*(?:SECRET|TOKEN|PASSWORD|PASSWD|API_?KEY|ACCESS_?KEY|PRIVATE_?KEY|CREDENTIALS?)[A-Z0-9_.-]*["']?\s*[:=]\s*["']?(?P<secret>[^\s"'` + "`" + `,;]{8,})`)},
+}
+
+// minLiteralSecretLength avoids redacting short config values that would
+// match ordinary words
+const minLiteralSecretLength = 8
+
+// Redactor removes secrets from transcript text before it leaves the machine
+type Redactor struct {
+	patterns []redactionPattern
+	literals []string
+}
+
+// RedactionCounts maps a rule name to the number of values it replaced
+type RedactionCounts map[string]int
+
+// Total returns the number of redacted values
+func (c RedactionCounts) Total() int {
+	total := 0
+	for _, n := range c {
+		total += n
+	}
+	return total
+}
+
+// String formats the counts as "rule=n, ..." sorted by rule name
+func (c RedactionCounts) String() string {
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%d", name, c[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// NewRedactor creates a redactor with the built-in patterns, the given
+// literal secret values and user patterns. User patterns are named
+// "custom-1", "custom-2", ... in the order given.
+func NewRedactor(literals []string, userPatterns []string) (*Redactor, error) {
+	r := &Redactor{patterns: append([]redactionPattern{}, builtinRedactionPatterns...)}
+	for i, expr := range userPatterns {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", expr, err)
+		}
+		r.patterns = append(r.patterns, redactionPattern{Name: fmt.Sprintf("custom-%d", i+1), Re: re})
+	}
+
+	seen := make(map[string]bool)
+	for _, lit := range literals {
+		if len(lit) >= minLiteralSecretLength && !seen[lit] {
+			seen[lit] = true
+			r.literals = append(r.literals, lit)
+		}
+	}
+	// Longest first so a value containing another is replaced whole
+	sort.Slice(r.literals, func(i, j int) bool { return len(r.literals[i]) > len(r.literals[j]) })
+	return r, nil
+}
+
+// LoadRedactor builds the redactor for the project at workdir: built-in
+// patterns, the auth token and API keys from the resolved agent config, and
+// session.redaction.patterns from specledger.yaml.
+func LoadRedactor(workdir string) (*Redactor, error) {
+	var userPatterns []string
+	if meta, err := metadata.LoadFromProject(workdir); err == nil && meta.Session != nil {
+		userPatterns = meta.Session.Redaction.Patterns
+	}
+	return NewRedactor(config.ResolveSensitiveValues(), userPatterns)
+}
+
+// Redact replaces secrets in text and adds what it replaced to counts
+func (r *Redactor) Redact(text string, counts RedactionCounts) string {
+	for _, lit := range r.literals {
+		if n := strings.Count(text, lit); n > 0 {
+			text = strings.ReplaceAll(text, lit, redactionMarker("config-secret"))
+			counts["config-secret"] += n
+		}
+	}
+
+	for _, p := range r.patterns {
+		group := p.Re.SubexpIndex("secret")
+		text = p.Re.ReplaceAllStringFunc(text, func(match string) string {
+			// Don't count values an earlier rule already replaced
+			if strings.Contains(match, "[REDACTED:") {
+				return match
+			}
+			counts[p.Name]++
+			if group < 0 {
+				return redactionMarker(p.Name)
+			}
+			sub := p.Re.FindStringSubmatchIndex(match)
+			start, end := sub[2*group], sub[2*group+1]
+			return match[:start] + redactionMarker(p.Name) + match[end:]
+		})
+	}
+	return text
+}
+
+// RedactMessages redacts message content and tool call commands and output
+// in place, and returns the counts
+func (r *Redactor) RedactMessages(messages []Message) RedactionCounts {
+	counts := make(RedactionCounts)
+	for i := range messages {
+		messages[i].Content = r.Redact(messages[i].Content, counts)
+		for j := range messages[i].ToolCalls {
+			call := &messages[i].ToolCalls[j]
+			call.Command = r.Redact(call.Command, counts)
+			call.Output = r.Redact(call.Output, counts)
+		}
+	}
+	return counts
+}
+
+func redactionMarker(name string) string {
+	return "[REDACTED:" + name + "]"
+}
+
+// RedactionFinding describes the secrets found in one message or file
+type RedactionFinding struct {
+	Source  string          `json:"source"`  // e.g. "message 12 (assistant)" or a file path
+	Counts  RedactionCounts `json:"counts"`  // values replaced, by rule
+	Excerpt string          `json:"excerpt"` // redacted text around the first replacement
+}
+
+// excerptRadius is the context kept on each side of the first redaction
+const excerptRadius = 40
+
+// Scan reports what Redact would replace in text without keeping the secrets.
+// Returns nil if nothing would be redacted.
+func (r *Redactor) Scan(source, text string) *RedactionFinding {
+	counts := make(RedactionCounts)
+	redacted := r.Redact(text, counts)
+	if counts.Total() == 0 {
+		return nil
+	}
+
+	start := strings.Index(redacted, "[REDACTED:")
+	end := start + strings.Index(redacted[start:], "]") + 1
+	from, to := max(0, start-excerptRadius), min(len(redacted), end+excerptRadius)
+	for from > 0 && !utf8.RuneStart(redacted[from]) {
+		from--
+	}
+	for to < len(redacted) && !utf8.RuneStart(redacted[to]) {
+		to++
+	}
+	excerpt := strings.Join(strings.Fields(redacted[from:to]), " ")
+	if from > 0 {
+		excerpt = "…" + excerpt
+	}
+	if to < len(redacted) {
+		excerpt += "…"
+	}
+
+	return &RedactionFinding{Source: source, Counts: counts, Excerpt: excerpt}
+}
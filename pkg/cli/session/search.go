@@ -0,0 +1,383 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// SearchIndexFile is the name of a project's search index, stored next to
+	// its cached sessions in ~/.specledger/{project_id}/
+	SearchIndexFile = ".search-index.json"
+	// searchIndexVersion is bumped when tokenization changes, which forces a
+	// full rebuild
+	searchIndexVersion = 1
+	// snippetRadius is the number of characters shown around a match
+	snippetRadius = 60
+)
+
+// SearchIndex is a persistent full-text index over the sessions cached in
+// ~/.specledger/{project_id}/. Each cached session is indexed once and
+// re-indexed only when its file changes, so searches don't decompress every
+// session.
+type SearchIndex struct {
+	Version  int                        `json:"version"`
+	Sessions map[string]*IndexedSession `json:"sessions"` // keyed by path relative to the project cache
+	path     string
+	root     string
+}
+
+// IndexedSession is a cached session's metadata and terms
+type IndexedSession struct {
+	SessionID     string           `json:"session_id"`
+	FeatureBranch string           `json:"feature_branch"`
+	CommitHash    string           `json:"commit_hash,omitempty"`
+	TaskID        string           `json:"task_id,omitempty"`
+	Author        string           `json:"author,omitempty"`
+	CapturedAt    time.Time        `json:"captured_at"`
+	MessageCount  int              `json:"message_count"`
+	ModTime       time.Time        `json:"mod_time"`
+	Size          int64            `json:"size"`
+	Terms         map[string][]int `json:"terms"` // term -> indexes of the messages containing it
+}
+
+// SearchOptions filters a search
+type SearchOptions struct {
+	FeatureBranch string
+	Since         time.Time
+	Limit         int
+}
+
+// SearchResult is a session that matched a search
+type SearchResult struct {
+	SessionID     string          `json:"session_id"`
+	FeatureBranch string          `json:"feature_branch"`
+	CommitHash    string          `json:"commit_hash,omitempty"`
+	TaskID        string          `json:"task_id,omitempty"`
+	CapturedAt    time.Time       `json:"captured_at"`
+	Matches       int             `json:"matches"` // number of matching messages
+	Snippets      []SearchSnippet `json:"snippets"`
+}
+
+// SearchSnippet is an excerpt of a matching message
+type SearchSnippet struct {
+	Message int    `json:"message"` // index in the session's messages
+	Role    string `json:"role"`
+	Text    string `json:"text"`
+}
+
+// LoadSearchIndex loads the search index of a project's session cache. A
+// missing or outdated index is returned empty and rebuilt by Refresh.
+func LoadSearchIndex(projectID string) (*SearchIndex, error) {
+	root := filepath.Join(GetBaseDir(), projectID)
+	index := &SearchIndex{
+		Version:  searchIndexVersion,
+		Sessions: make(map[string]*IndexedSession),
+		path:     filepath.Join(root, SearchIndexFile),
+		root:     root,
+	}
+
+	data, err := os.ReadFile(index.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return index, nil
+		}
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+
+	var stored SearchIndex
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != searchIndexVersion || stored.Sessions == nil {
+		return index, nil // Corrupt or old format; rebuild
+	}
+	index.Sessions = stored.Sessions
+	return index, nil
+}
+
+// Refresh indexes new and changed sessions in the cache and drops deleted
+// ones. It returns the number of sessions (re)indexed and removed.
+func (idx *SearchIndex) Refresh() (indexed int, removed int, err error) {
+	seen := make(map[string]bool)
+	walkErr := filepath.Walk(idx.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == idx.root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".json.gz") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(idx.root, path)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		seen[rel] = true
+
+		if existing, ok := idx.Sessions[rel]; ok && existing.Size == info.Size() && existing.ModTime.Equal(info.ModTime()) {
+			return nil
+		}
+
+		content, err := readCachedSession(path)
+		if err != nil {
+			delete(idx.Sessions, rel) // Unreadable sessions are skipped until they change
+			return nil
+		}
+		entry := indexSession(content)
+		entry.ModTime = info.ModTime()
+		entry.Size = info.Size()
+		idx.Sessions[rel] = entry
+		indexed++
+		return nil
+	})
+	if walkErr != nil {
+		return indexed, removed, fmt.Errorf("failed to scan session cache: %w", walkErr)
+	}
+
+	for rel := range idx.Sessions {
+		if !seen[rel] {
+			delete(idx.Sessions, rel)
+			removed++
+		}
+	}
+	return indexed, removed, nil
+}
+
+// Save writes the index to disk
+func (idx *SearchIndex) Save() error {
+	if err := ensureDir(idx.root); err != nil {
+		return fmt.Errorf("failed to create session cache directory: %w", err)
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search index: %w", err)
+	}
+	if err := os.WriteFile(idx.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	return nil
+}
+
+// Search returns the cached sessions containing every term of the query,
+// most matching messages first. Snippets are read from the matching
+// sessions only.
+func (idx *SearchIndex) Search(query string, opts SearchOptions) ([]SearchResult, error) {
+	terms := Tokenize(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search query has no searchable words")
+	}
+
+	type match struct {
+		rel      string
+		entry    *IndexedSession
+		messages []int
+	}
+	var matches []match
+	for rel, entry := range idx.Sessions {
+		if opts.FeatureBranch != "" && entry.FeatureBranch != opts.FeatureBranch {
+			continue
+		}
+		if !opts.Since.IsZero() && entry.CapturedAt.Before(opts.Since) {
+			continue
+		}
+		if messages := entry.messagesWithAll(terms); len(messages) > 0 {
+			matches = append(matches, match{rel, entry, messages})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if len(matches[i].messages) != len(matches[j].messages) {
+			return len(matches[i].messages) > len(matches[j].messages)
+		}
+		return matches[i].entry.CapturedAt.After(matches[j].entry.CapturedAt)
+	})
+	if opts.Limit > 0 && len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
+	}
+
+	results := make([]SearchResult, 0, len(matches))
+	for _, m := range matches {
+		result := SearchResult{
+			SessionID:     m.entry.SessionID,
+			FeatureBranch: m.entry.FeatureBranch,
+			CommitHash:    m.entry.CommitHash,
+			TaskID:        m.entry.TaskID,
+			CapturedAt:    m.entry.CapturedAt,
+			Matches:       len(m.messages),
+			Snippets:      []SearchSnippet{},
+		}
+		if content, err := readCachedSession(filepath.Join(idx.root, filepath.FromSlash(m.rel))); err == nil {
+			for _, i := range m.messages {
+				if i >= len(content.Messages) || len(result.Snippets) == 3 {
+					break
+				}
+				msg := content.Messages[i]
+				result.Snippets = append(result.Snippets, SearchSnippet{
+					Message: i,
+					Role:    msg.Role,
+					Text:    snippet(messageSearchText(msg), terms),
+				})
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// messagesWithAll returns the indexes of the messages containing every term
+func (s *IndexedSession) messagesWithAll(terms []string) []int {
+	counts := make(map[int]int)
+	for _, term := range terms {
+		positions, ok := s.Terms[term]
+		if !ok {
+			return nil
+		}
+		for _, i := range positions {
+			counts[i]++
+		}
+	}
+	var messages []int
+	for i, n := range counts {
+		if n == len(terms) {
+			messages = append(messages, i)
+		}
+	}
+	sort.Ints(messages)
+	return messages
+}
+
+// Tokenize splits text into lowercase words of two or more letters or
+// digits, without duplicates
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	seen := make(map[string]bool, len(words))
+	var terms []string
+	for _, w := range words {
+		if len([]rune(w)) < 2 || seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+// indexSession builds the index entry of a session
+func indexSession(content *SessionContent) *IndexedSession {
+	entry := &IndexedSession{
+		SessionID:     content.SessionID,
+		FeatureBranch: content.FeatureBranch,
+		CommitHash:    content.CommitHash,
+		TaskID:        content.TaskID,
+		Author:        content.Author,
+		CapturedAt:    content.CapturedAt,
+		MessageCount:  len(content.Messages),
+		Terms:         make(map[string][]int),
+	}
+	for i, msg := range content.Messages {
+		for _, term := range Tokenize(messageSearchText(msg)) {
+			entry.Terms[term] = append(entry.Terms[term], i)
+		}
+	}
+	return entry
+}
+
+// messageSearchText is the searchable text of a message: its content and
+// the commands and files of its tool calls
+func messageSearchText(msg Message) string {
+	parts := []string{msg.Content}
+	for _, call := range msg.ToolCalls {
+		if call.Command != "" {
+			parts = append(parts, "$ "+call.Command)
+		}
+		if call.FilePath != "" {
+			parts = append(parts, call.Name+" "+call.FilePath)
+		}
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n"))
+}
+
+// snippet returns the text around the first occurrence of any term, on a
+// single line
+func snippet(text string, terms []string) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	lower := []rune(strings.ToLower(string(runes)))
+
+	pos := -1
+	for _, term := range terms {
+		if i := runeIndex(lower, []rune(term)); i >= 0 && (pos < 0 || i < pos) {
+			pos = i
+		}
+	}
+	if pos < 0 {
+		pos = 0
+	}
+
+	start, end := pos-snippetRadius, pos+snippetRadius
+	prefix, suffix := "...", "..."
+	if start <= 0 {
+		start, prefix = 0, ""
+	}
+	if end >= len(runes) {
+		end, suffix = len(runes), ""
+	}
+	return prefix + string(runes[start:end]) + suffix
+}
+
+// runeIndex returns the index of needle in haystack, or -1
+func runeIndex(haystack, needle []rune) int {
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		if string(haystack[i:i+len(needle)]) == string(needle) {
+			return i
+		}
+	}
+	return -1
+}
+
+// readCachedSession reads and parses a compressed session file
+func readCachedSession(path string) (*SessionContent, error) {
+	compressed, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := Decompress(compressed)
+	if err != nil {
+		return nil, err
+	}
+	var content SessionContent
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+	return &content, nil
+}
+
+// LocalIdentifier returns the name a session is stored under in the local
+// cache: its commit hash, else its task ID, else its session ID
+func LocalIdentifier(commitHash, taskID *string, sessionID string) string {
+	if commitHash != nil && *commitHash != "" {
+		return *commitHash
+	}
+	if taskID != nil && *taskID != "" {
+		return *taskID
+	}
+	return sessionID
+}
+
+// CacheSession keeps a copy of an uploaded or downloaded session in the
+// local cache so it can be searched offline. Failures are ignored: the cache
+// is an optimization.
+func CacheSession(meta *SessionMetadata, compressed []byte) {
+	if meta == nil || meta.ProjectID == "" || meta.FeatureBranch == "" {
+		return
+	}
+	_ = SaveLocalSession(meta.ProjectID, meta.FeatureBranch, LocalIdentifier(meta.CommitHash, meta.TaskID, meta.ID), compressed)
+}
@@ -0,0 +1,104 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func cacheTestSession(t *testing.T, projectID, branch, commit string, capturedAt time.Time, messages ...Message) {
+	t.Helper()
+	data, err := json.Marshal(&SessionContent{
+		Version:       SessionContentVersion,
+		SessionID:     "id-" + commit,
+		FeatureBranch: branch,
+		CommitHash:    commit,
+		CapturedAt:    capturedAt,
+		Messages:      messages,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed, err := Compress(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveLocalSession(projectID, branch, commit, compressed); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Use the Retry-Policy: retry x3, a_b ÉTÉ")
+	want := []string{"use", "the", "retry", "policy", "x3", "a_b", "été"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize() = %v, want %v", got, want)
+	}
+}
+
+func TestSearchIndex(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now()
+
+	cacheTestSession(t, "proj", "010-retry", "aaaaaaa1", now.Add(-48*time.Hour),
+		Message{Role: "user", Content: "Should the retry policy use exponential backoff?"},
+		Message{Role: "assistant", Content: "Yes, we decided on exponential backoff with jitter for the retry policy."},
+	)
+	cacheTestSession(t, "proj", "feature/auth", "bbbbbbb2", now,
+		Message{Role: "user", Content: "add a login page"},
+		Message{Role: "assistant", Content: "Done.", ToolCalls: []ToolCall{{Name: "Bash", Command: "go test ./auth/..."}}},
+	)
+
+	index, err := LoadSearchIndex("proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexed, removed, err := index.Refresh()
+	if err != nil || indexed != 2 || removed != 0 {
+		t.Fatalf("Refresh() = %d, %d, %v", indexed, removed, err)
+	}
+	if err := index.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := index.Search("Retry Policy", SearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].CommitHash != "aaaaaaa1" || results[0].Matches != 2 {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if s := results[0].Snippets[1].Text; s == "" || !strings.Contains(strings.ToLower(s), "retry") {
+		t.Errorf("unexpected snippet: %q", s)
+	}
+
+	// Tool commands are searchable, and filters apply
+	if results, _ := index.Search("go test", SearchOptions{}); len(results) != 1 || results[0].FeatureBranch != "feature/auth" {
+		t.Errorf("expected tool command match, got %+v", results)
+	}
+	if results, _ := index.Search("backoff", SearchOptions{FeatureBranch: "feature/auth"}); len(results) != 0 {
+		t.Errorf("expected feature filter to exclude matches, got %+v", results)
+	}
+	if results, _ := index.Search("backoff", SearchOptions{Since: now.Add(-time.Hour)}); len(results) != 0 {
+		t.Errorf("expected since filter to exclude matches, got %+v", results)
+	}
+	if _, err := index.Search("?!", SearchOptions{}); err == nil {
+		t.Error("expected error for a query without words")
+	}
+
+	// A reloaded index only reindexes what changed
+	reloaded, err := LoadSearchIndex("proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(GetSessionPath("proj", "feature/auth", "bbbbbbb2")); err != nil {
+		t.Fatal(err)
+	}
+	indexed, removed, err = reloaded.Refresh()
+	if err != nil || indexed != 0 || removed != 1 {
+		t.Errorf("Refresh() after delete = %d, %d, %v", indexed, removed, err)
+	}
+}
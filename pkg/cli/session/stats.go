@@ -0,0 +1,208 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FeatureStats aggregates the sessions of a feature branch
+type FeatureStats struct {
+	FeatureBranch       string    `json:"feature_branch"`
+	Sessions            int       `json:"sessions"`
+	Messages            int       `json:"messages"`
+	SizeBytes           int64     `json:"size_bytes"`
+	RawSizeBytes        int64     `json:"raw_size_bytes"`
+	CommitsWithSessions int       `json:"commits_with_sessions"`
+	TotalCommits        int       `json:"total_commits"` // commits on the branch; -1 if unknown
+	Authors             []string  `json:"authors"`
+	FirstSession        time.Time `json:"first_session"`
+	LastSession         time.Time `json:"last_session"`
+	commits             map[string]bool
+}
+
+// CommitsWithoutSessions returns the branch commits that have no session, or
+// -1 if the branch's commits are unknown
+func (f *FeatureStats) CommitsWithoutSessions() int {
+	if f.TotalCommits < 0 {
+		return -1
+	}
+	return f.TotalCommits - f.CommitsWithSessions
+}
+
+// AssistedPercent returns the share of the branch's commits that have a
+// session, or -1 if the branch's commits are unknown
+func (f *FeatureStats) AssistedPercent() float64 {
+	if f.TotalCommits <= 0 {
+		return -1
+	}
+	return 100 * float64(f.CommitsWithSessions) / float64(f.TotalCommits)
+}
+
+// IssueStats aggregates the sessions linked to an issue
+type IssueStats struct {
+	TaskID        string   `json:"task_id"`
+	FeatureBranch string   `json:"feature_branch"`
+	Sessions      int      `json:"sessions"`
+	Messages      int      `json:"messages"`
+	SizeBytes     int64    `json:"size_bytes"`
+	Commits       int      `json:"commits"`
+	Authors       []string `json:"authors"`
+	commits       map[string]bool
+}
+
+// StatsReport is the aggregate of a set of sessions
+type StatsReport struct {
+	Features []*FeatureStats `json:"features"`
+	Issues   []*IssueStats   `json:"issues"`
+	Sessions int             `json:"sessions"`
+	Messages int             `json:"messages"`
+	Authors  []string        `json:"authors"`
+}
+
+// ComputeStats aggregates sessions per feature branch and per issue. Branch
+// commit counts are left unknown (-1); see AddBranchCommits.
+func ComputeStats(sessions []SessionMetadata) *StatsReport {
+	report := &StatsReport{Features: []*FeatureStats{}, Issues: []*IssueStats{}}
+	features := make(map[string]*FeatureStats)
+	issues := make(map[string]*IssueStats)
+	featureAuthors := make(map[string]map[string]bool)
+	issueAuthors := make(map[string]map[string]bool)
+	allAuthors := make(map[string]bool)
+
+	for _, s := range sessions {
+		report.Sessions++
+		report.Messages += s.MessageCount
+		allAuthors[s.AuthorID] = true
+
+		f, ok := features[s.FeatureBranch]
+		if !ok {
+			f = &FeatureStats{FeatureBranch: s.FeatureBranch, TotalCommits: -1, FirstSession: s.CreatedAt, LastSession: s.CreatedAt, commits: make(map[string]bool)}
+			features[s.FeatureBranch] = f
+			featureAuthors[s.FeatureBranch] = make(map[string]bool)
+			report.Features = append(report.Features, f)
+		}
+		f.Sessions++
+		f.Messages += s.MessageCount
+		f.SizeBytes += s.SizeBytes
+		f.RawSizeBytes += s.RawSizeBytes
+		featureAuthors[s.FeatureBranch][s.AuthorID] = true
+		if s.CreatedAt.Before(f.FirstSession) {
+			f.FirstSession = s.CreatedAt
+		}
+		if s.CreatedAt.After(f.LastSession) {
+			f.LastSession = s.CreatedAt
+		}
+		if s.CommitHash != nil && *s.CommitHash != "" {
+			f.commits[*s.CommitHash] = true
+		}
+
+		if s.TaskID == nil || *s.TaskID == "" {
+			continue
+		}
+		issue, ok := issues[*s.TaskID]
+		if !ok {
+			issue = &IssueStats{TaskID: *s.TaskID, FeatureBranch: s.FeatureBranch, commits: make(map[string]bool)}
+			issues[*s.TaskID] = issue
+			issueAuthors[*s.TaskID] = make(map[string]bool)
+			report.Issues = append(report.Issues, issue)
+		}
+		issue.Sessions++
+		issue.Messages += s.MessageCount
+		issue.SizeBytes += s.SizeBytes
+		issueAuthors[*s.TaskID][s.AuthorID] = true
+		if s.CommitHash != nil && *s.CommitHash != "" {
+			issue.commits[*s.CommitHash] = true
+		}
+	}
+
+	for _, f := range report.Features {
+		f.CommitsWithSessions = len(f.commits)
+		f.Authors = sortedKeys(featureAuthors[f.FeatureBranch])
+	}
+	for _, issue := range report.Issues {
+		issue.Commits = len(issue.commits)
+		issue.Authors = sortedKeys(issueAuthors[issue.TaskID])
+	}
+	report.Authors = sortedKeys(allAuthors)
+
+	sort.Slice(report.Features, func(i, j int) bool {
+		return report.Features[i].LastSession.After(report.Features[j].LastSession)
+	})
+	sort.Slice(report.Issues, func(i, j int) bool { return report.Issues[i].TaskID < report.Issues[j].TaskID })
+	return report
+}
+
+// AddBranchCommits fills in the feature's branch commit count and restricts
+// its commits with sessions to those still on the branch (amended or rebased
+// commits no longer count)
+func (f *FeatureStats) AddBranchCommits(branchCommits []string) {
+	f.TotalCommits = len(branchCommits)
+	with := 0
+	for _, c := range branchCommits {
+		if f.commits[c] {
+			with++
+		}
+	}
+	f.CommitsWithSessions = with
+}
+
+// BranchCommits returns the commits on branch that aren't on the repository's
+// default branch, newest first, optionally only those committed since a time.
+// It fails if the branch doesn't exist locally or is the default branch.
+func BranchCommits(workdir, branch string, since time.Time) ([]string, error) {
+	base := DefaultBranch(workdir)
+	if base == "" || base == branch {
+		return nil, errors.New("no base branch to compare with")
+	}
+	if !gitRefExists(workdir, "refs/heads/"+base) {
+		base = "origin/" + base
+	}
+	args := []string{"rev-list", base + ".." + branch}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+	cmd := exec.Command("git", append(args, "--")...)
+	cmd.Dir = workdir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits of %s: %w", branch, err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// DefaultBranch returns the repository's default branch: origin's HEAD if
+// known, else main or master, whichever exists
+func DefaultBranch(workdir string) string {
+	cmd := exec.Command("git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	cmd.Dir = workdir
+	if output, err := cmd.Output(); err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(string(output)), "origin/")
+	}
+	for _, name := range []string{"main", "master"} {
+		if gitRefExists(workdir, "refs/heads/"+name) {
+			return name
+		}
+	}
+	return ""
+}
+
+func gitRefExists(workdir, ref string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref)
+	cmd.Dir = workdir
+	return cmd.Run() == nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,73 @@
+package session
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeStats(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sessions := []SessionMetadata{
+		{FeatureBranch: "010-a", CommitHash: strPtr("c1"), TaskID: strPtr("SL-aaaaaa"), AuthorID: "ann", MessageCount: 10, SizeBytes: 100, CreatedAt: base},
+		{FeatureBranch: "010-a", CommitHash: strPtr("c1"), AuthorID: "bob", MessageCount: 5, SizeBytes: 50, CreatedAt: base.Add(time.Hour)},
+		{FeatureBranch: "010-a", CommitHash: strPtr("c2"), TaskID: strPtr("SL-aaaaaa"), AuthorID: "ann", MessageCount: 1, SizeBytes: 10, CreatedAt: base.Add(2 * time.Hour)},
+		{FeatureBranch: "011-b", CommitHash: strPtr("c9"), AuthorID: "ann", MessageCount: 2, SizeBytes: 20, CreatedAt: base.Add(48 * time.Hour)},
+	}
+
+	report := ComputeStats(sessions)
+	if report.Sessions != 4 || report.Messages != 18 || strings.Join(report.Authors, ",") != "ann,bob" {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+	if len(report.Features) != 2 || report.Features[0].FeatureBranch != "011-b" {
+		t.Fatalf("expected features newest first: %+v", report.Features)
+	}
+	a := report.Features[1]
+	if a.Sessions != 3 || a.Messages != 16 || a.SizeBytes != 160 || a.CommitsWithSessions != 2 || len(a.Authors) != 2 || a.TotalCommits != -1 {
+		t.Errorf("unexpected feature stats: %+v", a)
+	}
+	if a.CommitsWithoutSessions() != -1 || a.AssistedPercent() != -1 {
+		t.Error("expected unknown commit counts before AddBranchCommits")
+	}
+
+	// c2 was amended away; c3 and c4 have no session
+	a.AddBranchCommits([]string{"c1", "c3", "c4"})
+	if a.TotalCommits != 3 || a.CommitsWithSessions != 1 || a.CommitsWithoutSessions() != 2 || int(a.AssistedPercent()) != 33 {
+		t.Errorf("unexpected branch stats: %+v", a)
+	}
+
+	if len(report.Issues) != 1 || report.Issues[0].Sessions != 2 || report.Issues[0].Commits != 2 || report.Issues[0].Messages != 11 {
+		t.Errorf("unexpected issue stats: %+v", report.Issues)
+	}
+}
+
+func TestBranchCommits(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	git("commit", "-q", "--allow-empty", "-m", "base")
+	git("checkout", "-q", "-b", "010-a")
+	git("commit", "-q", "--allow-empty", "-m", "one")
+	git("commit", "-q", "--allow-empty", "-m", "two")
+
+	commits, err := BranchCommits(dir, "010-a", time.Time{})
+	if err != nil || len(commits) != 2 {
+		t.Errorf("BranchCommits() = %v, %v", commits, err)
+	}
+	if _, err := BranchCommits(dir, "main", time.Time{}); err == nil {
+		t.Error("expected error for the default branch")
+	}
+	if _, err := BranchCommits(dir, "missing", time.Time{}); err == nil {
+		t.Error("expected error for a missing branch")
+	}
+}
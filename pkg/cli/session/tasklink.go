@@ -0,0 +1,98 @@
+package session
+
+import (
+	"bufio"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+// issueIDPattern matches issue IDs (SL-xxxxxx) anywhere in text
+var issueIDPattern = regexp.MustCompile(`\bSL-[a-f0-9]{6}\b`)
+
+// trailerPattern matches a git trailer line, e.g. "Refs: SL-abc123"
+var trailerPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*:\s+\S`)
+
+// IssueIDsFromCommitMessage returns the issue IDs mentioned in a commit
+// message, IDs in trailers ("Refs: SL-abc123", "Closes: ...") first, then
+// those in the subject and body, without duplicates.
+func IssueIDsFromCommitMessage(message string) []string {
+	paragraphs := strings.Split(strings.TrimSpace(message), "\n\n")
+	var trailers, body []string
+	if len(paragraphs) > 1 && isTrailerBlock(paragraphs[len(paragraphs)-1]) {
+		trailers = issueIDPattern.FindAllString(paragraphs[len(paragraphs)-1], -1)
+		paragraphs = paragraphs[:len(paragraphs)-1]
+	}
+	body = issueIDPattern.FindAllString(strings.Join(paragraphs, "\n\n"), -1)
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, id := range append(trailers, body...) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// isTrailerBlock checks if every line of a paragraph is a "Key: value" trailer
+func isTrailerBlock(paragraph string) bool {
+	scanner := bufio.NewScanner(strings.NewReader(paragraph))
+	for scanner.Scan() {
+		if !trailerPattern.MatchString(scanner.Text()) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetCommitMessage returns the full message of a commit
+func GetCommitMessage(workdir, commit string) (string, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%B", commit)
+	cmd.Dir = workdir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// ResolveTaskID picks the issue a commit session belongs to: the first issue
+// ID in the commit message, otherwise the spec's only in_progress issue.
+// With several issues in progress there's no telling which one the commit
+// is for, so the session stays unlinked. Returns "" if nothing matches.
+func ResolveTaskID(workdir, commitHash, branch string) string {
+	if message, err := GetCommitMessage(workdir, commitHash); err == nil {
+		if ids := IssueIDsFromCommitMessage(message); len(ids) > 0 {
+			return ids[0]
+		}
+	}
+
+	specContext, ok := issues.ParseSpecFromBranch(branch)
+	if !ok {
+		return ""
+	}
+	artifactPath := "specledger"
+	if meta, err := metadata.LoadFromProject(workdir); err == nil {
+		artifactPath = meta.GetArtifactPath()
+	}
+	store, err := issues.NewStore(issues.StoreOptions{
+		BasePath:    filepath.Join(workdir, artifactPath),
+		SpecContext: specContext,
+	})
+	if err != nil {
+		return ""
+	}
+
+	status := issues.StatusInProgress
+	inProgress, err := store.List(issues.ListFilter{Status: &status})
+	if err != nil || len(inProgress) != 1 {
+		return ""
+	}
+	return inProgress[0].ID
+}
@@ -0,0 +1,84 @@
+package session
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+func TestIssueIDsFromCommitMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{"none", "fix typo", nil},
+		{"subject", "SL-abc123: fix typo", []string{"SL-abc123"}},
+		{"trailer first", "fix SL-aaaaaa regression\n\nSee also SL-bbbbbb.\n\nRefs: SL-cccccc\nSigned-off-by: A <a@b.c>", []string{"SL-cccccc", "SL-aaaaaa", "SL-bbbbbb"}},
+		{"deduplicated", "SL-abc123 part 1\n\nCloses: SL-abc123", []string{"SL-abc123"}},
+		{"invalid ids ignored", "SL-ABC123 SL-12345 SL-1234567", nil},
+		{"prose last paragraph", "fix\n\nthis is SL-abc123 work, not a trailer", []string{"SL-abc123"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IssueIDsFromCommitMessage(tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IssueIDsFromCommitMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveTaskID(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("commit", "-q", "--allow-empty", "-m", "wip\n\nRefs: SL-abc123")
+	if got := ResolveTaskID(dir, "HEAD", "010-feature"); got != "SL-abc123" {
+		t.Errorf("ResolveTaskID() from trailer = %q", got)
+	}
+
+	git("commit", "-q", "--allow-empty", "-m", "no issue mentioned")
+	if got := ResolveTaskID(dir, "HEAD", "010-feature"); got != "" {
+		t.Errorf("ResolveTaskID() without issues = %q, want empty", got)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "specledger", "010-feature"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	store, err := issues.NewStore(issues.StoreOptions{BasePath: filepath.Join(dir, "specledger"), SpecContext: "010-feature"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := issues.NewIssue("first", "", "010-feature", issues.TypeTask, 2)
+	first.Status = issues.StatusInProgress
+	if err := store.Create(first); err != nil {
+		t.Fatal(err)
+	}
+	if got := ResolveTaskID(dir, "HEAD", "010-feature"); got != first.ID {
+		t.Errorf("ResolveTaskID() with one in_progress issue = %q, want %q", got, first.ID)
+	}
+	if got := ResolveTaskID(dir, "HEAD", "main"); got != "" {
+		t.Errorf("ResolveTaskID() off a feature branch = %q, want empty", got)
+	}
+
+	second := issues.NewIssue("second", "", "010-feature", issues.TypeTask, 2)
+	second.Status = issues.StatusInProgress
+	if err := store.Create(second); err != nil {
+		t.Fatal(err)
+	}
+	if got := ResolveTaskID(dir, "HEAD", "010-feature"); got != "" {
+		t.Errorf("ResolveTaskID() with two in_progress issues = %q, want empty", got)
+	}
+}
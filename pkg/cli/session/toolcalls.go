@@ -0,0 +1,242 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxToolOutput caps the tool result kept per call; full command output
+// would dwarf the conversation. Adapters keep the full output so redaction
+// sees whole secrets; capture truncates afterwards.
+const maxToolOutput = 2000
+
+// exitCodePattern matches the "Exit code N" prefix of failed Bash results
+var exitCodePattern = regexp.MustCompile(`^Exit code (\d+)`)
+
+// toolResult is a tool's response, recorded in a later transcript line than
+// the call it answers
+type toolResult struct {
+	ID       string
+	IsError  bool
+	Output   string
+	ExitCode *int
+}
+
+// newToolCall builds a ToolCall from a tool's name and JSON input, picking out
+// the fields reviewers care about for the tools agents commonly use.
+func newToolCall(id, name string, input map[string]interface{}) ToolCall {
+	call := ToolCall{ID: id, Name: name}
+	str := func(key string) string {
+		s, _ := input[key].(string)
+		return s
+	}
+
+	call.Command = str("command")
+	for _, key := range []string{"file_path", "notebook_path", "path"} {
+		if call.FilePath = str(key); call.FilePath != "" {
+			break
+		}
+	}
+
+	switch name {
+	case "Edit":
+		call.DiffSummary = diffSummary(lineCount(str("new_string")), lineCount(str("old_string")))
+	case "MultiEdit":
+		var added, removed int
+		if edits, ok := input["edits"].([]interface{}); ok {
+			for _, e := range edits {
+				if edit, ok := e.(map[string]interface{}); ok {
+					newString, _ := edit["new_string"].(string)
+					oldString, _ := edit["old_string"].(string)
+					added += lineCount(newString)
+					removed += lineCount(oldString)
+				}
+			}
+		}
+		call.DiffSummary = diffSummary(added, removed)
+	case "Write":
+		call.DiffSummary = diffSummary(lineCount(str("content")), 0)
+	}
+	return call
+}
+
+// newPatchToolCall builds a ToolCall for an apply_patch style edit, taking
+// the files and line counts from the patch text
+func newPatchToolCall(id, name, patch string) ToolCall {
+	call := ToolCall{ID: id, Name: name}
+	var files []string
+	var added, removed int
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "*** Add File: "), strings.HasPrefix(line, "*** Update File: "), strings.HasPrefix(line, "*** Delete File: "):
+			files = append(files, strings.TrimSpace(line[strings.Index(line, ":")+1:]))
+		case strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++"):
+			added++
+		case strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---"):
+			removed++
+		}
+	}
+	call.FilePath = strings.Join(files, ", ")
+	call.DiffSummary = diffSummary(added, removed)
+	return call
+}
+
+// attachToolResults moves results onto the calls they answer and drops
+// messages that only carried results. Results for calls outside messages
+// (e.g. made before the previous capture) are discarded.
+func attachToolResults(messages []Message) []Message {
+	calls := make(map[string]*ToolCall)
+	out := messages[:0]
+	for _, msg := range messages {
+		for _, res := range msg.toolResults {
+			call, ok := calls[res.ID]
+			if !ok {
+				continue
+			}
+			call.Status = ToolStatusSuccess
+			if res.IsError {
+				call.Status = ToolStatusError
+			}
+			call.Output = res.Output
+			call.ExitCode = res.ExitCode
+			if call.ExitCode == nil && call.Command != "" {
+				// Claude's Bash tool reports failures as "Exit code N"
+				code := 0
+				if m := exitCodePattern.FindStringSubmatch(res.Output); m != nil {
+					code, _ = strconv.Atoi(m[1])
+				} else if res.IsError {
+					code = -1
+				}
+				if code >= 0 {
+					call.ExitCode = &code
+				}
+			}
+		}
+		msg.toolResults = nil
+
+		if msg.Content == "" && len(msg.ToolCalls) == 0 {
+			continue
+		}
+		out = append(out, msg)
+		last := &out[len(out)-1]
+		for i := range last.ToolCalls {
+			if id := last.ToolCalls[i].ID; id != "" {
+				calls[id] = &last.ToolCalls[i]
+			}
+		}
+	}
+	return out
+}
+
+// extractToolBlocks returns the tool_use and tool_result blocks of a Claude
+// message's content array
+func extractToolBlocks(c interface{}) ([]ToolCall, []toolResult) {
+	blocks, ok := c.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	var calls []ToolCall
+	var results []toolResult
+	for _, item := range blocks {
+		block, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch block["type"] {
+		case "tool_use":
+			id, _ := block["id"].(string)
+			name, _ := block["name"].(string)
+			input, _ := block["input"].(map[string]interface{})
+			calls = append(calls, newToolCall(id, name, input))
+		case "tool_result":
+			id, _ := block["tool_use_id"].(string)
+			isError, _ := block["is_error"].(bool)
+			results = append(results, toolResult{ID: id, IsError: isError, Output: extractContent(block["content"])})
+		}
+	}
+	return calls, results
+}
+
+// decodeToolArguments parses a JSON-encoded argument object, as used by
+// function calls in OpenAI-style transcripts
+func decodeToolArguments(arguments string) map[string]interface{} {
+	var input map[string]interface{}
+	if err := json.Unmarshal([]byte(arguments), &input); err != nil {
+		return map[string]interface{}{}
+	}
+	return input
+}
+
+// FormatToolCall renders a tool call on one line, e.g.
+// "$ go test ./... (exit 1)" or "Edit pkg/x.go (+3 -1)"
+func FormatToolCall(call ToolCall) string {
+	var b strings.Builder
+	switch {
+	case call.Command != "":
+		b.WriteString("$ " + firstLine(call.Command))
+	case call.FilePath != "":
+		b.WriteString(call.Name + " " + call.FilePath)
+	default:
+		b.WriteString(call.Name)
+	}
+
+	var details []string
+	if call.DiffSummary != "" {
+		details = append(details, call.DiffSummary)
+	}
+	if call.ExitCode != nil {
+		details = append(details, fmt.Sprintf("exit %d", *call.ExitCode))
+	} else if call.Status == ToolStatusError {
+		details = append(details, "failed")
+	}
+	if len(details) > 0 {
+		b.WriteString(" (" + strings.Join(details, ", ") + ")")
+	}
+	return b.String()
+}
+
+func diffSummary(added, removed int) string {
+	if added == 0 && removed == 0 {
+		return ""
+	}
+	return fmt.Sprintf("+%d -%d", added, removed)
+}
+
+func lineCount(s string) int {
+	if s == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimSuffix(s, "\n"), "\n") + 1
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + " …"
+	}
+	return s
+}
+
+// truncateToolOutputs caps each tool call's output at maxToolOutput
+func truncateToolOutputs(messages []Message) {
+	for i := range messages {
+		for j := range messages[i].ToolCalls {
+			call := &messages[i].ToolCalls[j]
+			call.Output = truncateOutput(call.Output)
+		}
+	}
+}
+
+func truncateOutput(s string) string {
+	if len(s) <= maxToolOutput {
+		return s
+	}
+	cut := maxToolOutput
+	for cut > 0 && s[cut]&0xC0 == 0x80 {
+		cut-- // don't split a UTF-8 sequence
+	}
+	return s[:cut] + fmt.Sprintf("\n… (%d bytes truncated)", len(s)-cut)
+}
@@ -0,0 +1,112 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTranscript(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestComputeDeltaToolCalls(t *testing.T) {
+	path := writeTranscript(t,
+		`{"type":"user","message":{"role":"user","content":"run the tests"},"timestamp":"2026-01-01T00:00:00Z"}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Running them."},{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test ./..."}}]},"timestamp":"2026-01-01T00:00:01Z"}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","is_error":true,"content":"Exit code 1\n--- FAIL: TestX"}]},"timestamp":"2026-01-01T00:00:02Z"}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Edit","input":{"file_path":"x.go","old_string":"a\nb","new_string":"a\nb\nc"}}]},"timestamp":"2026-01-01T00:00:03Z"}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":"ok"}]},"timestamp":"2026-01-01T00:00:04Z"}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"earlier","content":"orphan"}]},"timestamp":"2026-01-01T00:00:05Z"}`,
+	)
+
+	messages, _, err := ComputeDelta(path, 0)
+	if err != nil {
+		t.Fatalf("ComputeDelta() error: %v", err)
+	}
+	if len(messages) != 3 {
+		t.Fatalf("expected result-only messages to be folded into calls, got %d: %+v", len(messages), messages)
+	}
+
+	bash := messages[1].ToolCalls
+	if messages[1].Content != "Running them." || len(bash) != 1 {
+		t.Fatalf("unexpected assistant message: %+v", messages[1])
+	}
+	if bash[0].Command != "go test ./..." || bash[0].Status != ToolStatusError || bash[0].ExitCode == nil || *bash[0].ExitCode != 1 {
+		t.Errorf("unexpected bash call: %+v", bash[0])
+	}
+
+	edit := messages[2].ToolCalls[0]
+	if edit.FilePath != "x.go" || edit.DiffSummary != "+3 -2" || edit.Status != ToolStatusSuccess || edit.ExitCode != nil {
+		t.Errorf("unexpected edit call: %+v", edit)
+	}
+	if got := FormatToolCall(edit); got != "Edit x.go (+3 -2)" {
+		t.Errorf("FormatToolCall() = %q", got)
+	}
+	if got := FormatToolCall(bash[0]); got != "$ go test ./... (exit 1)" {
+		t.Errorf("FormatToolCall() = %q", got)
+	}
+}
+
+func TestCodexToolCalls(t *testing.T) {
+	path := writeTranscript(t,
+		`{"type":"response_item","payload":{"type":"function_call","name":"shell","call_id":"c1","arguments":"{\"command\":[\"bash\",\"-lc\",\"git status\"],\"workdir\":\"/work\"}"}}`,
+		`{"type":"response_item","payload":{"type":"function_call_output","call_id":"c1","output":"{\"output\":\"clean\",\"metadata\":{\"exit_code\":0}}"}}`,
+		`{"type":"response_item","payload":{"type":"custom_tool_call","name":"apply_patch","call_id":"c2","input":"*** Begin Patch\n*** Update File: a.go\n@@\n-old\n+new\n+more\n*** End Patch"}}`,
+	)
+
+	messages, _, err := codexAdapter{}.ReadDelta(path, 0)
+	if err != nil {
+		t.Fatalf("ReadDelta() error: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 messages, got %+v", messages)
+	}
+
+	shell := messages[0].ToolCalls[0]
+	if shell.Command != "git status" || shell.Output != "clean" || shell.ExitCode == nil || *shell.ExitCode != 0 || shell.Status != ToolStatusSuccess {
+		t.Errorf("unexpected shell call: %+v", shell)
+	}
+	patch := messages[1].ToolCalls[0]
+	if patch.FilePath != "a.go" || patch.DiffSummary != "+2 -1" {
+		t.Errorf("unexpected patch call: %+v", patch)
+	}
+}
+
+func TestTruncateOutput(t *testing.T) {
+	long := strings.Repeat("é", maxToolOutput)
+	got := truncateOutput(long)
+	if len(got) > maxToolOutput+64 || !strings.Contains(got, "bytes truncated") {
+		t.Errorf("unexpected truncation: %d bytes", len(got))
+	}
+	if !strings.HasPrefix(got, strings.Repeat("é", maxToolOutput/2)) {
+		t.Error("truncation split a UTF-8 sequence")
+	}
+}
+
+func TestTruncateToolOutputsAfterRedaction(t *testing.T) {
+	r, err := NewRedactor([]string{"my-config-token-value"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The secret straddles the truncation point
+	output := strings.Repeat("x", maxToolOutput-10) + "my-config-token-value" + strings.Repeat("y", 100)
+	messages := []Message{{Role: "assistant", ToolCalls: []ToolCall{{ID: "1", Name: "Bash", Output: output}}}}
+
+	if counts := r.RedactMessages(messages); counts.Total() != 1 {
+		t.Fatalf("expected the secret to be redacted, got %v", counts)
+	}
+	truncateToolOutputs(messages)
+
+	got := messages[0].ToolCalls[0].Output
+	if strings.Contains(got, "my-config") || !strings.Contains(got, "bytes truncated") {
+		t.Errorf("unexpected output: %q", got[len(got)-80:])
+	}
+}
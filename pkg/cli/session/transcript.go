@@ -0,0 +1,132 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Agent names accepted by 'sl session capture --agent'. They match the
+// commands in internal/agent.Registry.
+const (
+	AgentClaude   = "claude"
+	AgentOpenCode = "opencode"
+	AgentCopilot  = "github-copilot"
+	AgentCodex    = "codex"
+)
+
+// TranscriptAdapter understands one coding agent's hook payload and
+// transcript storage.
+type TranscriptAdapter interface {
+	// Name returns the agent command, e.g. "codex"
+	Name() string
+	// ParseHookInput converts the agent's hook payload into a HookInput
+	ParseHookInput(data []byte) (*HookInput, error)
+	// FindTranscript locates the transcript for sessionID, or the most recent
+	// transcript for cwd when sessionID is empty
+	FindTranscript(sessionID, cwd string) (string, error)
+	// ReadDelta returns the messages recorded after offset and the new offset.
+	// Offsets are opaque to callers; file-based transcripts use byte offsets.
+	ReadDelta(path string, offset int64) ([]Message, int64, error)
+}
+
+var transcriptAdapters = map[string]TranscriptAdapter{
+	AgentClaude:   claudeAdapter{},
+	AgentCodex:    codexAdapter{},
+	AgentCopilot:  copilotAdapter{},
+	AgentOpenCode: openCodeAdapter{},
+}
+
+// GetTranscriptAdapter returns the adapter for an agent command.
+// An empty name selects Claude Code.
+func GetTranscriptAdapter(agent string) (TranscriptAdapter, error) {
+	if agent == "" {
+		agent = AgentClaude
+	}
+	adapter, ok := transcriptAdapters[strings.ToLower(agent)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported agent: %s (valid: %s)", agent, strings.Join(TranscriptAgents(), ", "))
+	}
+	return adapter, nil
+}
+
+// TranscriptAgents lists the agents session capture supports
+func TranscriptAgents() []string {
+	names := make([]string, 0, len(transcriptAdapters))
+	for name := range transcriptAdapters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// claudeAdapter reads Claude Code's JSONL transcripts from
+// ~/.claude/projects/<project-slug>/<session-id>.jsonl
+type claudeAdapter struct{}
+
+func (claudeAdapter) Name() string { return AgentClaude }
+
+func (claudeAdapter) ParseHookInput(data []byte) (*HookInput, error) {
+	return ParseHookInput(data)
+}
+
+func (claudeAdapter) FindTranscript(sessionID, cwd string) (string, error) {
+	if sessionID != "" {
+		return findTranscriptBySessionID(sessionID)
+	}
+
+	// Claude Code names project directories after the path, e.g.
+	// /home/me/app -> -home-me-app
+	projectsDir := filepath.Join(userHome(), ".claude", "projects")
+	slug := strings.NewReplacer("/", "-", ".", "-").Replace(cwd)
+	isTranscript := func(path string, info os.FileInfo) bool {
+		return strings.HasSuffix(info.Name(), ".jsonl")
+	}
+	path, err := newestFile(filepath.Join(projectsDir, slug), isTranscript)
+	if err != nil {
+		// Another project's transcript would attach the wrong conversation
+		return "", fmt.Errorf("no Claude Code transcript found for %s", cwd)
+	}
+	return path, nil
+}
+
+func (claudeAdapter) ReadDelta(path string, offset int64) ([]Message, int64, error) {
+	return ComputeDelta(path, offset)
+}
+
+// newestFile returns the most recently modified file below root accepted by
+// match, or an error if there is none.
+func newestFile(root string, match func(path string, info os.FileInfo) bool) (string, error) {
+	var newest string
+	var newestInfo os.FileInfo
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if !match(path, info) {
+			return nil
+		}
+		if newestInfo == nil || info.ModTime().After(newestInfo.ModTime()) {
+			newest, newestInfo = path, info
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if newest == "" {
+		return "", fmt.Errorf("no transcript found in %s", root)
+	}
+	return newest, nil
+}
+
+// userHome returns the user's home directory
+func userHome() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return os.Getenv("HOME")
+	}
+	return home
+}
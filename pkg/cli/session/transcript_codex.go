@@ -0,0 +1,216 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// codexAdapter reads OpenAI Codex CLI rollout files from
+// $CODEX_HOME/sessions/YYYY/MM/DD/rollout-<timestamp>-<session-id>.jsonl.
+//
+// Codex has no tool hooks; its notify program is called with a JSON payload
+// after every agent turn, so captures are triggered by HEAD moving between
+// turns instead of by a git commit tool call.
+type codexAdapter struct{}
+
+// codexNotification is the payload Codex passes to its notify program
+type codexNotification struct {
+	Type     string `json:"type"`      // "agent-turn-complete"
+	ThreadID string `json:"thread-id"` // session ID
+	TurnID   string `json:"turn-id"`
+	Cwd      string `json:"cwd"`
+}
+
+// codexLine is a rollout line. Current versions wrap items in a payload;
+// older versions wrote response items directly.
+type codexLine struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	Role      string          `json:"role"`
+	Content   interface{}     `json:"content"`
+}
+
+type codexItem struct {
+	Type    string      `json:"type"`
+	Role    string      `json:"role"`
+	Content interface{} `json:"content"`
+	Cwd     string      `json:"cwd"` // session_meta only
+
+	// function_call, custom_tool_call and their outputs
+	Name      string `json:"name"`
+	CallID    string `json:"call_id"`
+	Arguments string `json:"arguments"` // JSON-encoded
+	Input     string `json:"input"`     // custom tools, e.g. apply_patch
+	Output    string `json:"output"`
+}
+
+// codexShellOutput is the JSON-encoded output of a shell function call
+type codexShellOutput struct {
+	Output   string `json:"output"`
+	Metadata struct {
+		ExitCode *int `json:"exit_code"`
+	} `json:"metadata"`
+}
+
+func (codexAdapter) Name() string { return AgentCodex }
+
+func (codexAdapter) ParseHookInput(data []byte) (*HookInput, error) {
+	var n codexNotification
+	if err := json.Unmarshal(data, &n); err != nil {
+		return nil, fmt.Errorf("failed to parse codex notification: %w", err)
+	}
+	cwd := n.Cwd
+	if cwd == "" {
+		cwd, _ = os.Getwd()
+	}
+	return &HookInput{
+		SessionID:     n.ThreadID,
+		Cwd:           cwd,
+		HookEventName: n.Type,
+		TurnComplete:  n.Type == "agent-turn-complete",
+	}, nil
+}
+
+func (codexAdapter) FindTranscript(sessionID, cwd string) (string, error) {
+	root := filepath.Join(codexHome(), "sessions")
+	if sessionID != "" {
+		return newestFile(root, func(path string, info os.FileInfo) bool {
+			return strings.HasSuffix(info.Name(), ".jsonl") && strings.Contains(info.Name(), sessionID)
+		})
+	}
+
+	// The newest rollout started in cwd; another directory's rollout would
+	// attach the wrong conversation
+	path, err := newestFile(root, func(path string, info os.FileInfo) bool {
+		return strings.HasPrefix(info.Name(), "rollout-") && strings.HasSuffix(info.Name(), ".jsonl") &&
+			codexRolloutCwd(path) == cwd
+	})
+	if err != nil {
+		return "", fmt.Errorf("no Codex rollout found for %s", cwd)
+	}
+	return path, nil
+}
+
+func (codexAdapter) ReadDelta(path string, offset int64) ([]Message, int64, error) {
+	return readJSONLDelta(path, offset, parseCodexLine)
+}
+
+// parseCodexLine converts user and assistant message items and tool calls
+// to messages
+func parseCodexLine(line []byte) *Message {
+	var cl codexLine
+	if err := json.Unmarshal(line, &cl); err != nil {
+		return nil
+	}
+
+	item := codexItem{Type: cl.Type, Role: cl.Role, Content: cl.Content}
+	if cl.Type == "response_item" {
+		if err := json.Unmarshal(cl.Payload, &item); err != nil {
+			return nil
+		}
+	}
+	timestamp := cl.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	switch item.Type {
+	case "function_call", "custom_tool_call":
+		return &Message{Role: "assistant", Timestamp: timestamp, ToolCalls: []ToolCall{codexToolCall(item)}}
+	case "function_call_output", "custom_tool_call_output":
+		return &Message{Role: "user", Timestamp: timestamp, toolResults: []toolResult{codexToolResult(item)}}
+	}
+
+	if item.Type != "message" || (item.Role != "user" && item.Role != "assistant") {
+		return nil
+	}
+
+	content := extractContent(item.Content)
+	// Skip context Codex injects as user messages
+	trimmed := strings.TrimSpace(content)
+	if content == "" || strings.HasPrefix(trimmed, "<environment_context>") || strings.HasPrefix(trimmed, "<user_instructions>") {
+		return nil
+	}
+
+	return &Message{Role: item.Role, Content: content, Timestamp: timestamp}
+}
+
+// codexToolCall converts a function or custom tool call. Shell commands
+// arrive as an argv array, usually ["bash", "-lc", "<script>"].
+func codexToolCall(item codexItem) ToolCall {
+	if item.Name == "apply_patch" {
+		patch := item.Input
+		if patch == "" {
+			patch, _ = decodeToolArguments(item.Arguments)["input"].(string)
+		}
+		return newPatchToolCall(item.CallID, item.Name, patch)
+	}
+
+	input := decodeToolArguments(item.Arguments)
+	if argv, ok := input["command"].([]interface{}); ok {
+		parts := make([]string, 0, len(argv))
+		for _, a := range argv {
+			if s, ok := a.(string); ok {
+				parts = append(parts, s)
+			}
+		}
+		if len(parts) == 3 && (parts[1] == "-lc" || parts[1] == "-c") {
+			input["command"] = parts[2]
+		} else {
+			input["command"] = strings.Join(parts, " ")
+		}
+	}
+	return newToolCall(item.CallID, item.Name, input)
+}
+
+// codexToolResult converts a call output, which for shell calls is a JSON
+// document carrying the exit code
+func codexToolResult(item codexItem) toolResult {
+	res := toolResult{ID: item.CallID, Output: item.Output}
+	var shell codexShellOutput
+	if err := json.Unmarshal([]byte(item.Output), &shell); err == nil && shell.Metadata.ExitCode != nil {
+		res.Output = shell.Output
+		res.ExitCode = shell.Metadata.ExitCode
+		res.IsError = *shell.Metadata.ExitCode != 0
+	}
+	return res
+}
+
+// codexRolloutCwd returns the working directory recorded in a rollout's
+// session_meta line, or "" if there is none.
+func codexRolloutCwd(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	if !scanner.Scan() {
+		return ""
+	}
+	var cl codexLine
+	if err := json.Unmarshal(scanner.Bytes(), &cl); err != nil || cl.Type != "session_meta" {
+		return ""
+	}
+	var meta codexItem
+	if err := json.Unmarshal(cl.Payload, &meta); err != nil {
+		return ""
+	}
+	return meta.Cwd
+}
+
+// codexHome returns $CODEX_HOME, defaulting to ~/.codex
+func codexHome() string {
+	if home := os.Getenv("CODEX_HOME"); home != "" {
+		return home
+	}
+	return filepath.Join(userHome(), ".codex")
+}
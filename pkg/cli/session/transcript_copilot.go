@@ -0,0 +1,114 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// copilotAdapter reads GitHub Copilot CLI session event logs from
+// ~/.copilot/session-state/<session-id>.jsonl. Its postToolUse hook (see
+// .github/hooks) reports tool calls with their arguments as a JSON string.
+type copilotAdapter struct{}
+
+// copilotHookInput is the postToolUse hook payload
+type copilotHookInput struct {
+	SessionID string          `json:"sessionId"`
+	Cwd       string          `json:"cwd"`
+	ToolName  string          `json:"toolName"`
+	ToolArgs  json.RawMessage `json:"toolArgs"` // JSON object, or a string containing one
+	Result    struct {
+		ResultType string `json:"resultType"` // success, failure, denied
+	} `json:"toolResult"`
+}
+
+// copilotEvent is a line of the session event log
+type copilotEvent struct {
+	Type      string    `json:"type"` // user.message, assistant.message, ...
+	Timestamp time.Time `json:"timestamp"`
+	Data      struct {
+		Content string `json:"content"`
+	} `json:"data"`
+}
+
+func (copilotAdapter) Name() string { return AgentCopilot }
+
+func (copilotAdapter) ParseHookInput(data []byte) (*HookInput, error) {
+	var in copilotHookInput
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("failed to parse copilot hook input: %w", err)
+	}
+
+	// toolArgs may be a JSON-encoded string
+	args := in.ToolArgs
+	var encoded string
+	if err := json.Unmarshal(args, &encoded); err == nil && strings.HasPrefix(strings.TrimSpace(encoded), "{") {
+		args = json.RawMessage(encoded)
+	}
+
+	toolName := in.ToolName
+	if toolName == "bash" || toolName == "shell" {
+		toolName = "Bash"
+	}
+
+	return &HookInput{
+		SessionID:     in.SessionID,
+		Cwd:           in.Cwd,
+		HookEventName: "postToolUse",
+		ToolName:      toolName,
+		ToolInput:     ToolInput{Raw: args},
+		ToolResponse:  ToolResponse{Interrupted: in.Result.ResultType != "" && in.Result.ResultType != "success"},
+	}, nil
+}
+
+// FindTranscript requires the session ID: event logs don't record the
+// directory they were started in, so the newest log may be another project's.
+func (copilotAdapter) FindTranscript(sessionID, cwd string) (string, error) {
+	if sessionID == "" {
+		return "", fmt.Errorf("copilot hook input has no session ID")
+	}
+	root := filepath.Join(copilotHome(), "session-state")
+	for _, candidate := range []string{
+		filepath.Join(root, sessionID+".jsonl"),
+		filepath.Join(root, sessionID, "events.jsonl"),
+	} {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("transcript not found for session %s", sessionID)
+}
+
+func (copilotAdapter) ReadDelta(path string, offset int64) ([]Message, int64, error) {
+	return readJSONLDelta(path, offset, func(line []byte) *Message {
+		var ev copilotEvent
+		if err := json.Unmarshal(line, &ev); err != nil || ev.Data.Content == "" {
+			return nil
+		}
+		var role string
+		switch ev.Type {
+		case "user.message":
+			role = "user"
+		case "assistant.message":
+			role = "assistant"
+		default:
+			return nil
+		}
+		timestamp := ev.Timestamp
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+		return &Message{Role: role, Content: ev.Data.Content, Timestamp: timestamp}
+	})
+}
+
+// copilotHome returns $COPILOT_HOME, defaulting to ~/.copilot
+func copilotHome() string {
+	if home := os.Getenv("COPILOT_HOME"); home != "" {
+		return home
+	}
+	return filepath.Join(userHome(), ".copilot")
+}
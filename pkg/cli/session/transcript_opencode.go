@@ -0,0 +1,188 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// openCodeAdapter reads OpenCode's JSON storage, where each message is a file
+// in storage/message/<session-id>/ and its text lives in
+// storage/part/<message-id>/. The transcript "path" is the session's message
+// directory and offsets count messages rather than bytes.
+//
+// OpenCode's hook is the plugin installed by 'sl auth hook --install --agent
+// opencode', which sends Claude-style hook JSON after each bash tool call.
+type openCodeAdapter struct{}
+
+type openCodeMessage struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+	Time struct {
+		Created int64 `json:"created"` // Unix milliseconds
+	} `json:"time"`
+}
+
+type openCodePart struct {
+	Type      string `json:"type"`
+	Text      string `json:"text"`
+	Synthetic bool   `json:"synthetic"`
+
+	// tool parts
+	CallID string `json:"callID"`
+	Tool   string `json:"tool"`
+	State  struct {
+		Status   string                 `json:"status"` // pending, running, completed, error
+		Input    map[string]interface{} `json:"input"`
+		Output   string                 `json:"output"`
+		Error    string                 `json:"error"`
+		Metadata struct {
+			Exit *int `json:"exit"`
+		} `json:"metadata"`
+	} `json:"state"`
+}
+
+// openCodeInputKeys maps OpenCode's camelCase tool inputs to the names
+// newToolCall understands
+var openCodeInputKeys = map[string]string{
+	"filePath":  "file_path",
+	"oldString": "old_string",
+	"newString": "new_string",
+}
+
+func (openCodeAdapter) Name() string { return AgentOpenCode }
+
+func (openCodeAdapter) ParseHookInput(data []byte) (*HookInput, error) {
+	return ParseHookInput(data)
+}
+
+// FindTranscript requires the session ID: message directories are shared by
+// all projects, so the newest one may be another project's.
+func (openCodeAdapter) FindTranscript(sessionID, cwd string) (string, error) {
+	if sessionID == "" {
+		return "", fmt.Errorf("opencode hook input has no session ID")
+	}
+	dir := filepath.Join(openCodeStorageDir(), "message", sessionID)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("transcript not found for session %s", sessionID)
+	}
+	return dir, nil
+}
+
+func (openCodeAdapter) ReadDelta(path string, offset int64) ([]Message, int64, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, offset, fmt.Errorf("failed to read transcript: %w", err)
+	}
+
+	var all []openCodeMessage
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var msg openCodeMessage
+		if err := json.Unmarshal(data, &msg); err != nil || msg.ID == "" {
+			continue
+		}
+		all = append(all, msg)
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Time.Created < all[j].Time.Created })
+
+	if offset > int64(len(all)) {
+		offset = 0 // Session was compacted or replaced; start over
+	}
+
+	partsRoot := filepath.Join(filepath.Dir(filepath.Dir(path)), "part")
+	var messages []Message
+	for _, msg := range all[offset:] {
+		if msg.Role != "user" && msg.Role != "assistant" {
+			continue
+		}
+		content, calls := openCodeMessageParts(filepath.Join(partsRoot, msg.ID))
+		if content == "" && len(calls) == 0 {
+			continue
+		}
+		messages = append(messages, Message{
+			Role:      msg.Role,
+			Content:   content,
+			Timestamp: time.UnixMilli(msg.Time.Created),
+			ToolCalls: calls,
+		})
+	}
+
+	return messages, int64(len(all)), nil
+}
+
+// openCodeMessageParts joins the text parts of a message in file order and
+// converts its tool parts, which carry their own results
+func openCodeMessageParts(dir string) (string, []ToolCall) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil
+	}
+	var texts []string
+	var calls []ToolCall
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var part openCodePart
+		if err := json.Unmarshal(data, &part); err != nil {
+			continue
+		}
+		switch {
+		case part.Type == "text" && !part.Synthetic && part.Text != "":
+			texts = append(texts, part.Text)
+		case part.Type == "tool":
+			calls = append(calls, openCodeToolCall(part))
+		}
+	}
+	return strings.Join(texts, "\n"), calls
+}
+
+// openCodeToolCall converts a tool part; tool names are lowercase ("bash",
+// "edit") and are capitalized to match other agents
+func openCodeToolCall(part openCodePart) ToolCall {
+	input := make(map[string]interface{}, len(part.State.Input))
+	for k, v := range part.State.Input {
+		if mapped, ok := openCodeInputKeys[k]; ok {
+			k = mapped
+		}
+		input[k] = v
+	}
+	name := part.Tool
+	if name != "" {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+
+	call := newToolCall(part.CallID, name, input)
+	switch part.State.Status {
+	case "completed":
+		call.Status = ToolStatusSuccess
+		call.Output = part.State.Output
+	case "error":
+		call.Status = ToolStatusError
+		call.Output = part.State.Error
+	}
+	call.ExitCode = part.State.Metadata.Exit
+	return call
+}
+
+// openCodeStorageDir returns OpenCode's storage directory under
+// $XDG_DATA_HOME (default ~/.local/share)
+func openCodeStorageDir() string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(userHome(), ".local", "share")
+	}
+	return filepath.Join(dataHome, "opencode", "storage")
+}
@@ -0,0 +1,195 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetTranscriptAdapter(t *testing.T) {
+	for _, name := range []string{"", AgentClaude, AgentOpenCode, AgentCopilot, AgentCodex} {
+		if _, err := GetTranscriptAdapter(name); err != nil {
+			t.Errorf("GetTranscriptAdapter(%q) error: %v", name, err)
+		}
+	}
+	if _, err := GetTranscriptAdapter("cursor"); err == nil {
+		t.Error("expected error for unsupported agent")
+	}
+}
+
+func TestCodexAdapter(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("CODEX_HOME", home)
+	adapter := codexAdapter{}
+
+	input, err := adapter.ParseHookInput([]byte(`{"type":"agent-turn-complete","thread-id":"abc-123","turn-id":"1","cwd":"/work"}`))
+	if err != nil {
+		t.Fatalf("ParseHookInput() error: %v", err)
+	}
+	if !input.TurnComplete || input.SessionID != "abc-123" || input.Cwd != "/work" {
+		t.Errorf("unexpected hook input: %+v", input)
+	}
+
+	dir := filepath.Join(home, "sessions", "2026", "01", "02")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	rollout := filepath.Join(dir, "rollout-2026-01-02T10-00-00-abc-123.jsonl")
+	lines := []string{
+		`{"timestamp":"2026-01-02T10:00:00Z","type":"session_meta","payload":{"id":"abc-123","cwd":"/work"}}`,
+		`{"timestamp":"2026-01-02T10:00:01Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"<environment_context>cwd</environment_context>"}]}}`,
+		`{"timestamp":"2026-01-02T10:00:02Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"add a test"}]}}`,
+		`{"timestamp":"2026-01-02T10:00:03Z","type":"response_item","payload":{"type":"function_call","name":"shell","call_id":"c1","arguments":"{\"command\":[\"ls\"]}"}}`,
+		`{"timestamp":"2026-01-02T10:00:04Z","type":"response_item","payload":{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Done."}]}}`,
+	}
+	if err := os.WriteFile(rollout, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sessionID := range []string{"abc-123", ""} {
+		path, err := adapter.FindTranscript(sessionID, "/work")
+		if err != nil || path != rollout {
+			t.Errorf("FindTranscript(%q) = %q, %v", sessionID, path, err)
+		}
+	}
+	if path, err := adapter.FindTranscript("", "/elsewhere"); err == nil {
+		t.Errorf("expected no rollout for /elsewhere, got %s", path)
+	}
+
+	messages, offset, err := adapter.ReadDelta(rollout, 0)
+	if err != nil {
+		t.Fatalf("ReadDelta() error: %v", err)
+	}
+	if len(messages) != 3 || messages[0].Content != "add a test" || messages[1].ToolCalls[0].Command != "ls" || messages[2].Content != "Done." {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+
+	again, _, err := adapter.ReadDelta(rollout, offset)
+	if err != nil || len(again) != 0 {
+		t.Errorf("expected empty delta after offset, got %+v, %v", again, err)
+	}
+}
+
+func TestCopilotAdapter(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("COPILOT_HOME", home)
+	adapter := copilotAdapter{}
+
+	input, err := adapter.ParseHookInput([]byte(`{"timestamp":1700000000000,"cwd":"/work","toolName":"bash","toolArgs":"{\"command\":\"git commit -m 'x'\"}","toolResult":{"resultType":"success"}}`))
+	if err != nil {
+		t.Fatalf("ParseHookInput() error: %v", err)
+	}
+	if input.ToolName != "Bash" || !IsGitCommit(input.ToolInput.Command()) || !input.ToolSuccess() {
+		t.Errorf("unexpected hook input: %+v (command %q)", input, input.ToolInput.Command())
+	}
+
+	failed, err := adapter.ParseHookInput([]byte(`{"toolName":"bash","toolArgs":{"command":"git commit"},"toolResult":{"resultType":"failure"}}`))
+	if err != nil {
+		t.Fatalf("ParseHookInput() error: %v", err)
+	}
+	if failed.ToolSuccess() || failed.ToolInput.Command() != "git commit" {
+		t.Errorf("expected failed tool with object args: %+v", failed)
+	}
+
+	dir := filepath.Join(home, "session-state")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	log := filepath.Join(dir, "s1.jsonl")
+	lines := []string{
+		`{"type":"session.start","data":{}}`,
+		`{"type":"user.message","data":{"content":"fix the bug"},"timestamp":"2026-01-02T10:00:00Z"}`,
+		`{"type":"tool.execution_start","data":{"toolName":"bash"}}`,
+		`{"type":"assistant.message","data":{"content":"Fixed."},"timestamp":"2026-01-02T10:00:05Z"}`,
+	}
+	if err := os.WriteFile(log, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if path, err := adapter.FindTranscript("", "/work"); err == nil {
+		t.Errorf("expected an error without a session ID, got %s", path)
+	}
+	path, err := adapter.FindTranscript("s1", "/work")
+	if err != nil || path != log {
+		t.Fatalf("FindTranscript() = %q, %v", path, err)
+	}
+	messages, _, err := adapter.ReadDelta(path, 0)
+	if err != nil {
+		t.Fatalf("ReadDelta() error: %v", err)
+	}
+	if len(messages) != 2 || messages[0].Role != "user" || messages[1].Content != "Fixed." {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+}
+
+func TestOpenCodeAdapter(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	storage := filepath.Join(dataHome, "opencode", "storage")
+
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(storage, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("message/ses_1/msg_a.json", `{"id":"msg_a","role":"user","time":{"created":1700000000000}}`)
+	write("message/ses_1/msg_b.json", `{"id":"msg_b","role":"assistant","time":{"created":1700000001000}}`)
+	write("part/msg_a/prt_1.json", `{"type":"text","text":"rename the flag"}`)
+	write("part/msg_b/prt_1.json", `{"type":"tool","callID":"call_1","tool":"edit","state":{"status":"completed","input":{"filePath":"main.go","oldString":"a","newString":"b"},"output":""}}`)
+	write("part/msg_b/prt_2.json", `{"type":"text","text":"Renamed."}`)
+
+	adapter := openCodeAdapter{}
+	if path, err := adapter.FindTranscript("", ""); err == nil {
+		t.Errorf("expected an error without a session ID, got %s", path)
+	}
+	path, err := adapter.FindTranscript("ses_1", "")
+	if err != nil {
+		t.Fatalf("FindTranscript() error: %v", err)
+	}
+
+	messages, offset, err := adapter.ReadDelta(path, 0)
+	if err != nil {
+		t.Fatalf("ReadDelta() error: %v", err)
+	}
+	if offset != 2 || len(messages) != 2 || messages[0].Content != "rename the flag" || messages[1].Content != "Renamed." {
+		t.Errorf("unexpected delta: offset=%d messages=%+v", offset, messages)
+	}
+	if calls := messages[1].ToolCalls; len(calls) != 1 || calls[0].Name != "Edit" || calls[0].FilePath != "main.go" || calls[0].DiffSummary != "+1 -1" || calls[0].Status != ToolStatusSuccess {
+		t.Errorf("unexpected tool calls: %+v", messages[1].ToolCalls)
+	}
+
+	write("message/ses_1/msg_c.json", `{"id":"msg_c","role":"user","time":{"created":1700000002000}}`)
+	write("part/msg_c/prt_1.json", `{"type":"text","text":"thanks"}`)
+	messages, offset, err = adapter.ReadDelta(path, offset)
+	if err != nil || offset != 3 || len(messages) != 1 || messages[0].Content != "thanks" {
+		t.Errorf("unexpected second delta: offset=%d messages=%+v err=%v", offset, messages, err)
+	}
+}
+
+func TestClaudeFindTranscriptStaysInProject(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	adapter, _ := GetTranscriptAdapter(AgentClaude)
+
+	other := filepath.Join(home, ".claude", "projects", "-other-app", "s1.jsonl")
+	if err := os.MkdirAll(filepath.Dir(other), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(other, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if path, err := adapter.FindTranscript("", "/work/app"); err == nil {
+		t.Errorf("expected no transcript for /work/app, got %s", path)
+	}
+	path, err := adapter.FindTranscript("", "/other/app")
+	if err != nil || path != other {
+		t.Errorf("FindTranscript() = %q, %v, want %s", path, err, other)
+	}
+}
@@ -19,39 +19,68 @@ const (
 	StatusAbandoned SessionStatus = "abandoned"
 )
 
+// SessionContentVersion is the schema version written by Capture.
+// Version 2.0 adds structured tool calls to messages; 1.0 sessions have text only.
+const SessionContentVersion = "2.0"
+
 // Message represents a single message in the conversation
 type Message struct {
-	Role      string    `json:"role"`      // "user" or "assistant"
-	Content   string    `json:"content"`   // message content
-	Timestamp time.Time `json:"timestamp"` // when the message was sent
+	Role      string     `json:"role"`                 // "user" or "assistant"
+	Content   string     `json:"content"`              // message content
+	Timestamp time.Time  `json:"timestamp"`            // when the message was sent
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"` // tools the agent invoked (v2)
+
+	// results carried by this message, moved onto their calls by attachToolResults
+	toolResults []toolResult
+}
+
+// Tool call statuses
+const (
+	ToolStatusSuccess = "success"
+	ToolStatusError   = "error"
+)
+
+// ToolCall is a tool invocation made by the agent, e.g. a shell command or a file edit
+type ToolCall struct {
+	ID          string `json:"id,omitempty"`           // agent's tool use ID
+	Name        string `json:"name"`                   // e.g. "Bash", "Edit", "shell"
+	Command     string `json:"command,omitempty"`      // shell command that was run
+	FilePath    string `json:"file_path,omitempty"`    // file read or edited
+	DiffSummary string `json:"diff_summary,omitempty"` // lines added/removed by an edit, e.g. "+12 -3"
+	Status      string `json:"status,omitempty"`       // success or error; empty if no result was recorded
+	ExitCode    *int   `json:"exit_code,omitempty"`    // shell exit status, when known
+	Output      string `json:"output,omitempty"`       // tool result, truncated
 }
 
 // SessionContent represents the full session data stored in Supabase Storage
 type SessionContent struct {
-	Version       string    `json:"version"`        // schema version (e.g., "1.0")
-	SessionID     string    `json:"session_id"`     // unique identifier
-	FeatureBranch string    `json:"feature_branch"` // e.g., "010-checkpoint-session-capture"
-	CommitHash    string    `json:"commit_hash"`    // git commit hash (nullable for task sessions)
-	TaskID        string    `json:"task_id"`        // task ID (nullable for commit sessions)
-	Author        string    `json:"author"`         // user email
-	CapturedAt    time.Time `json:"captured_at"`    // when captured
-	Messages      []Message `json:"messages"`       // conversation messages
+	Version       string         `json:"version"`              // schema version (see SessionContentVersion)
+	SessionID     string         `json:"session_id"`           // unique identifier
+	FeatureBranch string         `json:"feature_branch"`       // e.g., "010-checkpoint-session-capture"
+	CommitHash    string         `json:"commit_hash"`          // git commit hash (nullable for task sessions)
+	TaskID        string         `json:"task_id"`              // task ID (nullable for commit sessions)
+	Author        string         `json:"author"`               // user email
+	Agent         string         `json:"agent,omitempty"`      // coding agent that produced the transcript
+	CapturedAt    time.Time      `json:"captured_at"`          // when captured
+	Redactions    map[string]int `json:"redactions,omitempty"` // secrets replaced before upload, by rule
+	Messages      []Message      `json:"messages"`             // conversation messages
 }
 
 // SessionMetadata represents the queryable metadata stored in the database
 type SessionMetadata struct {
-	ID            string        `json:"id"`
-	ProjectID     string        `json:"project_id"`
-	FeatureBranch string        `json:"feature_branch"`
-	CommitHash    *string       `json:"commit_hash,omitempty"`
-	TaskID        *string       `json:"task_id,omitempty"`
-	AuthorID      string        `json:"author_id"`
-	StoragePath   string        `json:"storage_path"`
-	Status        SessionStatus `json:"status"`
-	SizeBytes     int64         `json:"size_bytes"`     // compressed size
-	RawSizeBytes  int64         `json:"raw_size_bytes"` // uncompressed size
-	MessageCount  int           `json:"message_count"`
-	CreatedAt     time.Time     `json:"created_at"`
+	ID            string         `json:"id"`
+	ProjectID     string         `json:"project_id"`
+	FeatureBranch string         `json:"feature_branch"`
+	CommitHash    *string        `json:"commit_hash,omitempty"`
+	TaskID        *string        `json:"task_id,omitempty"`
+	AuthorID      string         `json:"author_id"`
+	StoragePath   string         `json:"storage_path"`
+	Status        SessionStatus  `json:"status"`
+	SizeBytes     int64          `json:"size_bytes"`     // compressed size
+	RawSizeBytes  int64          `json:"raw_size_bytes"` // uncompressed size
+	MessageCount  int            `json:"message_count"`
+	Redactions    map[string]int `json:"redactions,omitempty"` // secrets replaced before upload, by rule; not in the sessions table
+	CreatedAt     time.Time      `json:"created_at"`
 }
 
 // ToolInput represents the tool_input field from Claude Code hooks.
@@ -106,6 +135,10 @@ type HookInput struct {
 	ToolInput      ToolInput    `json:"tool_input"`    // the command that was run
 	ToolResponse   ToolResponse `json:"tool_response"` // response from the tool
 	ToolUseID      string       `json:"tool_use_id"`   // unique ID for this tool use
+
+	// Set by the transcript adapter, not part of the hook JSON
+	Agent        string `json:"-"` // agent command that sent the hook, e.g. "codex"
+	TurnComplete bool   `json:"-"` // agent only reports finished turns, not tool calls
 }
 
 // ToolSuccess returns true if the tool executed successfully (no interruption)
@@ -127,16 +160,23 @@ type SessionOffsetInfo struct {
 
 // QueueEntry represents a session queued for upload
 type QueueEntry struct {
-	SessionID     string        `json:"session_id"`
-	ProjectID     string        `json:"project_id"`
-	FeatureBranch string        `json:"feature_branch"`
-	CommitHash    *string       `json:"commit_hash,omitempty"`
-	TaskID        *string       `json:"task_id,omitempty"`
-	AuthorID      string        `json:"author_id"`
-	Status        SessionStatus `json:"status"`
-	CreatedAt     time.Time     `json:"created_at"`
-	RetryCount    int           `json:"retry_count"`
-	LastRetry     *time.Time    `json:"last_retry,omitempty"`
+	SessionID     string         `json:"session_id"`
+	ProjectID     string         `json:"project_id"`
+	Backend       string         `json:"backend,omitempty"` // backend the session was captured for; empty is Supabase
+	Workdir       string         `json:"workdir,omitempty"` // project directory the session was captured in
+	FeatureBranch string         `json:"feature_branch"`
+	CommitHash    *string        `json:"commit_hash,omitempty"`
+	TaskID        *string        `json:"task_id,omitempty"`
+	AuthorID      string         `json:"author_id"`
+	Status        SessionStatus  `json:"status"`
+	CreatedAt     time.Time      `json:"created_at"`
+	RetryCount    int            `json:"retry_count"`
+	LastRetry     *time.Time     `json:"last_retry,omitempty"`
+	NextRetry     *time.Time     `json:"next_retry,omitempty"` // not retried before this time
+	LastError     string         `json:"last_error,omitempty"` // error of the last failed upload
+	MessageCount  int            `json:"message_count,omitempty"`
+	RawSizeBytes  int64          `json:"raw_size_bytes,omitempty"`
+	Redactions    map[string]int `json:"redactions,omitempty"`
 }
 
 // TranscriptLine represents a single line from the Claude Code transcript JSONL
@@ -157,12 +197,14 @@ type TranscriptMsg struct {
 
 // CaptureResult represents the outcome of a capture operation
 type CaptureResult struct {
-	Captured     bool   // whether a session was captured
-	SessionID    string // the session ID if captured
-	StoragePath  string // path in storage
-	MessageCount int    // number of messages in the session
-	SizeBytes    int64  // compressed size
-	RawSizeBytes int64  // uncompressed size
-	Queued       bool   // whether it was queued for later upload
-	Error        error  // any error that occurred
+	Captured     bool            // whether a session was captured
+	SessionID    string          // the session ID if captured
+	StoragePath  string          // path in storage
+	MessageCount int             // number of messages in the session
+	SizeBytes    int64           // compressed size
+	RawSizeBytes int64           // uncompressed size
+	Redactions   RedactionCounts // secrets replaced before upload, by rule
+	TaskID       string          // issue the session was linked to, if any
+	Queued       bool            // whether it was queued for later upload
+	Error        error           // any error that occurred
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // CacheDir returns the global cache directory for SpecLedger dependencies.
@@ -39,7 +40,15 @@ func CachePathForDependency(alias, url string) (string, error) {
 
 // generateDirName generates a directory name from a Git URL.
 func generateDirName(url string) string {
-	// This is a placeholder - the actual implementation in pkg/cli/commands/deps.go
-	// has the full logic. This can be refactored later to share code.
-	return "dep"
+	// Remove protocol and domain prefix, extract repo path
+	url = strings.TrimPrefix(url, "https://")
+	url = strings.TrimPrefix(url, "http://")
+	url = strings.TrimPrefix(url, "git@")
+
+	// Replace : and / with -
+	url = strings.ReplaceAll(url, ":", "-")
+	url = strings.ReplaceAll(url, "/", "-")
+
+	// Remove .git suffix if present
+	return strings.TrimSuffix(url, ".git")
 }
@@ -0,0 +1,236 @@
+package deps
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// ConflictKind classifies a problem found in the dependency graph.
+type ConflictKind string
+
+const (
+	ConflictDuplicate ConflictKind = "duplicate" // Same repository or alias declared twice
+	ConflictCycle     ConflictKind = "cycle"     // Dependency chain leads back to an earlier node
+	ConflictBranch    ConflictKind = "branch"    // Same repository required at different branches
+)
+
+// Conflict describes a single problem found by CheckConflicts.
+type Conflict struct {
+	Kind    ConflictKind
+	Message string
+}
+
+// UpstreamLoader returns the dependencies declared by a dependency's own
+// specledger.yaml. Implementations should return (nil, nil) when the
+// upstream is not available locally or is not a SpecLedger repository.
+type UpstreamLoader func(dep metadata.Dependency) ([]metadata.Dependency, error)
+
+// LoadUpstreamDependencies reads the dependencies declared in the
+// specledger.yaml of a locally available repository.
+// Returns (nil, nil) if the repository has no specledger.yaml.
+func LoadUpstreamDependencies(repoPath string) ([]metadata.Dependency, error) {
+	if !metadata.HasYAMLMetadata(repoPath) {
+		return nil, nil
+	}
+	meta, err := metadata.Load(filepath.Join(repoPath, metadata.DefaultMetadataFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upstream specledger.yaml: %w", err)
+	}
+	return meta.Dependencies, nil
+}
+
+// NormalizeURL reduces a repository URL to a comparable form so that
+// git@host:org/repo.git and https://host/org/repo refer to the same node.
+func NormalizeURL(url string) string {
+	u := strings.TrimSpace(url)
+	u = strings.TrimPrefix(u, "https://")
+	u = strings.TrimPrefix(u, "http://")
+	u = strings.TrimPrefix(u, "ssh://")
+	if strings.HasPrefix(u, "git@") {
+		u = strings.Replace(strings.TrimPrefix(u, "git@"), ":", "/", 1)
+	}
+	u = strings.TrimSuffix(u, "/")
+	u = strings.TrimSuffix(u, ".git")
+	return strings.ToLower(u)
+}
+
+// CheckConflicts inspects the project's declared dependencies and, through
+// load, their transitive dependencies. It reports duplicate declarations,
+// dependency cycles and repositories required at conflicting branches.
+//
+// rootURL is the project's own repository URL (may be empty); it is used to
+// detect upstreams that depend back on the project.
+func CheckConflicts(rootURL string, dependencies []metadata.Dependency, load UpstreamLoader) []Conflict {
+	var conflicts []Conflict
+	conflicts = append(conflicts, checkDuplicates(dependencies)...)
+
+	root := NormalizeURL(rootURL)
+	if root == "" {
+		root = "<project>"
+	}
+
+	// Walk the graph breadth-first, recording edges and requested branches.
+	edges := make(map[string][]string)
+	branches := make(map[string]map[string][]string) // url -> branch -> requested by
+	visited := map[string]bool{root: true}
+
+	type node struct {
+		url  string
+		deps []metadata.Dependency
+	}
+	queue := []node{{url: root, deps: dependencies}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dep := range current.deps {
+			target := NormalizeURL(dep.URL)
+			edges[current.url] = append(edges[current.url], target)
+
+			// Local sources have no branch to conflict on
+			if !IsLocalSource(dep.URL) {
+				branch := dep.Branch
+				if branch == "" {
+					branch = "main"
+				}
+				if branches[target] == nil {
+					branches[target] = make(map[string][]string)
+				}
+				branches[target][branch] = append(branches[target][branch], current.url)
+			}
+
+			if visited[target] || load == nil {
+				continue
+			}
+			visited[target] = true
+
+			upstream, err := load(dep)
+			if err != nil || len(upstream) == 0 {
+				continue
+			}
+			queue = append(queue, node{url: target, deps: upstream})
+		}
+	}
+
+	conflicts = append(conflicts, findCycles(root, edges)...)
+	conflicts = append(conflicts, branchConflicts(branches)...)
+	return conflicts
+}
+
+// checkDuplicates reports repositories or aliases declared more than once.
+func checkDuplicates(dependencies []metadata.Dependency) []Conflict {
+	var conflicts []Conflict
+	urls := make(map[string]string)
+	aliases := make(map[string]string)
+
+	for _, dep := range dependencies {
+		key := NormalizeURL(dep.URL)
+		if first, ok := urls[key]; ok {
+			conflicts = append(conflicts, Conflict{
+				Kind:    ConflictDuplicate,
+				Message: fmt.Sprintf("repository declared twice: %s and %s", first, dep.URL),
+			})
+		} else {
+			urls[key] = dep.URL
+		}
+
+		if dep.Alias == "" {
+			continue
+		}
+		if first, ok := aliases[dep.Alias]; ok {
+			conflicts = append(conflicts, Conflict{
+				Kind:    ConflictDuplicate,
+				Message: fmt.Sprintf("alias %q used by both %s and %s", dep.Alias, first, dep.URL),
+			})
+		} else {
+			aliases[dep.Alias] = dep.URL
+		}
+	}
+
+	return conflicts
+}
+
+// findCycles runs a depth-first search from root and reports each back edge
+// as a cycle, rendered as "a -> b -> a".
+func findCycles(root string, edges map[string][]string) []Conflict {
+	const (
+		white = iota
+		grey
+		black
+	)
+	color := make(map[string]int)
+	var stack []string
+	var conflicts []Conflict
+	seen := make(map[string]bool)
+
+	var visit func(n string)
+	visit = func(n string) {
+		color[n] = grey
+		stack = append(stack, n)
+		for _, next := range edges[n] {
+			switch color[next] {
+			case white:
+				visit(next)
+			case grey:
+				start := 0
+				for i, s := range stack {
+					if s == next {
+						start = i
+						break
+					}
+				}
+				cycle := append(append([]string{}, stack[start:]...), next)
+				path := strings.Join(cycle, " -> ")
+				if !seen[path] {
+					seen[path] = true
+					conflicts = append(conflicts, Conflict{
+						Kind:    ConflictCycle,
+						Message: path,
+					})
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		color[n] = black
+	}
+	visit(root)
+
+	return conflicts
+}
+
+// branchConflicts reports repositories that are required at more than one branch.
+func branchConflicts(branches map[string]map[string][]string) []Conflict {
+	urls := make([]string, 0, len(branches))
+	for url := range branches {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+
+	var conflicts []Conflict
+	for _, url := range urls {
+		byBranch := branches[url]
+		if len(byBranch) < 2 {
+			continue
+		}
+		names := make([]string, 0, len(byBranch))
+		for branch := range byBranch {
+			names = append(names, branch)
+		}
+		sort.Strings(names)
+
+		parts := make([]string, 0, len(names))
+		for _, branch := range names {
+			parts = append(parts, fmt.Sprintf("%s (required by %s)", branch, strings.Join(byBranch[branch], ", ")))
+		}
+		conflicts = append(conflicts, Conflict{
+			Kind:    ConflictBranch,
+			Message: fmt.Sprintf("%s requested at %s", url, strings.Join(parts, "; ")),
+		})
+	}
+
+	return conflicts
+}
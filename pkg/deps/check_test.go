@@ -0,0 +1,120 @@
+package deps
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"git@github.com:org/spec.git", "github.com/org/spec"},
+		{"https://github.com/org/spec", "github.com/org/spec"},
+		{"https://github.com/Org/Spec.git/", "github.com/org/spec"},
+		{"ssh://git@github.com/org/spec.git", "github.com/org/spec"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeURL(tt.in); got != tt.want {
+			t.Errorf("NormalizeURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCheckConflicts(t *testing.T) {
+	upstreams := map[string][]metadata.Dependency{
+		"github.com/org/a": {
+			{URL: "https://github.com/org/b", Branch: "main", Alias: "b"},
+		},
+		"github.com/org/b": {
+			{URL: "git@github.com:org/a.git", Branch: "main", Alias: "a"},
+			{URL: "https://github.com/org/c", Branch: "develop", Alias: "c"},
+		},
+	}
+	load := func(dep metadata.Dependency) ([]metadata.Dependency, error) {
+		return upstreams[NormalizeURL(dep.URL)], nil
+	}
+
+	tests := []struct {
+		name  string
+		deps  []metadata.Dependency
+		load  UpstreamLoader
+		kinds map[ConflictKind]int
+	}{
+		{
+			name: "no conflicts",
+			deps: []metadata.Dependency{
+				{URL: "https://github.com/org/x", Alias: "x"},
+				{URL: "https://github.com/org/y", Alias: "y"},
+			},
+			load:  load,
+			kinds: map[ConflictKind]int{},
+		},
+		{
+			name: "duplicate url and alias",
+			deps: []metadata.Dependency{
+				{URL: "https://github.com/org/x", Alias: "x"},
+				{URL: "git@github.com:org/x.git", Alias: "x2"},
+				{URL: "https://github.com/org/y", Alias: "x"},
+			},
+			load:  load,
+			kinds: map[ConflictKind]int{ConflictDuplicate: 2},
+		},
+		{
+			name: "transitive cycle and branch conflict",
+			deps: []metadata.Dependency{
+				{URL: "https://github.com/org/a", Alias: "a"},
+				{URL: "https://github.com/org/c", Branch: "main", Alias: "c"},
+			},
+			load:  load,
+			kinds: map[ConflictKind]int{ConflictCycle: 1, ConflictBranch: 1},
+		},
+		{
+			name: "nil loader ignores transitive graph",
+			deps: []metadata.Dependency{
+				{URL: "https://github.com/org/a", Alias: "a"},
+			},
+			load:  nil,
+			kinds: map[ConflictKind]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts := CheckConflicts("", tt.deps, tt.load)
+			got := make(map[ConflictKind]int)
+			for _, c := range conflicts {
+				got[c.Kind]++
+			}
+			if len(got) != len(tt.kinds) {
+				t.Fatalf("conflicts = %+v, want kinds %v", conflicts, tt.kinds)
+			}
+			for kind, n := range tt.kinds {
+				if got[kind] != n {
+					t.Errorf("%s conflicts = %d, want %d (%+v)", kind, got[kind], n, conflicts)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckConflicts_CycleThroughProject(t *testing.T) {
+	load := func(dep metadata.Dependency) ([]metadata.Dependency, error) {
+		return []metadata.Dependency{{URL: "git@github.com:org/self.git", Alias: "self"}}, nil
+	}
+
+	conflicts := CheckConflicts("https://github.com/org/self", []metadata.Dependency{
+		{URL: "https://github.com/org/a", Alias: "a"},
+	}, load)
+
+	if len(conflicts) != 1 || conflicts[0].Kind != ConflictCycle {
+		t.Fatalf("expected one cycle, got %+v", conflicts)
+	}
+	if !strings.HasPrefix(conflicts[0].Message, "github.com/org/self -> github.com/org/a") {
+		t.Errorf("unexpected cycle message: %s", conflicts[0].Message)
+	}
+}
@@ -0,0 +1,241 @@
+package deps
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// ArtifactKind classifies a file inside a dependency's artifact_path.
+type ArtifactKind string
+
+const (
+	ArtifactSpec      ArtifactKind = "spec"
+	ArtifactPlan      ArtifactKind = "plan"
+	ArtifactDataModel ArtifactKind = "data-model"
+	ArtifactContract  ArtifactKind = "contract"
+	ArtifactOther     ArtifactKind = "other"
+)
+
+// ChangeAction describes how an artifact changed between two commits.
+type ChangeAction string
+
+const (
+	ChangeAdded    ChangeAction = "added"
+	ChangeModified ChangeAction = "modified"
+	ChangeDeleted  ChangeAction = "deleted"
+)
+
+// ArtifactChange is a file under artifact_path that changed between two commits.
+type ArtifactChange struct {
+	Path      string       `json:"path"`    // Relative to the dependency's artifact_path
+	Feature   string       `json:"feature"` // First path segment, e.g. "001-auth"
+	Kind      ArtifactKind `json:"kind"`
+	Action    ChangeAction `json:"action"`
+	Additions int          `json:"additions"`
+	Deletions int          `json:"deletions"`
+}
+
+// ArtifactDiff summarizes what changed in a dependency between two commits.
+type ArtifactDiff struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	Commits []CommitSummary  `json:"commits"`
+	Changes []ArtifactChange `json:"changes"`
+}
+
+// SpecChanges returns the changes to spec, plan, data-model and contract
+// artifacts, i.e. everything except ArtifactOther.
+func (d *ArtifactDiff) SpecChanges() []ArtifactChange {
+	var out []ArtifactChange
+	for _, c := range d.Changes {
+		if c.Kind != ArtifactOther {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Features returns the sorted list of feature directories touched by the diff.
+func (d *ArtifactDiff) Features() []string {
+	seen := make(map[string]bool)
+	var features []string
+	for _, c := range d.Changes {
+		if c.Feature != "" && !seen[c.Feature] {
+			seen[c.Feature] = true
+			features = append(features, c.Feature)
+		}
+	}
+	sort.Strings(features)
+	return features
+}
+
+// DiffArtifacts compares the trees of two commits and returns the files
+// under artifactPath that changed, together with the commit log between them.
+// Both commits must already be present in the local repository.
+func DiffArtifacts(repo *git.Repository, from, to, artifactPath string) (*ArtifactDiff, error) {
+	fromTree, err := commitTree(repo, from)
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := commitTree(repo, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff trees: %w", err)
+	}
+
+	prefix := cleanRepoPath(artifactPath)
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	diff := &ArtifactDiff{From: from, To: to}
+	var numstat map[string][2]int // from the git CLI, when go-git lacks the blobs
+	for _, change := range changes {
+		name := change.To.Name
+		if name == "" {
+			name = change.From.Name
+		}
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		rel := strings.TrimPrefix(name, prefix)
+
+		ac := ArtifactChange{
+			Path: rel,
+			Kind: ClassifyArtifact(rel),
+		}
+		if feature, _, ok := strings.Cut(rel, "/"); ok {
+			ac.Feature = feature
+		}
+
+		action, err := change.Action()
+		if err != nil {
+			return nil, fmt.Errorf("failed to classify change to %s: %w", name, err)
+		}
+		switch action {
+		case merkletrie.Insert:
+			ac.Action = ChangeAdded
+		case merkletrie.Delete:
+			ac.Action = ChangeDeleted
+		default:
+			ac.Action = ChangeModified
+		}
+
+		patch, err := change.Patch()
+		if err != nil {
+			// Sparse and partial caches lack blobs outside the checkout;
+			// the git CLI fetches them on demand
+			if numstat == nil {
+				numstat, err = gitNumstat(repo, from, to, prefix)
+				if err != nil {
+					return nil, fmt.Errorf("failed to diff %s: %w", name, err)
+				}
+			}
+			stat := numstat[name]
+			ac.Additions, ac.Deletions = stat[0], stat[1]
+		} else {
+			for _, stat := range patch.Stats() {
+				ac.Additions += stat.Addition
+				ac.Deletions += stat.Deletion
+			}
+		}
+
+		diff.Changes = append(diff.Changes, ac)
+	}
+
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		return diff.Changes[i].Path < diff.Changes[j].Path
+	})
+
+	diff.Commits, err = Log(repo, from, to, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return diff, nil
+}
+
+// gitNumstat returns the lines added and deleted per file under prefix
+// between two commits, using the git CLI. Binary files count as zero.
+func gitNumstat(repo *git.Repository, from, to, prefix string) (map[string][2]int, error) {
+	dir := repoDir(repo)
+	if dir == "" {
+		return nil, fmt.Errorf("repository has no worktree")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, ErrGitCLIUnavailable
+	}
+	args := []string{"-C", dir, "diff", "--numstat", "--no-renames", from, to}
+	if prefix != "" {
+		args = append(args, "--", prefix)
+	}
+	// #nosec G204 -- arguments are commit SHAs and the dependency's artifact path
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %w", err)
+	}
+
+	stats := make(map[string][2]int)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		added, _ := strconv.Atoi(fields[0]) // "-" for binary files
+		deleted, _ := strconv.Atoi(fields[1])
+		stats[fields[2]] = [2]int{added, deleted}
+	}
+	return stats, nil
+}
+
+// ClassifyArtifact returns the kind of a path relative to artifact_path.
+func ClassifyArtifact(rel string) ArtifactKind {
+	switch path.Base(rel) {
+	case "spec.md":
+		return ArtifactSpec
+	case "plan.md":
+		return ArtifactPlan
+	case "data-model.md":
+		return ArtifactDataModel
+	}
+	for _, segment := range strings.Split(path.Dir(rel), "/") {
+		if segment == "contracts" {
+			return ArtifactContract
+		}
+	}
+	return ArtifactOther
+}
+
+// commitTree returns the tree of the commit identified by sha.
+func commitTree(repo *git.Repository, sha string) (*object.Tree, error) {
+	commit, err := repo.CommitObject(plumbing.NewHash(sha))
+	if err != nil {
+		return nil, fmt.Errorf("commit %s not found: %w", shortSHA(sha), err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree of %s: %w", shortSHA(sha), err)
+	}
+	return tree, nil
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
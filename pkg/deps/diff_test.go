@@ -0,0 +1,168 @@
+package deps
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// commitFiles writes files into the worktree (nil content deletes) and commits them.
+func commitFiles(t *testing.T, repo *git.Repository, dir string, files map[string][]byte, msg string) string {
+	t.Helper()
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if content == nil {
+			if _, err := wt.Remove(name); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash, err := wt.Commit(msg, &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash.String()
+}
+
+func TestDiffArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := commitFiles(t, repo, dir, map[string][]byte{
+		"specledger/001-auth/spec.md":                []byte("# Auth\n"),
+		"specledger/001-auth/plan.md":                []byte("# Plan\n"),
+		"specledger/002-billing/data-model.md":       []byte("# Model\n"),
+		"specledger/002-billing/contracts/api.yaml":  []byte("openapi: 3.0.0\n"),
+		"specledger/002-billing/contracts/old.proto": []byte("syntax = \"proto3\";\n"),
+		"cmd/main.go": []byte("package main\n"),
+	}, "initial")
+
+	to := commitFiles(t, repo, dir, map[string][]byte{
+		"specledger/001-auth/spec.md":                []byte("# Auth\n\nNew requirement\n"),
+		"specledger/002-billing/contracts/api.yaml":  []byte("openapi: 3.1.0\n"),
+		"specledger/002-billing/contracts/old.proto": nil,
+		"specledger/003-search/research.md":          []byte("# Research\n"),
+		"cmd/main.go":                                []byte("package main\n\nfunc main() {}\n"),
+	}, "Update auth spec\n\nLonger body")
+
+	diff, err := DiffArtifacts(repo, from, to, "specledger/")
+	if err != nil {
+		t.Fatalf("DiffArtifacts() error = %v", err)
+	}
+
+	want := map[string]struct {
+		kind   ArtifactKind
+		action ChangeAction
+	}{
+		"001-auth/spec.md":                {ArtifactSpec, ChangeModified},
+		"002-billing/contracts/api.yaml":  {ArtifactContract, ChangeModified},
+		"002-billing/contracts/old.proto": {ArtifactContract, ChangeDeleted},
+		"003-search/research.md":          {ArtifactOther, ChangeAdded},
+	}
+	if len(diff.Changes) != len(want) {
+		t.Fatalf("got %d changes, want %d: %+v", len(diff.Changes), len(want), diff.Changes)
+	}
+	for _, c := range diff.Changes {
+		w, ok := want[c.Path]
+		if !ok {
+			t.Errorf("unexpected change %s", c.Path)
+			continue
+		}
+		if c.Kind != w.kind || c.Action != w.action {
+			t.Errorf("%s: got %s/%s, want %s/%s", c.Path, c.Kind, c.Action, w.kind, w.action)
+		}
+	}
+
+	if got := len(diff.SpecChanges()); got != 3 {
+		t.Errorf("SpecChanges() = %d, want 3", got)
+	}
+	if got := diff.Features(); len(got) != 3 || got[0] != "001-auth" {
+		t.Errorf("Features() = %v", got)
+	}
+	if len(diff.Commits) != 1 || diff.Commits[0].Subject != "Update auth spec" {
+		t.Errorf("Commits = %+v, want single 'Update auth spec'", diff.Commits)
+	}
+}
+
+func TestClassifyArtifact(t *testing.T) {
+	tests := map[string]ArtifactKind{
+		"001-auth/spec.md":            ArtifactSpec,
+		"001-auth/plan.md":            ArtifactPlan,
+		"001-auth/data-model.md":      ArtifactDataModel,
+		"001-auth/contracts/api.yaml": ArtifactContract,
+		"001-auth/tasks.md":           ArtifactOther,
+	}
+	for path, want := range tests {
+		if got := ClassifyArtifact(path); got != want {
+			t.Errorf("ClassifyArtifact(%q) = %s, want %s", path, got, want)
+		}
+	}
+}
+
+func TestDiffArtifacts_PartialClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git CLI not available")
+	}
+
+	src := t.TempDir()
+	repo, err := git.PlainInit(src, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := commitFiles(t, repo, src, map[string][]byte{
+		"specs/001-auth/spec.md": []byte("# Auth\n"),
+	}, "initial")
+	to := commitFiles(t, repo, src, map[string][]byte{
+		"specs/001-auth/spec.md": []byte("# Auth\n\nNew requirement\n"),
+	}, "Update auth spec")
+	ref, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runGit(src, "config", "uploadpack.allowFilter", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The blob-less clone lacks the old spec.md, which go-git can't fetch
+	cloned, _, err := Clone(CloneOptions{
+		URL:         "file://" + src,
+		Branch:      ref.Name().Short(),
+		TargetDir:   filepath.Join(t.TempDir(), "cache"),
+		SparsePaths: SparsePathsForDependency("specs/"),
+	})
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+
+	diff, err := DiffArtifacts(cloned, from, to, "specs/")
+	if err != nil {
+		t.Fatalf("DiffArtifacts() error = %v", err)
+	}
+	if len(diff.Changes) != 1 || diff.Changes[0].Additions != 2 || diff.Changes[0].Deletions != 0 {
+		t.Errorf("Changes = %+v, want 001-auth/spec.md +2 -0", diff.Changes)
+	}
+}
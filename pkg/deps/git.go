@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -19,6 +21,11 @@ type CloneOptions struct {
 	Branch    string // Branch to clone (default "main")
 	TargetDir string // Directory to clone to
 	Shallow   bool   // Whether to do a shallow clone
+
+	// SparsePaths limits the clone to these repository-relative paths using a
+	// blob-less partial clone and sparse checkout. Directories end with "/".
+	// Requires the git CLI; see SparsePathsForDependency.
+	SparsePaths []string
 }
 
 // Clone clones a Git repository using go-git/v5.
@@ -35,6 +42,23 @@ func Clone(opts CloneOptions) (*git.Repository, string, error) {
 		branch = "main"
 	}
 
+	if len(opts.SparsePaths) > 0 {
+		if _, err := os.Stat(opts.TargetDir); os.IsNotExist(err) {
+			if err := sparseClone(opts, branch); err != nil {
+				return nil, "", fmt.Errorf("failed to clone repository: %w", err)
+			}
+		}
+		repo, err := git.PlainOpen(opts.TargetDir)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to open repository: %w", err)
+		}
+		commitSHA, err := ResolveHead(repo)
+		if err != nil {
+			return nil, "", err
+		}
+		return repo, commitSHA, nil
+	}
+
 	// Clone options
 	cloneOpts := &git.CloneOptions{
 		URL:          opts.URL,
@@ -102,6 +126,10 @@ func OpenRepository(path string) (*git.Repository, error) {
 
 // Fetch fetches the latest changes from a repository.
 func Fetch(repo *git.Repository, branch string) error {
+	if IsSparse(repo) {
+		return sparseFetch(repoDir(repo))
+	}
+
 	// Get the remote
 	remotes, err := repo.Remotes()
 	if err != nil || len(remotes) == 0 {
@@ -137,6 +165,10 @@ func Fetch(repo *git.Repository, branch string) error {
 
 // Checkout checks out a specific commit or branch in a repository.
 func Checkout(repo *git.Repository, ref string) error {
+	if IsSparse(repo) {
+		return sparseCheckout(repoDir(repo), ref)
+	}
+
 	worktree, err := repo.Worktree()
 	if err != nil {
 		return fmt.Errorf("failed to get worktree: %w", err)
@@ -192,6 +224,12 @@ func Pull(repo *git.Repository, branch string) (string, error) {
 	}
 
 	// Checkout the remote branch
+	if IsSparse(repo) {
+		if err := sparseCheckout(repoDir(repo), remoteHash.String()); err != nil {
+			return "", fmt.Errorf("failed to checkout remote branch: %w", err)
+		}
+		return remoteHash.String(), nil
+	}
 	if err := worktree.Checkout(&git.CheckoutOptions{
 		Hash:  *remoteHash,
 		Force: true,
@@ -234,27 +272,28 @@ func ResolveRemoteCommit(repo *git.Repository, branch string) (string, error) {
 	return hash.String(), nil
 }
 
-// Log returns commit log between two revisions.
-// limit specifies the maximum number of commits to return (0 for unlimited).
-func Log(repo *git.Repository, from, to string, limit int) (string, error) {
-	// Parse the commit hashes
-	toHash := plumbing.NewHash(to)
+// CommitSummary is a single entry of a dependency's commit log.
+type CommitSummary struct {
+	Hash    string    `json:"hash"`
+	Subject string    `json:"subject"`
+	Author  string    `json:"author"`
+	When    time.Time `json:"when"`
+}
 
-	// Get the commit iterator
+// Log returns the commits reachable from to but not past from, newest
+// first. limit specifies the maximum number of commits to return (0 for
+// unlimited).
+func Log(repo *git.Repository, from, to string, limit int) ([]CommitSummary, error) {
 	commitIter, err := repo.Log(&git.LogOptions{
-		From:  toHash,
+		From:  plumbing.NewHash(to),
 		Order: git.LogOrderCommitterTime,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to get commit log: %w", err)
+		return nil, fmt.Errorf("failed to get commit log: %w", err)
 	}
 	defer commitIter.Close()
 
-	// Collect commits
-	var commits []string
-	count := 0
-
-	// Iterate through commits
+	var commits []CommitSummary
 	for {
 		commit, err := commitIter.Next()
 		if err != nil {
@@ -266,31 +305,20 @@ func Log(repo *git.Repository, from, to string, limit int) (string, error) {
 			break
 		}
 
-		// Add commit to list
-		commits = append(commits, fmt.Sprintf("%s %s", commit.Hash.String()[:8], commit.Message))
-		count++
+		subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+		commits = append(commits, CommitSummary{
+			Hash:    commit.Hash.String(),
+			Subject: subject,
+			Author:  commit.Author.Name,
+			When:    commit.Author.When,
+		})
 
-		// Check limit
-		if limit > 0 && count >= limit {
+		if limit > 0 && len(commits) >= limit {
 			break
 		}
 	}
 
-	// If we didn't find any commits
-	if len(commits) == 0 {
-		return "", fmt.Errorf("no commits found")
-	}
-
-	// Join commits with newlines
-	result := ""
-	for i, commit := range commits {
-		if i > 0 {
-			result += "\n"
-		}
-		result += commit
-	}
-
-	return result, nil
+	return commits, nil
 }
 
 // getAuthForURL determines the appropriate authentication method for a Git URL.
@@ -0,0 +1,87 @@
+package deps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// IsLocalSource reports whether url refers to a directory on disk (a relative
+// or absolute path, or a file:// URL) rather than a remote git repository.
+// Local sources are linked directly and never cloned.
+func IsLocalSource(url string) bool {
+	return strings.HasPrefix(url, "file://") ||
+		strings.HasPrefix(url, "/") ||
+		strings.HasPrefix(url, "./") ||
+		strings.HasPrefix(url, "../") ||
+		filepath.IsAbs(url)
+}
+
+// LocalSourcePath resolves a local source to an absolute directory.
+// Relative paths are resolved against projectRoot, so a dependency such as
+// "../platform-specs" works for every checkout of a monorepo.
+func LocalSourcePath(url, projectRoot string) (string, error) {
+	path := strings.TrimPrefix(url, "file://")
+	if path == "" {
+		return "", fmt.Errorf("empty local path")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectRoot, path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("local source not found: %s", path)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("local source is not a directory: %s", path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// SourceKind describes where a dependency's contents are read from.
+type SourceKind string
+
+const (
+	SourceCache    SourceKind = "cache"    // Cloned into the global cache
+	SourceLocal    SourceKind = "local"    // Local path or file:// URL in specledger.yaml
+	SourceReplaced SourceKind = "replaced" // Overridden in specledger.local.yaml
+)
+
+// ResolveSource returns the directory holding a dependency's repository
+// contents and how it was found. A replace override for the dependency's
+// alias takes precedence, then a local source URL, then the global cache.
+// The directory is not checked for existence in the cache case.
+func ResolveSource(dep metadata.Dependency, projectRoot string, replace map[string]string) (string, SourceKind, error) {
+	if override, ok := replace[dep.Alias]; ok && dep.Alias != "" {
+		path, err := LocalSourcePath(override, projectRoot)
+		if err != nil {
+			return "", SourceReplaced, fmt.Errorf("replace for %s: %w", dep.Alias, err)
+		}
+		return path, SourceReplaced, nil
+	}
+
+	if IsLocalSource(dep.URL) {
+		path, err := LocalSourcePath(dep.URL, projectRoot)
+		return path, SourceLocal, err
+	}
+
+	path, err := CachePathForDependency(dep.Alias, dep.URL)
+	return path, SourceCache, err
+}
+
+// SourceUpstreamLoader returns an UpstreamLoader that reads upstream
+// specledger.yaml files from wherever ResolveSource finds each dependency.
+// It never clones.
+func SourceUpstreamLoader(projectRoot string, replace map[string]string) UpstreamLoader {
+	return func(dep metadata.Dependency) ([]metadata.Dependency, error) {
+		dir, _, err := ResolveSource(dep, projectRoot, replace)
+		if err != nil {
+			return nil, nil
+		}
+		return LoadUpstreamDependencies(dir)
+	}
+}
@@ -0,0 +1,94 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+func TestIsLocalSource(t *testing.T) {
+	tests := map[string]bool{
+		"../platform-specs":               true,
+		"./specs":                         true,
+		"/srv/specs":                      true,
+		"file:///srv/specs":               true,
+		"git@github.com:org/spec.git":     false,
+		"https://github.com/org/spec.git": false,
+	}
+	for url, want := range tests {
+		if got := IsLocalSource(url); got != want {
+			t.Errorf("IsLocalSource(%q) = %v, want %v", url, got, want)
+		}
+	}
+}
+
+func TestResolveSource(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "app")
+	platform := filepath.Join(root, "platform-specs")
+	checkout := filepath.Join(root, "checkout")
+	for _, dir := range []string{project, platform, checkout} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("SPECLEDGER_CACHE_DIR", filepath.Join(root, "cache"))
+
+	tests := []struct {
+		name     string
+		dep      metadata.Dependency
+		replace  map[string]string
+		wantPath string
+		wantKind SourceKind
+		wantErr  bool
+	}{
+		{
+			name:     "relative local path",
+			dep:      metadata.Dependency{URL: "../platform-specs", Alias: "platform"},
+			wantPath: platform,
+			wantKind: SourceLocal,
+		},
+		{
+			name:     "file url",
+			dep:      metadata.Dependency{URL: "file://" + platform, Alias: "platform"},
+			wantPath: platform,
+			wantKind: SourceLocal,
+		},
+		{
+			name:     "replace overrides remote",
+			dep:      metadata.Dependency{URL: "git@github.com:org/platform.git", Alias: "platform"},
+			replace:  map[string]string{"platform": "../checkout"},
+			wantPath: checkout,
+			wantKind: SourceReplaced,
+		},
+		{
+			name:     "remote uses cache",
+			dep:      metadata.Dependency{URL: "git@github.com:org/platform.git", Alias: "platform"},
+			wantPath: filepath.Join(root, "cache", "platform"),
+			wantKind: SourceCache,
+		},
+		{
+			name:     "missing local path",
+			dep:      metadata.Dependency{URL: "../missing", Alias: "missing"},
+			wantKind: SourceLocal,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, kind, err := ResolveSource(tt.dep, project, tt.replace)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if kind != tt.wantKind {
+				t.Errorf("ResolveSource() kind = %s, want %s", kind, tt.wantKind)
+			}
+			if !tt.wantErr && path != tt.wantPath {
+				t.Errorf("ResolveSource() path = %s, want %s", path, tt.wantPath)
+			}
+		})
+	}
+}
@@ -48,7 +48,7 @@ func DetectArtifactPathFromSpecLedgerRepo(repoPath string) (string, error) {
 	return artifactPath, nil
 }
 
-// DetectArtifactPathFromRemote clones a repository (shallow clone for speed),
+// DetectArtifactPathFromRemote clones a repository (sparse or shallow clone for speed),
 // reads its specledger.yaml, and returns the artifact_path value.
 //
 // This is useful for detecting artifact_path before adding a dependency.
@@ -62,15 +62,21 @@ func DetectArtifactPathFromSpecLedgerRepo(repoPath string) (string, error) {
 //   - artifact_path value from specledger.yaml
 //   - error if clone fails, not a SpecLedger repo, or artifact_path missing
 func DetectArtifactPathFromRemote(repoURL, branch, cacheDir string) (string, error) {
-	// Clone the repository using go-git
+	// Only specledger.yaml is needed, so try a sparse clone first and fall
+	// back to a shallow clone when the git CLI is unavailable.
 	cloneOpts := CloneOptions{
-		URL:       repoURL,
-		Branch:    branch,
-		TargetDir: cacheDir,
-		Shallow:   true,
+		URL:         repoURL,
+		Branch:      branch,
+		TargetDir:   cacheDir,
+		Shallow:     true,
+		SparsePaths: []string{metadata.DefaultMetadataFile},
 	}
 
 	_, _, err := Clone(cloneOpts)
+	if errors.Is(err, ErrGitCLIUnavailable) {
+		cloneOpts.SparsePaths = nil
+		_, _, err = Clone(cloneOpts)
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to clone repository: %w", err)
 	}
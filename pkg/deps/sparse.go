@@ -0,0 +1,145 @@
+package deps
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/plumbing/format/config"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// ErrGitCLIUnavailable is returned when a sparse operation is requested but
+// the git executable is not on PATH. go-git supports neither partial clone
+// filters nor persistent sparse checkout, so sparse caches need the git CLI.
+var ErrGitCLIUnavailable = errors.New("sparse checkout requires the git executable on PATH")
+
+// SparsePathsForDependency returns the paths a sparse cache of a dependency
+// needs: its artifact directory and the upstream specledger.yaml (used for
+// artifact_path detection and transitive dependency checks).
+// Directory paths end with "/".
+func SparsePathsForDependency(artifactPath string) []string {
+	paths := []string{metadata.DefaultMetadataFile}
+	if dir := cleanRepoPath(artifactPath); dir != "" {
+		paths = append(paths, dir+"/")
+	}
+	return paths
+}
+
+// IsSparse reports whether a repository was cloned with a sparse checkout.
+// Recent git versions record the setting in .git/config.worktree, which
+// go-git does not read, so that file is checked as well.
+func IsSparse(repo *git.Repository) bool {
+	if cfg, err := repo.Config(); err == nil && cfg.Raw.Section("core").Option("sparseCheckout") == "true" {
+		return true
+	}
+
+	dir := repoDir(repo)
+	if dir == "" {
+		return false
+	}
+	f, err := os.Open(filepath.Join(dir, ".git", "config.worktree"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	raw := gitconfig.New()
+	if err := gitconfig.NewDecoder(f).Decode(raw); err != nil {
+		return false
+	}
+	return raw.Section("core").Option("sparseCheckout") == "true"
+}
+
+// sparseClone clones only the objects needed for opts.SparsePaths using a
+// blob-less partial clone, then restricts the worktree to those paths.
+func sparseClone(opts CloneOptions, branch string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return ErrGitCLIUnavailable
+	}
+
+	args := []string{"clone", "--filter=blob:none", "--no-checkout", "--single-branch", "--branch", branch}
+	if opts.Shallow {
+		args = append(args, "--depth=1")
+	}
+	args = append(args, opts.URL, opts.TargetDir)
+	if err := runGit("", args...); err != nil {
+		_ = os.RemoveAll(opts.TargetDir)
+		return err
+	}
+
+	setArgs := append([]string{"sparse-checkout", "set", "--no-cone"}, sparsePatterns(opts.SparsePaths)...)
+	if err := runGit(opts.TargetDir, setArgs...); err != nil {
+		_ = os.RemoveAll(opts.TargetDir)
+		return err
+	}
+
+	if err := runGit(opts.TargetDir, "checkout", branch); err != nil {
+		_ = os.RemoveAll(opts.TargetDir)
+		return err
+	}
+	return nil
+}
+
+// sparseFetch fetches from origin without downloading blobs outside the sparse set.
+func sparseFetch(dir string) error {
+	return runGit(dir, "fetch", "--filter=blob:none", "origin")
+}
+
+// sparseCheckout checks out a commit or branch in a sparse repository.
+// Missing blobs inside the sparse set are fetched on demand by git.
+func sparseCheckout(dir, ref string) error {
+	return runGit(dir, "checkout", "--force", ref)
+}
+
+// sparsePatterns converts paths to non-cone sparse-checkout patterns anchored
+// at the repository root. Paths ending in "/" match whole directories.
+func sparsePatterns(paths []string) []string {
+	patterns := make([]string, 0, len(paths))
+	for _, p := range paths {
+		clean := cleanRepoPath(p)
+		if clean == "" {
+			continue
+		}
+		if strings.HasSuffix(p, "/") {
+			patterns = append(patterns, "/"+clean+"/")
+		} else {
+			patterns = append(patterns, "/"+clean)
+		}
+	}
+	return patterns
+}
+
+// cleanRepoPath normalizes a repository-relative path: forward slashes, no
+// leading or trailing slash, "" for the repository root.
+func cleanRepoPath(p string) string {
+	return strings.Trim(path.Clean("/"+strings.ReplaceAll(p, "\\", "/")), "/")
+}
+
+// repoDir returns the worktree root of repo, or "" if it has no worktree.
+func repoDir(repo *git.Repository) string {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return ""
+	}
+	return wt.Filesystem.Root()
+}
+
+// runGit runs the git CLI in dir (or the current directory when dir is empty).
+func runGit(dir string, args ...string) error {
+	name := args[0]
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	// #nosec G204 -- arguments are built from dependency metadata, not shell-interpreted
+	cmd := exec.Command("git", args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s failed: %w\nOutput: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
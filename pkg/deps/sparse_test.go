@@ -0,0 +1,75 @@
+package deps
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+)
+
+func TestSparsePatterns(t *testing.T) {
+	got := sparsePatterns([]string{"specledger/specledger.yaml", "specs/", "./docs/api/", ""})
+	want := []string{"/specledger/specledger.yaml", "/specs/", "/docs/api/"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sparsePatterns() = %v, want %v", got, want)
+	}
+}
+
+func TestSparsePathsForDependency(t *testing.T) {
+	got := SparsePathsForDependency("specs")
+	want := []string{"specledger/specledger.yaml", "specs/"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SparsePathsForDependency() = %v, want %v", got, want)
+	}
+}
+
+func TestCloneSparse(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git CLI not available")
+	}
+
+	src := t.TempDir()
+	repo, err := git.PlainInit(src, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := commitFiles(t, repo, src, map[string][]byte{
+		"specledger/specledger.yaml": []byte("version: 1.0.0\n"),
+		"specs/001-auth/spec.md":     []byte("# Auth\n"),
+		"app/large.bin":              []byte("not needed\n"),
+	}, "initial")
+
+	ref, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := filepath.Join(t.TempDir(), "cache")
+	cloned, sha, err := Clone(CloneOptions{
+		URL:         "file://" + src,
+		Branch:      ref.Name().Short(),
+		TargetDir:   target,
+		SparsePaths: SparsePathsForDependency("specs/"),
+	})
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	if sha != head {
+		t.Errorf("Clone() sha = %s, want %s", sha, head)
+	}
+	if !IsSparse(cloned) {
+		t.Error("expected cloned repository to be sparse")
+	}
+
+	for _, p := range []string{"specledger/specledger.yaml", "specs/001-auth/spec.md"} {
+		if _, err := os.Stat(filepath.Join(target, p)); err != nil {
+			t.Errorf("expected %s in sparse checkout: %v", p, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(target, "app", "large.bin")); !os.IsNotExist(err) {
+		t.Error("files outside the sparse set must not be checked out")
+	}
+}
@@ -0,0 +1,201 @@
+package deps
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultVendorDir is the vendor directory relative to the project's artifact_path.
+	DefaultVendorDir = "vendor"
+
+	// VendorManifestFile records which commit each vendored dependency was copied from.
+	VendorManifestFile = "vendor.yaml"
+)
+
+// VendorManifest describes the contents of a vendor directory.
+type VendorManifest struct {
+	Dependencies []VendoredDependency `yaml:"dependencies"`
+}
+
+// VendoredDependency records a single dependency copied into the vendor directory.
+type VendoredDependency struct {
+	Alias        string    `yaml:"alias"`
+	URL          string    `yaml:"url"`
+	Branch       string    `yaml:"branch,omitempty"`
+	ArtifactPath string    `yaml:"artifact_path"`
+	Commit       string    `yaml:"commit"`
+	VendoredAt   time.Time `yaml:"vendored_at"`
+}
+
+// VendorDir returns the vendor directory for a project.
+func VendorDir(projectRoot, projectArtifactPath string) string {
+	return filepath.Join(projectRoot, projectArtifactPath, DefaultVendorDir)
+}
+
+// LoadVendorManifest reads vendor.yaml from vendorDir.
+// Returns an empty manifest if the file does not exist.
+func LoadVendorManifest(vendorDir string) (*VendorManifest, error) {
+	data, err := os.ReadFile(filepath.Join(vendorDir, VendorManifestFile))
+	if os.IsNotExist(err) {
+		return &VendorManifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vendor manifest: %w", err)
+	}
+
+	var manifest VendorManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse vendor manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// Save writes the manifest to vendorDir/vendor.yaml, sorted by alias.
+func (m *VendorManifest) Save(vendorDir string) error {
+	sort.Slice(m.Dependencies, func(i, j int) bool {
+		return m.Dependencies[i].Alias < m.Dependencies[j].Alias
+	})
+
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vendor manifest: %w", err)
+	}
+	if err := os.MkdirAll(vendorDir, 0755); err != nil {
+		return fmt.Errorf("failed to create vendor directory: %w", err)
+	}
+	// #nosec G306 -- vendor manifest is committed to the repo, 0644 is appropriate
+	return os.WriteFile(filepath.Join(vendorDir, VendorManifestFile), data, 0644)
+}
+
+// Lookup returns the vendored entry for alias, or nil if not vendored.
+func (m *VendorManifest) Lookup(alias string) *VendoredDependency {
+	for i := range m.Dependencies {
+		if m.Dependencies[i].Alias == alias {
+			return &m.Dependencies[i]
+		}
+	}
+	return nil
+}
+
+// Set adds or replaces the entry for entry.Alias.
+func (m *VendorManifest) Set(entry VendoredDependency) {
+	if existing := m.Lookup(entry.Alias); existing != nil {
+		*existing = entry
+		return
+	}
+	m.Dependencies = append(m.Dependencies, entry)
+}
+
+// Remove deletes the entry for alias. Returns false if it was not present.
+func (m *VendorManifest) Remove(alias string) bool {
+	for i := range m.Dependencies {
+		if m.Dependencies[i].Alias == alias {
+			m.Dependencies = append(m.Dependencies[:i], m.Dependencies[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// VendoredPath returns the directory holding a vendored dependency's artifacts
+// if it was vendored at the given commit, or "" otherwise.
+func VendoredPath(vendorDir string, dep metadata.Dependency) string {
+	if dep.Alias == "" || dep.ResolvedCommit == "" {
+		return ""
+	}
+	manifest, err := LoadVendorManifest(vendorDir)
+	if err != nil {
+		return ""
+	}
+	entry := manifest.Lookup(dep.Alias)
+	if entry == nil || entry.Commit != dep.ResolvedCommit {
+		return ""
+	}
+	path := filepath.Join(vendorDir, dep.Alias)
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return ""
+	}
+	return path
+}
+
+// VendorDependency copies the artifact directory of a cached dependency into
+// vendorDir/<alias>, replacing any previous copy. Only the contents of the
+// dependency's artifact_path are copied; the .git directory is never included.
+func VendorDependency(cachePath, vendorDir string, dep metadata.Dependency) error {
+	if dep.Alias == "" {
+		return fmt.Errorf("dependency %s has no alias", dep.URL)
+	}
+	if dep.ArtifactPath == "" {
+		return fmt.Errorf("dependency %s has no artifact_path", dep.Alias)
+	}
+
+	source := filepath.Join(cachePath, dep.ArtifactPath)
+	if info, err := os.Stat(source); err != nil || !info.IsDir() {
+		return fmt.Errorf("artifact path not found in cache: %s", source)
+	}
+
+	target := filepath.Join(vendorDir, dep.Alias)
+	if err := os.RemoveAll(target); err != nil {
+		return fmt.Errorf("failed to remove previous vendor copy: %w", err)
+	}
+
+	return copyTree(source, target)
+}
+
+// copyTree recursively copies src to dst, skipping .git directories and symlinks.
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case info.IsDir():
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0755)
+		case info.Mode()&os.ModeSymlink != 0:
+			return nil
+		default:
+			return copyRegularFile(path, target)
+		}
+	})
+}
+
+// copyRegularFile copies a single file, preserving its permission bits.
+func copyRegularFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,71 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+func TestVendorDependency(t *testing.T) {
+	cache := t.TempDir()
+	vendorDir := filepath.Join(t.TempDir(), "vendor")
+
+	files := map[string]string{
+		"specs/001-auth/spec.md": "# Auth\n",
+		"specs/001-auth/plan.md": "# Plan\n",
+		"README.md":              "outside artifact path\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(cache, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dep := metadata.Dependency{
+		URL:            "https://github.com/org/platform",
+		Alias:          "platform",
+		ArtifactPath:   "specs/",
+		ResolvedCommit: "0123456789abcdef0123456789abcdef01234567",
+	}
+
+	if err := VendorDependency(cache, vendorDir, dep); err != nil {
+		t.Fatalf("VendorDependency() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(vendorDir, "platform", "001-auth", "spec.md")); err != nil {
+		t.Errorf("expected vendored spec.md: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(vendorDir, "platform", "README.md")); !os.IsNotExist(err) {
+		t.Errorf("files outside artifact_path must not be vendored")
+	}
+
+	// Not recorded in the manifest yet
+	if got := VendoredPath(vendorDir, dep); got != "" {
+		t.Errorf("VendoredPath() = %q before manifest save, want empty", got)
+	}
+
+	manifest, err := LoadVendorManifest(vendorDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest.Set(VendoredDependency{Alias: dep.Alias, URL: dep.URL, ArtifactPath: dep.ArtifactPath, Commit: dep.ResolvedCommit})
+	if err := manifest.Save(vendorDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := VendoredPath(vendorDir, dep); got != filepath.Join(vendorDir, "platform") {
+		t.Errorf("VendoredPath() = %q, want vendored dir", got)
+	}
+
+	// A different pinned commit must not use the stale vendored copy
+	dep.ResolvedCommit = "fedcba9876543210fedcba9876543210fedcba98"
+	if got := VendoredPath(vendorDir, dep); got != "" {
+		t.Errorf("VendoredPath() = %q for mismatched commit, want empty", got)
+	}
+}